@@ -0,0 +1,254 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DropPolicy defines the behavior of the asynchronous mode when its buffer is full
+type DropPolicy int
+
+const (
+	// BlockWhenFull blocks the logging until the buffer has room (default)
+	BlockWhenFull DropPolicy = iota
+	// DropOldest discards the oldest queued record to queue the new one
+	DropOldest
+	// DropNewest discards the new record
+	DropNewest
+)
+
+// dropPolicyNames are the names of the drop policies in the configuration files
+var dropPolicyNames = map[DropPolicy]string{
+	BlockWhenFull: "block",
+	DropOldest:    "drop_oldest",
+	DropNewest:    "drop_newest",
+}
+
+// String returns the name of the drop policy
+func (p DropPolicy) String() string {
+	if name, ok := dropPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("DropPolicy(%d)", int(p))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (p DropPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (p *DropPolicy) UnmarshalText(text []byte) error {
+	for policy, name := range dropPolicyNames {
+		if strings.EqualFold(string(text), name) {
+			*p = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown drop policy %q", text)
+}
+
+// asyncEntry is a record waiting to be written by the background worker
+type asyncEntry struct {
+	ctx    context.Context
+	record slog.Record
+
+	// flushed is closed by the worker when reached, if not nil
+	flushed chan struct{}
+}
+
+// asyncEmitter writes the records to the handler from a background worker
+type asyncEmitter struct {
+	handler   slog.Handler
+	policy    DropPolicy
+	dropLevel slog.Level
+	keyCase   KeyCase
+	queue     chan asyncEntry
+	done      chan struct{}
+
+	// dropped counts the records discarded because the buffer was full,
+	// reported the ones already logged by a summary record.
+	dropped  atomic.Uint64
+	reported uint64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newAsyncEmitter(handler slog.Handler, size int, policy DropPolicy, dropLevel slog.Level, keyCase KeyCase) *asyncEmitter {
+	e := &asyncEmitter{
+		handler:   handler,
+		policy:    policy,
+		dropLevel: dropLevel,
+		keyCase:   keyCase,
+		queue:     make(chan asyncEntry, size),
+		done:      make(chan struct{}),
+	}
+	go e.run()
+
+	return e
+}
+
+// run writes the queued records until the queue is closed
+func (e *asyncEmitter) run() {
+	defer close(e.done)
+
+	for entry := range e.queue {
+		if entry.flushed != nil {
+			e.reportDropped()
+			close(entry.flushed)
+			continue
+		}
+		_ = e.handler.Handle(entry.ctx, entry.record)
+
+		if len(e.queue) == 0 {
+			e.reportDropped()
+		}
+	}
+	e.reportDropped()
+}
+
+// reportDropped logs the number of records dropped since the last report, if any
+func (e *asyncEmitter) reportDropped() {
+	total := e.dropped.Load()
+	if total == e.reported {
+		return
+	}
+
+	dropped := total - e.reported
+	e.reported = total
+	if !e.handler.Enabled(context.Background(), e.dropLevel) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), e.dropLevel, AsyncDropMessage, 0)
+	r.AddAttrs(
+		slog.Uint64(e.keyCase.convert(DroppedField), dropped),
+		slog.Uint64(e.keyCase.convert(DroppedTotalField), total),
+	)
+	_ = e.handler.Handle(context.Background(), r)
+}
+
+// emit queues the record, or writes it synchronously once the emitter is closed
+func (e *asyncEmitter) emit(ctx context.Context, r slog.Record) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		_ = e.handler.Handle(ctx, r)
+		return
+	}
+	// The records dropped because the queue is full are not resolved
+	if e.policy == DropNewest && len(e.queue) == cap(e.queue) {
+		e.dropped.Add(1)
+		return
+	}
+	entry := asyncEntry{ctx: ctx, record: resolveRecord(r)}
+	switch e.policy {
+	case DropNewest:
+		select {
+		case e.queue <- entry:
+		default:
+			e.dropped.Add(1)
+		}
+
+	case DropOldest:
+		for {
+			select {
+			case e.queue <- entry:
+				return
+			default:
+			}
+
+			select {
+			case oldest := <-e.queue:
+				if oldest.flushed != nil {
+					// The records queued before the flush are already written
+					close(oldest.flushed)
+				} else {
+					e.dropped.Add(1)
+				}
+			default:
+			}
+		}
+
+	default:
+		e.queue <- entry
+	}
+}
+
+// flush waits until the records queued before the call are written
+func (e *asyncEmitter) flush() {
+	e.mu.RLock()
+	if e.closed {
+		e.mu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	e.queue <- asyncEntry{flushed: flushed}
+	e.mu.RUnlock()
+
+	<-flushed
+}
+
+// close writes the queued records and stops the background worker
+func (e *asyncEmitter) close() {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.mu.Unlock()
+
+	<-e.done
+}
+
+// resolveRecord returns a copy of the record whose attribute values are resolved,
+// as the lazy values of Trace cannot be resolved once it returns.
+func resolveRecord(r slog.Record) slog.Record {
+	resolved := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		resolved.AddAttrs(resolveAttr(attr))
+		return true
+	})
+	return resolved
+}
+
+// resolveAttr resolves the value of the attribute, including the attributes of groups
+func resolveAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = resolveAttr(a)
+		}
+		attr.Value = slog.GroupValue(attrs...)
+	}
+	return attr
+}
+
+// Flush waits until the records queued by the asynchronous mode are written.
+// It does nothing if the asynchronous mode is disabled.
+func (l logger) Flush() {
+	l = l.snapshot()
+	if l.async != nil {
+		l.async.flush()
+	}
+}
+
+// Close writes the records queued by the asynchronous mode and stops its background worker.
+// The records logged afterward are written synchronously. It does nothing if the
+// asynchronous mode is disabled.
+func (l logger) Close() error {
+	l = l.snapshot()
+	if l.async != nil {
+		l.async.close()
+	}
+	return nil
+}
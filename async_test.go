@@ -0,0 +1,166 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Async(t *testing.T) {
+	receiver := NewDummyHandler()
+	l := New(
+		WithHandler(receiver),
+		WithAsync(10),
+		WithTraceAll(),
+	).(*logger)
+
+	sql := "SELECT * FROM user"
+	fc := func() (string, int64) {
+		return sql, 1
+	}
+
+	for i := 0; i < 5; i++ {
+		l.Trace(context.Background(), time.Now(), fc, nil)
+	}
+	// The SQL query must be resolved when queued, as gorm reuses its statement
+	sql = "reused statement"
+
+	l.Flush()
+	require.Equal(t, 5, receiver.Len())
+	for _, record := range receiver.Records {
+		assertHasAttr(t, &record, slog.String(QueryField, "SELECT * FROM user"))
+		assertHasAttr(t, &record, slog.Int64(RowsField, 1))
+	}
+
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close())
+
+	// Written synchronously once closed
+	l.Info(context.Background(), "an info message")
+	assert.Equal(t, 6, receiver.Len())
+	assert.Equal(t, "an info message", receiver.Record.Message)
+
+	// Flush does nothing once closed
+	l.Flush()
+}
+
+func Test_logger_Async_Disabled(t *testing.T) {
+	receiver, l := getReceiverAndLogger(nil)
+
+	l.Info(context.Background(), "an info message")
+	l.Flush()
+	assert.NoError(t, l.Close())
+	assert.Equal(t, 1, receiver.Len())
+}
+
+func Test_logger_AsyncDropPolicy(t *testing.T) {
+	tests := []struct {
+		policy       DropPolicy
+		wantMessages []string
+	}{
+		{policy: DropNewest, wantMessages: []string{"1", "2", "3"}},
+		{policy: DropOldest, wantMessages: []string{"1", "4", "5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.policy), func(t *testing.T) {
+			receiver := newBlockingHandler()
+			l := New(
+				WithHandler(receiver),
+				WithAsync(2),
+				WithAsyncDropPolicy(tt.policy),
+			).(*logger)
+
+			// The first record blocks the worker, the next ones fill the buffer
+			l.Info(context.Background(), "1")
+			<-receiver.entered
+			for _, msg := range []string{"2", "3", "4", "5"} {
+				l.Info(context.Background(), msg)
+			}
+			assert.Equal(t, uint64(2), l.Stats().Dropped)
+
+			close(receiver.release)
+			l.Flush()
+
+			require.Equal(t, len(tt.wantMessages)+1, receiver.Len())
+			for i, msg := range tt.wantMessages {
+				assert.Equal(t, msg, receiver.Records[i].Message)
+			}
+
+			summary := receiver.Records[len(tt.wantMessages)]
+			assert.Equal(t, slog.LevelWarn, summary.Level)
+			assertHasAttr(t, &summary, slog.Uint64(DroppedField, 2))
+			assertHasAttr(t, &summary, slog.Uint64(DroppedTotalField, 2))
+			require.NoError(t, l.Close())
+		})
+	}
+}
+
+func Test_logger_Stats_Dropped(t *testing.T) {
+	_, l := getReceiverAndLogger(nil)
+
+	assert.Equal(t, uint64(0), l.Stats().Dropped)
+}
+
+func Test_resolveRecord(t *testing.T) {
+	query := newLazyQuery(func() (string, int64) {
+		return "SELECT 1", 2
+	})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	r.AddAttrs(
+		slog.Any(QueryField, sqlValuer{query}),
+		slog.Group("group", slog.Any(RowsField, rowsValuer{query})),
+	)
+
+	resolved := resolveRecord(r)
+	assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT 1"))
+	assertHasAttr(t, &resolved, slog.Group("group", slog.Int64(RowsField, 2)))
+}
+
+// Mock
+
+// blockingHandler blocks the first record until release is closed
+type blockingHandler struct {
+	*DummyHandler
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{
+		DummyHandler: NewDummyHandler(),
+		entered:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.once.Do(func() {
+		close(h.entered)
+		<-h.release
+	})
+	return h.DummyHandler.Handle(ctx, r)
+}
+
+func TestDropPolicy_Text(t *testing.T) {
+	for _, policy := range []DropPolicy{BlockWhenFull, DropOldest, DropNewest} {
+		text, err := policy.MarshalText()
+		require.NoError(t, err)
+
+		var actual DropPolicy
+		require.NoError(t, actual.UnmarshalText(text))
+		assert.Equal(t, policy, actual)
+	}
+
+	assert.Equal(t, "drop_oldest", DropOldest.String())
+	assert.Equal(t, "DropPolicy(42)", DropPolicy(42).String())
+	assert.Error(t, new(DropPolicy).UnmarshalText([]byte("unknown")))
+}
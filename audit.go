@@ -0,0 +1,166 @@
+package slogGorm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditedOperations are the operations of the statements modifying the data, logged by WithAudit
+var auditedOperations = map[string]struct{}{
+	"INSERT": {},
+	"UPDATE": {},
+	"DELETE": {},
+}
+
+// audit logs the statement with the audit handler if it modifies the data. The record is handled
+// synchronously, so that it is never dropped by the asynchronous mode.
+func (l logger) audit(ctx context.Context, elapsed time.Duration, query *lazyQuery, source *sourceValuer, txIndex int64, err error) {
+	if _, ok := auditedOperations[query.Operation()]; !ok {
+		return
+	}
+	level := l.logLevel[AuditLogType]
+	if l.silenced(AuditLogType) || !l.auditHandler.Enabled(ctx, level) {
+		return
+	}
+	if l.noValues {
+		err = withoutValues(err)
+	}
+
+	// The audit record has its own query, as the scrubber replaces the SQL query of the query logged
+	audited := newLazyQuery(func() (string, int64) {
+		return l.sanitizeSQL(query.SQL()), query.Rows()
+	})
+	audited.operation, audited.parsedOperation = query.Operation(), true
+
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+	*attributes = append(*attributes,
+		slog.String(OperationField, audited.Operation()),
+		slog.String(TableField, audited.Table()),
+		slog.Int64(RowsField, audited.Rows()),
+	)
+	if l.auditActor != nil {
+		if actor, ok := l.auditActor(ctx); ok {
+			*attributes = append(*attributes, slog.Attr{Key: ActorField, Value: actor})
+		}
+	}
+	*attributes = append(*attributes,
+		slog.Any(QueryField, sqlValuer{audited}),
+		slog.Duration(DurationField, elapsed),
+	)
+	if err != nil {
+		*attributes = append(*attributes, slog.Any(l.errorField, err))
+	}
+	*attributes = l.appendSourceAttribute(*attributes, source)
+	*attributes = appendTransactionAttributes(txIndex, *attributes)
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(audited, *attributes)
+	l.verifyRedaction(ctx, AuditLogType, audited)
+
+	event := messageEvent{
+		logType: AuditLogType,
+		query:   audited,
+		elapsed: elapsed,
+		err:     err,
+	}
+	msg, custom := l.formatMessage(ctx, event)
+	if !custom {
+		msg = l.defaultMessage(ctx, event)
+	}
+
+	// Properly handle the PC for the caller
+	var pcs [1]uintptr
+	// skip [runtime.Callers, this function, this function's caller]
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(l.clock.Now(), level, msg, pcs[0])
+	r.AddAttrs(l.convertKeys(l.applyPreset(ctx, AuditLogType, level, audited, *attributes))...)
+
+	if l.auditChain != nil {
+		_ = l.auditChain.handle(ctx, l.auditHandler, r, l.dropKeyCase())
+		return
+	}
+	_ = l.auditHandler.Handle(ctx, r)
+}
+
+// auditChain chains the audit records with a rolling hash, see WithAuditHashChain
+type auditChain struct {
+	mu   sync.Mutex
+	seq  int64
+	hash string
+}
+
+// handle adds the sequence number and the hash of the record chained to the previous record, and writes it
+// with the handler. The records are handled in the order of the chain.
+func (c *auditChain) handle(ctx context.Context, handler slog.Handler, r slog.Record, keyCase KeyCase) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	r.AddAttrs(slog.Int64(keyCase.convert(AuditSeqField), c.seq))
+	c.hash = AuditHash(c.hash, r)
+	r.AddAttrs(slog.String(keyCase.convert(AuditHashField), c.hash))
+	return handler.Handle(ctx, r)
+}
+
+// AuditHash returns the hash chaining the audit record to the previous one, whose hash is given (empty for
+// the first record), to verify the records logged with WithAuditHashChain. It is the hexadecimal SHA-256 of
+// the lines: the previous hash, the time of the record in UTC (RFC 3339 with nanoseconds), its level, its
+// message quoted with strconv.Quote, then a "key"="value" line per attribute, except the hash, the key and the
+// value formatted by slog.Value.String once resolved being quoted, so that the attributes cannot be merged or
+// split. The keys of the groups are prefixed by the quoted key of the group and a dot, e.g. "user"."id"="42".
+func AuditHash(previous string, r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(previous)
+	b.WriteByte('\n')
+	b.WriteString(r.Time.UTC().Format(time.RFC3339Nano))
+	b.WriteByte('\n')
+	b.WriteString(r.Level.String())
+	b.WriteByte('\n')
+	b.WriteString(strconv.Quote(r.Message))
+	r.Attrs(func(attr slog.Attr) bool {
+		writeAuditAttr(&b, "", attr)
+		return true
+	})
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeAuditAttr writes the lines of the attribute hashed by AuditHash
+func writeAuditAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += strconv.Quote(attr.Key) + "."
+		}
+		for _, a := range value.Group() {
+			writeAuditAttr(b, prefix, a)
+		}
+		return
+	}
+	if prefix == "" && isAuditHashKey(attr.Key) {
+		return
+	}
+	b.WriteByte('\n')
+	b.WriteString(prefix)
+	b.WriteString(strconv.Quote(attr.Key))
+	b.WriteByte('=')
+	b.WriteString(strconv.Quote(value.String()))
+}
+
+// isAuditHashKey reports whether the key is the key of the hash of the audit records, in any key casing convention
+func isAuditHashKey(key string) bool {
+	for keyCase := range keyCaseNames {
+		if key == keyCase.convert(AuditHashField) {
+			return true
+		}
+	}
+	return false
+}
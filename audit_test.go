@@ -0,0 +1,187 @@
+package slogGorm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Audit(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user", "john")
+	actor := func(ctx context.Context) (slog.Value, bool) {
+		user, ok := ctx.Value("user").(string)
+		return slog.StringValue(user), ok
+	}
+	trace := func(l *logger, sql string, err error) {
+		l.Trace(ctx, time.Now(), func() (string, int64) { return sql, 2 }, err)
+	}
+
+	t.Run("Statement modifying the data", func(t *testing.T) {
+		auditor := NewDummyHandler()
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithAudit(auditor, actor)})
+
+		trace(gormLogger, "UPDATE users SET name = 'john' WHERE id = 1", nil)
+
+		assert.Nil(t, receiver.Record)
+		require.Equal(t, 1, auditor.Len())
+		assert.Equal(t, slog.LevelInfo, auditor.Record.Level)
+		assert.Equal(t, "UPDATE on users", auditor.Record.Message)
+		assertHasAttr(t, auditor.Record, slog.String(OperationField, "UPDATE"))
+		assertHasAttr(t, auditor.Record, slog.String(TableField, "users"))
+		assertHasAttr(t, auditor.Record, slog.Int64(RowsField, 2))
+		assertHasAttr(t, auditor.Record, slog.String(ActorField, "john"))
+		resolved := resolveRecord(*auditor.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "UPDATE users SET name = 'john' WHERE id = 1"))
+		assert.Contains(t, sourceOf(resolved), "audit_test.go:")
+		assertNoAttr(t, auditor.Record, ErrorField)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		auditor := NewDummyHandler()
+		_, gormLogger := getReceiverAndLogger([]Option{WithAudit(auditor, actor)})
+
+		trace(gormLogger, "SELECT * FROM users", nil)
+
+		assert.Equal(t, 0, auditor.Len())
+	})
+
+	t.Run("Whatever the tracing", func(t *testing.T) {
+		auditor := NewDummyHandler()
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithAudit(auditor, nil),
+			WithIgnoreTrace(),
+			WithMinLevel(slog.LevelError),
+			WithIgnoredOperations("DELETE"),
+			SetLogLevel(AuditLogType, slog.LevelWarn),
+		})
+
+		err := errors.New("failed")
+		trace(gormLogger, "DELETE FROM sessions WHERE id = 1", err)
+
+		assert.Nil(t, receiver.Record)
+		require.Equal(t, 1, auditor.Len())
+		assert.Equal(t, slog.LevelWarn, auditor.Record.Level)
+		assertHasAttr(t, auditor.Record, slog.Any(ErrorField, err))
+		assertNoAttr(t, auditor.Record, ActorField)
+	})
+
+	t.Run("Traced", func(t *testing.T) {
+		auditor := NewDummyHandler()
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithAudit(auditor, nil), WithTraceAll()})
+
+		trace(gormLogger, "INSERT INTO users (name) VALUES ('john')", nil)
+
+		require.Equal(t, 1, auditor.Len())
+		require.Equal(t, 1, receiver.Len())
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "INSERT INTO users (name) VALUES ('john')"))
+	})
+
+	t.Run("No values", func(t *testing.T) {
+		auditor := NewDummyHandler()
+		_, gormLogger := getReceiverAndLogger([]Option{WithAudit(auditor, actor), WithNoValues()})
+
+		trace(gormLogger, "INSERT INTO users (name) VALUES ('john')", errors.New(`duplicate "john"`))
+
+		require.Equal(t, 1, auditor.Len())
+		resolved := resolveRecord(*auditor.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "INSERT INTO users (name) VALUES (?)"))
+		assertHasAttr(t, &resolved, slog.Any(ErrorField, noValuesError{msg: "*errors.errorString", typ: "*errors.errorString"}))
+	})
+
+	t.Run("Custom message", func(t *testing.T) {
+		auditor := NewDummyHandler()
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithAudit(auditor, nil),
+			WithMessage(AuditLogType, "{rows} rows modified in {table}"),
+		})
+
+		trace(gormLogger, "DELETE FROM sessions", nil)
+
+		require.Equal(t, 1, auditor.Len())
+		assert.Equal(t, "2 rows modified in sessions", auditor.Record.Message)
+	})
+}
+
+func Test_logger_AuditHashChain(t *testing.T) {
+	auditor := NewDummyHandler()
+	_, gormLogger := getReceiverAndLogger([]Option{WithAudit(auditor, nil), WithAuditHashChain()})
+	derived := gormLogger.with(WithKeyCase(CamelCase))
+
+	for _, l := range []*logger{gormLogger, derived, gormLogger} {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "DELETE FROM sessions WHERE id = 1", 1
+		}, nil)
+	}
+
+	require.Equal(t, 3, auditor.Len())
+	var previous string
+	for i, r := range auditor.Records {
+		seqKey, hashKey := AuditSeqField, AuditHashField
+		if i == 1 {
+			seqKey, hashKey = "auditSeq", "auditHash"
+		}
+		assertHasAttr(t, &r, slog.Int64(seqKey, int64(i+1)))
+
+		hash := AuditHash(previous, r)
+		assertHasAttr(t, &r, slog.String(hashKey, hash))
+		previous = hash
+	}
+
+	// An altered record breaks the chain
+	previous = AuditHash("", auditor.Records[0])
+	altered := auditor.Records[1].Clone()
+	altered.Message = "DELETE on users"
+	assert.NotEqual(t, AuditHash(previous, auditor.Records[1]), AuditHash(previous, altered))
+}
+
+func TestAuditHash(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	r := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions", 0)
+	r.AddAttrs(slog.String(TableField, "sessions"), slog.Group("user", slog.Int("id", 42)))
+
+	expected := sha256.Sum256([]byte("previous\n2024-01-02T03:04:05.000000006Z\nINFO\n\"DELETE on sessions\"\n\"table\"=\"sessions\"\n\"user\".\"id\"=\"42\""))
+	assert.Equal(t, hex.EncodeToString(expected[:]), AuditHash("previous", r))
+
+	// The hash of the record is not hashed
+	r.AddAttrs(slog.String(AuditHashField, "hash"))
+	assert.Equal(t, hex.EncodeToString(expected[:]), AuditHash("previous", r))
+
+	t.Run("Attributes merged or split", func(t *testing.T) {
+		merged := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions", 0)
+		merged.AddAttrs(slog.String(ActorField, "bob\nrows=0"))
+		split := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions", 0)
+		split.AddAttrs(slog.String(ActorField, "bob"), slog.String(RowsField, "0"))
+		assert.NotEqual(t, AuditHash("previous", merged), AuditHash("previous", split))
+
+		inMessage := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions\nactor=bob", 0)
+		asAttr := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions", 0)
+		asAttr.AddAttrs(slog.String(ActorField, "bob"))
+		assert.NotEqual(t, AuditHash("previous", inMessage), AuditHash("previous", asAttr))
+
+		grouped := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions", 0)
+		grouped.AddAttrs(slog.Group("user", slog.Int("id", 42)))
+		dotted := slog.NewRecord(now, slog.LevelInfo, "DELETE on sessions", 0)
+		dotted.AddAttrs(slog.Int("user.id", 42))
+		assert.NotEqual(t, AuditHash("previous", grouped), AuditHash("previous", dotted))
+	})
+}
+
+// sourceOf returns the source logged by the resolved record
+func sourceOf(r slog.Record) string {
+	var source string
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == SourceField {
+			source = attr.Value.String()
+		}
+		return true
+	})
+	return source
+}
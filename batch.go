@@ -0,0 +1,63 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"time"
+)
+
+// batchSize returns the number of rows inserted by the multi-row INSERT (or REPLACE) ... VALUES statement,
+// e.g. by gorm's CreateInBatches, zero for the other statements
+func batchSize(sql string) int {
+	var (
+		first  = true
+		values bool
+		depth  int
+		rows   int
+	)
+	tokenize(sql, func(t token) bool {
+		switch {
+		case t.kind == tokenSpace || t.kind == tokenComment:
+			return true
+		case first:
+			first = false
+			return t.isKeyword("INSERT") || t.isKeyword("REPLACE")
+		case t.kind == tokenPunctuation:
+			for i := 0; i < len(t.text); i++ {
+				switch t.text[i] {
+				case '(':
+					if values && depth == 0 {
+						rows++
+					}
+					depth++
+				case ')':
+					depth--
+				case ';':
+					if depth == 0 {
+						return false
+					}
+				}
+			}
+			return true
+		case depth > 0:
+			return true
+		case !values:
+			values = t.isKeyword("VALUES") || t.isKeyword("VALUE")
+			return true
+		}
+		// The end of the values, e.g. ON CONFLICT or RETURNING
+		return t.kind != tokenWord
+	})
+	return rows
+}
+
+// appendBatchAttributes adds the size of the batch inserted by the statement and the latency per row, if
+// it inserts several rows
+func appendBatchAttributes(args []slog.Attr, rows int, elapsed time.Duration) []slog.Attr {
+	if rows < 2 {
+		return args
+	}
+	return append(args,
+		slog.Int(BatchSizeField, rows),
+		slog.Duration(RowLatencyField, elapsed/time.Duration(rows)),
+	)
+}
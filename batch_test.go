@@ -0,0 +1,76 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_batchSize(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want int
+	}{
+		{sql: "INSERT INTO users (name, age) VALUES ('a', 1),('b', 2),('c', 3)", want: 3},
+		{sql: "insert into users (name) values (?), (?) ON CONFLICT (id) DO NOTHING", want: 2},
+		{sql: "INSERT INTO users (name) VALUES ('a (b)') RETURNING id", want: 1},
+		{sql: "INSERT INTO users (id, tags) VALUES (1, ARRAY['a', 'b']), (2, ARRAY['c'])", want: 2},
+		{sql: "/* load */ REPLACE INTO users VALUES (1), (2)", want: 2},
+		{sql: "INSERT INTO users (name) SELECT name FROM (VALUES ('a'), ('b')) AS v(name)", want: 0},
+		{sql: "INSERT INTO users VALUES (1); INSERT INTO users VALUES (2), (3)", want: 1},
+		{sql: "SELECT * FROM users WHERE id IN (1, 2)", want: 0},
+		{sql: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, batchSize(tt.sql))
+		})
+	}
+}
+
+func Test_logger_BatchMetrics(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now().Add(-40*time.Millisecond), func() (string, int64) { return sql, 4 }, nil)
+	}
+
+	t.Run("Batch insert", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithBatchMetrics(), WithNoValues()})
+
+		trace(gormLogger, "INSERT INTO users (name) VALUES ('a'),('b'),('c'),('d')")
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.Int(BatchSizeField, 4))
+		var latency time.Duration
+		receiver.Record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == RowLatencyField {
+				latency = attr.Value.Duration()
+			}
+			return true
+		})
+		assert.GreaterOrEqual(t, latency, 10*time.Millisecond)
+	})
+
+	t.Run("Single row", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithBatchMetrics()})
+
+		trace(gormLogger, "INSERT INTO users (name) VALUES ('a')")
+
+		require.Equal(t, 1, receiver.Len())
+		assertNoAttr(t, receiver.Record, BatchSizeField)
+		assertNoAttr(t, receiver.Record, RowLatencyField)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+
+		trace(gormLogger, "INSERT INTO users (name) VALUES ('a'),('b')")
+
+		require.Equal(t, 1, receiver.Len())
+		assertNoAttr(t, receiver.Record, BatchSizeField)
+	})
+}
@@ -0,0 +1,54 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// benchmarkFc simulates the SQL explained by gorm
+var benchmarkFc = func() (string, int64) {
+	return "SELECT * FROM users WHERE id = 1", 1
+}
+
+func Benchmark_logger_Trace(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		level   slog.Level
+		options []Option
+		err     error
+	}{
+		{name: "untraced query"},
+		{name: "trace all mode, level disabled", level: slog.LevelWarn, options: []Option{WithTraceAll()}},
+		{name: "trace all mode, level enabled", level: slog.LevelInfo, options: []Option{WithTraceAll()}},
+		{name: "slow query, level disabled", level: slog.LevelError, options: []Option{WithSlowThreshold(time.Millisecond)}},
+		{name: "slow query, level enabled", level: slog.LevelWarn, options: []Option{WithSlowThreshold(time.Millisecond)}},
+		{name: "error, level disabled", level: slog.Level(42), err: fmt.Errorf("awesome error")},
+		{name: "error, level enabled", level: slog.LevelError, err: fmt.Errorf("awesome error")},
+		{name: "trace all mode, with context attributes", level: slog.LevelInfo, options: []Option{
+			WithTraceAll(),
+			WithContextValue("attrKeyViaValue", "ctxKey"),
+			WithContextFunc("attrKeyViaFunc", func(ctx context.Context) (slog.Value, bool) {
+				return slog.StringValue("value"), true
+			}),
+		}},
+	}
+
+	for _, bb := range benchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			handler := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: bb.level})
+			l := New(append(bb.options, WithHandler(handler))...).(*logger)
+			ctx := context.Background()
+			begin := time.Now().Add(-1 * time.Second)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Trace(ctx, begin, benchmarkFc, bb.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// statementContextKey is the context key under which the statement of a query is stored, to count its
+// bound parameters
+type statementContextKey struct{}
+
+// captureStatement returns a callback storing the statement into its context, so that Trace counts its
+// bound parameters once gorm built it, see WithBindParamsCount
+func (l logger) captureStatement() func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		l := l.snapshot()
+		if !l.bindParamsCount {
+			return
+		}
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		db.Statement.Context = context.WithValue(ctx, statementContextKey{}, db.Statement)
+	}
+}
+
+// appendBindParamsAttribute adds the number of parameters bound to the statement of the query, if it
+// was captured by the plugin
+func (l logger) appendBindParamsAttribute(ctx context.Context, args []slog.Attr) []slog.Attr {
+	if !l.bindParamsCount {
+		return args
+	}
+	statement, _ := ctx.Value(statementContextKey{}).(*gorm.Statement)
+	if statement == nil {
+		return args
+	}
+	return append(args, slog.Int(BindParamsField, len(statement.Vars)))
+}
@@ -0,0 +1,51 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_BindParamsCount(t *testing.T) {
+	t.Run("Bound parameters", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithBindParamsCount()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE id IN ? AND name = ?", []int{1, 2, 3}, "john").Error)
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.Int(BindParamsField, 4))
+	})
+
+	t.Run("Without parameters", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithBindParamsCount()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.Int(BindParamsField, 0))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE id = ?", 1).Error)
+
+		require.Equal(t, 1, receiver.Len())
+		assertNoAttr(t, receiver.Record, BindParamsField)
+	})
+
+	t.Run("Without the plugin", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithBindParamsCount()})
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) { return "DELETE FROM users", 1 }, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		assertNoAttr(t, receiver.Record, BindParamsField)
+	})
+}
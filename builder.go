@@ -0,0 +1,510 @@
+package slogGorm
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoggerBuilder builds a logger with chained calls, each method applying the option of the same name
+type LoggerBuilder struct {
+	options []Option
+}
+
+// Builder returns a builder of logger, an alternative to the list of options.
+//
+// Usage:
+//
+//	gormLogger := slogGorm.Builder().
+//		Handler(handler).
+//		SlowThreshold(500 * time.Millisecond).
+//		TraceAll().
+//		Build()
+func Builder() *LoggerBuilder {
+	return &LoggerBuilder{}
+}
+
+// Build creates the logger, see New
+func (b *LoggerBuilder) Build() Logger {
+	return New(b.options...)
+}
+
+// BuildE creates the logger or returns the errors of the invalid settings, see NewE
+func (b *LoggerBuilder) BuildE() (Logger, error) {
+	return NewE(b.options...)
+}
+
+// Options adds the given options
+func (b *LoggerBuilder) Options(options ...Option) *LoggerBuilder {
+	b.options = append(b.options, options...)
+	return b
+}
+
+// Handler defines the handler, see WithHandler
+func (b *LoggerBuilder) Handler(handler slog.Handler) *LoggerBuilder {
+	return b.Options(WithHandler(handler))
+}
+
+// FallbackHandler writes the records the handler fails to write, see WithFallbackHandler
+func (b *LoggerBuilder) FallbackHandler(handler slog.Handler) *LoggerBuilder {
+	return b.Options(WithFallbackHandler(handler))
+}
+
+// StatsSummary logs periodically the records suppressed or failed, see WithStatsSummary
+func (b *LoggerBuilder) StatsSummary(interval time.Duration) *LoggerBuilder {
+	return b.Options(WithStatsSummary(interval))
+}
+
+// SlowThreshold defines the threshold of the slow queries, see WithSlowThreshold
+func (b *LoggerBuilder) SlowThreshold(threshold time.Duration) *LoggerBuilder {
+	return b.Options(WithSlowThreshold(threshold))
+}
+
+// SlowThresholdFor defines the slow threshold of the SQL queries whose context attribute has the given value,
+// see WithSlowThresholdFor
+func (b *LoggerBuilder) SlowThresholdFor(key, value string, threshold time.Duration) *LoggerBuilder {
+	return b.Options(WithSlowThresholdFor(key, value, threshold))
+}
+
+// Clock defines the clock timing the SQL queries, see WithClock
+func (b *LoggerBuilder) Clock(clock Clock) *LoggerBuilder {
+	return b.Options(WithClock(clock))
+}
+
+// TransactionWatchdog defines the threshold of the long transactions, see WithTransactionWatchdog
+func (b *LoggerBuilder) TransactionWatchdog(threshold time.Duration) *LoggerBuilder {
+	return b.Options(WithTransactionWatchdog(threshold))
+}
+
+// TraceAll logs all SQL messages, see WithTraceAll
+func (b *LoggerBuilder) TraceAll() *LoggerBuilder {
+	return b.Options(WithTraceAll())
+}
+
+// BurstCapture traces all the SQL queries for the given window after each SQL error, see WithBurstCapture
+func (b *LoggerBuilder) BurstCapture(window time.Duration) *LoggerBuilder {
+	return b.Options(WithBurstCapture(window))
+}
+
+// ErrorEscalation escalates the SQL errors repeated more than threshold times within the window, see
+// WithErrorEscalation
+func (b *LoggerBuilder) ErrorEscalation(threshold int, window time.Duration) *LoggerBuilder {
+	return b.Options(WithErrorEscalation(threshold, window))
+}
+
+// MaintenanceSchedule declares the recurring maintenance windows of the slow queries, see WithMaintenanceSchedule
+func (b *LoggerBuilder) MaintenanceSchedule(schedule func(now time.Time) bool) *LoggerBuilder {
+	return b.Options(WithMaintenanceSchedule(schedule))
+}
+
+// IgnoreTrace disables the tracing of SQL queries, see WithIgnoreTrace
+func (b *LoggerBuilder) IgnoreTrace() *LoggerBuilder {
+	return b.Options(WithIgnoreTrace())
+}
+
+// RecordNotFoundError logs the gorm.ErrRecordNotFound errors, see WithRecordNotFoundError
+func (b *LoggerBuilder) RecordNotFoundError() *LoggerBuilder {
+	return b.Options(WithRecordNotFoundError())
+}
+
+// ParameterizedQueries logs the SQL queries without their parameters, see WithParameterizedQueries
+func (b *LoggerBuilder) ParameterizedQueries() *LoggerBuilder {
+	return b.Options(WithParameterizedQueries())
+}
+
+// NoValues guarantees that no value is logged, see WithNoValues
+func (b *LoggerBuilder) NoValues(allowedContextAttrs ...string) *LoggerBuilder {
+	return b.Options(WithNoValues(allowedContextAttrs...))
+}
+
+// MaskedColumns masks the values of the given columns in the SQL queries, see WithMaskedColumns
+func (b *LoggerBuilder) MaskedColumns(columns ...string) *LoggerBuilder {
+	return b.Options(WithMaskedColumns(columns...))
+}
+
+// RedactionVerification verifies that the SQL queries logged don't contain literals, see WithRedactionVerification
+func (b *LoggerBuilder) RedactionVerification(report func(ctx context.Context, violation RedactionViolation)) *LoggerBuilder {
+	return b.Options(WithRedactionVerification(report))
+}
+
+// Audit logs the statements modifying the data with the given handler, see WithAudit
+func (b *LoggerBuilder) Audit(handler slog.Handler, actor func(ctx context.Context) (slog.Value, bool)) *LoggerBuilder {
+	return b.Options(WithAudit(handler, actor))
+}
+
+// AuditHashChain chains the audit records with a rolling hash, see WithAuditHashChain
+func (b *LoggerBuilder) AuditHashChain() *LoggerBuilder {
+	return b.Options(WithAuditHashChain())
+}
+
+// SlowLog writes the slow queries in the format of the slow query log of MySQL, see WithSlowLog
+func (b *LoggerBuilder) SlowLog(w io.Writer) *LoggerBuilder {
+	return b.Options(WithSlowLog(w))
+}
+
+// QueryLogFile writes all the queries to the query log file as JSON Lines, see WithQueryLogFile
+func (b *LoggerBuilder) QueryLogFile(file *QueryLogFile) *LoggerBuilder {
+	return b.Options(WithQueryLogFile(file))
+}
+
+// SlowQueryLogFile writes the slow queries to their own query log file, see WithSlowQueryLogFile
+func (b *LoggerBuilder) SlowQueryLogFile(file *QueryLogFile) *LoggerBuilder {
+	return b.Options(WithSlowQueryLogFile(file))
+}
+
+// WithoutQueryLogFile stops writing the queries to the query log file, see WithoutQueryLogFile
+func (b *LoggerBuilder) WithoutQueryLogFile() *LoggerBuilder {
+	return b.Options(WithoutQueryLogFile())
+}
+
+// QueryStats aggregates the queries by fingerprint, see WithQueryStats
+func (b *LoggerBuilder) QueryStats() *LoggerBuilder {
+	return b.Options(WithQueryStats())
+}
+
+// JobStats aggregates the queries by background job, see WithJobStats
+func (b *LoggerBuilder) JobStats(attr string) *LoggerBuilder {
+	return b.Options(WithJobStats(attr))
+}
+
+// RouteStats aggregates the queries by route, see WithRouteStats
+func (b *LoggerBuilder) RouteStats(attr string) *LoggerBuilder {
+	return b.Options(WithRouteStats(attr))
+}
+
+// Sink hands the queries to the given sinks as typed events, see WithSink
+func (b *LoggerBuilder) Sink(sinks ...Sink) *LoggerBuilder {
+	return b.Options(WithSink(sinks...))
+}
+
+// Tracer records a span for each SQL query, see WithTracer
+func (b *LoggerBuilder) Tracer(tracer Tracer) *LoggerBuilder {
+	return b.Options(WithTracer(tracer))
+}
+
+// SecurityDetection logs the SQL queries suspicious of a SQL injection, see WithSecurityDetection
+func (b *LoggerBuilder) SecurityDetection() *LoggerBuilder {
+	return b.Options(WithSecurityDetection())
+}
+
+// DangerousWriteDetection logs the UPDATE and DELETE statements without WHERE clause, see WithDangerousWriteDetection
+func (b *LoggerBuilder) DangerousWriteDetection() *LoggerBuilder {
+	return b.Options(WithDangerousWriteDetection())
+}
+
+// Scrubber removes the personal data from the records, see WithScrubber
+func (b *LoggerBuilder) Scrubber(scrubber Scrubber) *LoggerBuilder {
+	return b.Options(WithScrubber(scrubber))
+}
+
+// MetadataMode defines where the duration and the rows are logged, see WithMetadataMode
+func (b *LoggerBuilder) MetadataMode(mode MetadataMode) *LoggerBuilder {
+	return b.Options(WithMetadataMode(mode))
+}
+
+// DebugFullQueries logs the full SQL queries in companion records, see WithDebugFullQueries
+func (b *LoggerBuilder) DebugFullQueries() *LoggerBuilder {
+	return b.Options(WithDebugFullQueries())
+}
+
+// StaticMessages logs static messages, see WithStaticMessages
+func (b *LoggerBuilder) StaticMessages() *LoggerBuilder {
+	return b.Options(WithStaticMessages())
+}
+
+// MinLevel defines the minimum level of the records logged, see WithMinLevel
+func (b *LoggerBuilder) MinLevel(level slog.Leveler) *LoggerBuilder {
+	return b.Options(WithMinLevel(level))
+}
+
+// SilencedLogTypes turns off the records of the given log types, see WithSilencedLogTypes
+func (b *LoggerBuilder) SilencedLogTypes(logTypes ...LogType) *LoggerBuilder {
+	return b.Options(WithSilencedLogTypes(logTypes...))
+}
+
+// Level sets the slog.Level of a LogType, see SetLogLevel
+func (b *LoggerBuilder) Level(key LogType, level slog.Level) *LoggerBuilder {
+	return b.Options(SetLogLevel(key, level))
+}
+
+// Message defines the template of the messages of a LogType, see WithMessage
+func (b *LoggerBuilder) Message(logType LogType, template string) *LoggerBuilder {
+	return b.Options(WithMessage(logType, template))
+}
+
+// MessageFunc formats the messages of a LogType with the given function, see WithMessageFunc
+func (b *LoggerBuilder) MessageFunc(logType LogType, fn func(ctx context.Context, info MessageInfo) string) *LoggerBuilder {
+	return b.Options(WithMessageFunc(logType, fn))
+}
+
+// MessageCatalog defines the templates of the messages of the log types of the catalog, see WithMessageCatalog
+func (b *LoggerBuilder) MessageCatalog(catalog MessageCatalog) *LoggerBuilder {
+	return b.Options(WithMessageCatalog(catalog))
+}
+
+// SourceField defines the field of the file name and line number, see WithSourceField
+func (b *LoggerBuilder) SourceField(field string) *LoggerBuilder {
+	return b.Options(WithSourceField(field))
+}
+
+// WithoutSourceField disables the field of the file name and line number, see WithoutSourceField
+func (b *LoggerBuilder) WithoutSourceField() *LoggerBuilder {
+	return b.Options(WithoutSourceField())
+}
+
+// PprofLabels sets the pprof labels of the SQL queries executed, see WithPprofLabels
+func (b *LoggerBuilder) PprofLabels() *LoggerBuilder {
+	return b.Options(WithPprofLabels())
+}
+
+// Name names the logger and registers it under the name, see WithName
+func (b *LoggerBuilder) Name(name string) *LoggerBuilder {
+	return b.Options(WithName(name))
+}
+
+// Resource stamps the records with the service, its version and its environment, see WithResource
+func (b *LoggerBuilder) Resource(service, version, environment string) *LoggerBuilder {
+	return b.Options(WithResource(service, version, environment))
+}
+
+// ResourceFromEnv stamps the records with the resource attributes of the OpenTelemetry environment variables,
+// see WithResourceFromEnv
+func (b *LoggerBuilder) ResourceFromEnv() *LoggerBuilder {
+	return b.Options(WithResourceFromEnv())
+}
+
+// ContextAttrConflict defines how the context attributes conflicting with the attributes of the handler are
+// logged, see WithContextAttrConflict
+func (b *LoggerBuilder) ContextAttrConflict(conflict ContextAttrConflict, handlerKeys ...string) *LoggerBuilder {
+	return b.Options(WithContextAttrConflict(conflict, handlerKeys...))
+}
+
+// KubernetesMetadata stamps the records with the pod, its namespace and its node, see WithKubernetesMetadata
+func (b *LoggerBuilder) KubernetesMetadata() *LoggerBuilder {
+	return b.Options(WithKubernetesMetadata())
+}
+
+// HostInfo stamps the records with the host name and the process ID, see WithHostInfo
+func (b *LoggerBuilder) HostInfo() *LoggerBuilder {
+	return b.Options(WithHostInfo())
+}
+
+// BuildInfo stamps every record with the build information, see WithBuildInfo
+func (b *LoggerBuilder) BuildInfo() *LoggerBuilder {
+	return b.Options(WithBuildInfo())
+}
+
+// StartupBuildInfo stamps the startup summary with the build information, see WithStartupBuildInfo
+func (b *LoggerBuilder) StartupBuildInfo() *LoggerBuilder {
+	return b.Options(WithStartupBuildInfo())
+}
+
+// ExplainOnSlow logs the plans of the slow queries, see WithExplainOnSlow
+func (b *LoggerBuilder) ExplainOnSlow(db *gorm.DB, options ...ExplainOption) *LoggerBuilder {
+	return b.Options(WithExplainOnSlow(db, options...))
+}
+
+// DecisionDebug logs why the SQL queries are not logged, see WithDecisionDebug
+func (b *LoggerBuilder) DecisionDebug() *LoggerBuilder {
+	return b.Options(WithDecisionDebug())
+}
+
+// GoroutineID adds the ID of the goroutine executing each SQL query, for debugging only, see WithGoroutineID
+func (b *LoggerBuilder) GoroutineID() *LoggerBuilder {
+	return b.Options(WithGoroutineID())
+}
+
+// BindParamsCount adds the number of parameters bound to each statement, see WithBindParamsCount
+func (b *LoggerBuilder) BindParamsCount() *LoggerBuilder {
+	return b.Options(WithBindParamsCount())
+}
+
+// QueryBytes adds the byte length of the SQL queries, see WithQueryBytes
+func (b *LoggerBuilder) QueryBytes() *LoggerBuilder {
+	return b.Options(WithQueryBytes())
+}
+
+// MaxQueryBytesWarn logs the SQL queries longer than the given number of bytes, see WithMaxQueryBytesWarn
+func (b *LoggerBuilder) MaxQueryBytesWarn(n int) *LoggerBuilder {
+	return b.Options(WithMaxQueryBytesWarn(n))
+}
+
+// LargeResultThreshold logs the SQL queries returning more rows than the threshold, see WithLargeResultThreshold
+func (b *LoggerBuilder) LargeResultThreshold(rows int64) *LoggerBuilder {
+	return b.Options(WithLargeResultThreshold(rows))
+}
+
+// BatchMetrics adds the size and the latency per row of the batch inserts, see WithBatchMetrics
+func (b *LoggerBuilder) BatchMetrics() *LoggerBuilder {
+	return b.Options(WithBatchMetrics())
+}
+
+// ReturningRedaction strips or masks the RETURNING clauses, see WithReturningRedaction
+func (b *LoggerBuilder) ReturningRedaction(redaction ReturningRedaction) *LoggerBuilder {
+	return b.Options(WithReturningRedaction(redaction))
+}
+
+// WithoutComments removes the comments from the SQL queries logged, see WithoutComments
+func (b *LoggerBuilder) WithoutComments() *LoggerBuilder {
+	return b.Options(WithoutComments())
+}
+
+// ShardedTables adds the logical and shard tables of the queries on the sharded tables, see WithShardedTables
+func (b *LoggerBuilder) ShardedTables(tables ...string) *LoggerBuilder {
+	return b.Options(WithShardedTables(tables...))
+}
+
+// SoftDeleteFlag flags the statements of the soft deletes, see WithSoftDeleteFlag
+func (b *LoggerBuilder) SoftDeleteFlag() *LoggerBuilder {
+	return b.Options(WithSoftDeleteFlag())
+}
+
+// SpanContext stamps the SQL records with the identifiers of their span, see WithSpanContext
+func (b *LoggerBuilder) SpanContext(spanContext func(ctx context.Context) (traceID, spanID string)) *LoggerBuilder {
+	return b.Options(WithSpanContext(spanContext))
+}
+
+// WithoutSpanStatement does not log the SQL query on the records linked to a span, see WithoutSpanStatement
+func (b *LoggerBuilder) WithoutSpanStatement() *LoggerBuilder {
+	return b.Options(WithoutSpanStatement())
+}
+
+// Tables adds the tables referenced by the SQL queries, see WithTables
+func (b *LoggerBuilder) Tables() *LoggerBuilder {
+	return b.Options(WithTables())
+}
+
+// TenantLevels defines the minimum level of the records of the tenants, see WithTenantLevels
+func (b *LoggerBuilder) TenantLevels(fn func(ctx context.Context) (slog.Level, bool)) *LoggerBuilder {
+	return b.Options(WithTenantLevels(fn))
+}
+
+// Tenant stamps the tenant of the context on each record, see WithTenant
+func (b *LoggerBuilder) Tenant(fn func(ctx context.Context) (string, bool)) *LoggerBuilder {
+	return b.Options(WithTenant(fn))
+}
+
+// TenantSamplingRates overrides the sampling rate of the given tenants, see WithTenantSamplingRates
+func (b *LoggerBuilder) TenantSamplingRates(rates map[string]float64) *LoggerBuilder {
+	return b.Options(WithTenantSamplingRates(rates))
+}
+
+// TablePolicy defines how the SQL queries on the given table are logged, see WithTablePolicy
+func (b *LoggerBuilder) TablePolicy(table string, policy ModelPolicy) *LoggerBuilder {
+	return b.Options(WithTablePolicy(table, policy))
+}
+
+// ModelPolicy defines how the SQL queries on the table of the given model are logged, see WithModelPolicy
+func (b *LoggerBuilder) ModelPolicy(model any, policy ModelPolicy) *LoggerBuilder {
+	return b.Options(WithModelPolicy(model, policy))
+}
+
+// CallerFunction adds the package and the function calling gorm, see WithCallerFunction
+func (b *LoggerBuilder) CallerFunction() *LoggerBuilder {
+	return b.Options(WithCallerFunction())
+}
+
+// SourceCacheSize defines the number of call sites whose source is cached, see WithSourceCacheSize
+func (b *LoggerBuilder) SourceCacheSize(size int) *LoggerBuilder {
+	return b.Options(WithSourceCacheSize(size))
+}
+
+// ErrorField defines the field of the error, see WithErrorField
+func (b *LoggerBuilder) ErrorField(field string) *LoggerBuilder {
+	return b.Options(WithErrorField(field))
+}
+
+// ContextValue adds a context value to the log, see WithContextValue
+func (b *LoggerBuilder) ContextValue(slogAttrName string, contextKey any) *LoggerBuilder {
+	return b.Options(WithContextValue(slogAttrName, contextKey))
+}
+
+// ContextFunc adds an attribute returned by the given function, see WithContextFunc
+func (b *LoggerBuilder) ContextFunc(slogAttrName string, slogValueFunc func(ctx context.Context) (slog.Value, bool)) *LoggerBuilder {
+	return b.Options(WithContextFunc(slogAttrName, slogValueFunc))
+}
+
+// IgnoredOperations ignores the queries with the given operations, see WithIgnoredOperations
+func (b *LoggerBuilder) IgnoredOperations(operations ...string) *LoggerBuilder {
+	return b.Options(WithIgnoredOperations(operations...))
+}
+
+// IgnoredTables ignores the queries on the given tables, see WithIgnoredTables
+func (b *LoggerBuilder) IgnoredTables(tables ...string) *LoggerBuilder {
+	return b.Options(WithIgnoredTables(tables...))
+}
+
+// IgnoredQueries ignores the queries matching one of the given patterns, see WithIgnoredQueries
+func (b *LoggerBuilder) IgnoredQueries(patterns ...*regexp.Regexp) *LoggerBuilder {
+	return b.Options(WithIgnoredQueries(patterns...))
+}
+
+// QueryFilter ignores the queries for which the given function returns false, see WithQueryFilter
+func (b *LoggerBuilder) QueryFilter(fn func(ctx context.Context, query QueryInfo) bool) *LoggerBuilder {
+	return b.Options(WithQueryFilter(fn))
+}
+
+// KeyCase converts the keys of the attributes, see WithKeyCase
+func (b *LoggerBuilder) KeyCase(keyCase KeyCase) *LoggerBuilder {
+	return b.Options(WithKeyCase(keyCase))
+}
+
+// Preset names the attributes after the registered field preset, see WithPreset
+func (b *LoggerBuilder) Preset(name string) *LoggerBuilder {
+	return b.Options(WithPreset(name))
+}
+
+// FieldPreset names the attributes with the given field preset, see WithFieldPreset
+func (b *LoggerBuilder) FieldPreset(preset FieldPreset) *LoggerBuilder {
+	return b.Options(WithFieldPreset(preset))
+}
+
+// OtelSemconvFields names the attributes after the semantic conventions of OpenTelemetry, see WithOtelSemconvFields
+func (b *LoggerBuilder) OtelSemconvFields() *LoggerBuilder {
+	return b.Options(WithOtelSemconvFields())
+}
+
+// ECSFields names the attributes after the Elastic Common Schema, see WithECSFields
+func (b *LoggerBuilder) ECSFields() *LoggerBuilder {
+	return b.Options(WithECSFields())
+}
+
+// GoogleCloudFields names the attributes after the structured logs of Google Cloud Logging, see WithGoogleCloudFields
+func (b *LoggerBuilder) GoogleCloudFields() *LoggerBuilder {
+	return b.Options(WithGoogleCloudFields())
+}
+
+// DatadogFields names the attributes after the standard attributes of Datadog, see WithDatadogFields
+func (b *LoggerBuilder) DatadogFields(spanContext func(ctx context.Context) (traceID, spanID string)) *LoggerBuilder {
+	return b.Options(WithDatadogFields(spanContext))
+}
+
+// SamplingRate logs only the given rate of the SQL messages traced, see WithSamplingRate
+func (b *LoggerBuilder) SamplingRate(rate float64) *LoggerBuilder {
+	return b.Options(WithSamplingRate(rate))
+}
+
+// VolumeBudget adapts the sampling to log at most the given number of SQL records per minute, see
+// WithVolumeBudget
+func (b *LoggerBuilder) VolumeBudget(maxRecordsPerMinute int) *LoggerBuilder {
+	return b.Options(WithVolumeBudget(maxRecordsPerMinute))
+}
+
+// SamplingRates sets the sampling rate of each log type of the SQL queries, see WithSamplingRates
+func (b *LoggerBuilder) SamplingRates(rates map[LogType]float64) *LoggerBuilder {
+	return b.Options(WithSamplingRates(rates))
+}
+
+// Async enables the asynchronous mode, see WithAsync
+func (b *LoggerBuilder) Async(bufferSize int) *LoggerBuilder {
+	return b.Options(WithAsync(bufferSize))
+}
+
+// AsyncDropPolicy defines the behavior of the asynchronous mode when its buffer is full, see WithAsyncDropPolicy
+func (b *LoggerBuilder) AsyncDropPolicy(policy DropPolicy) *LoggerBuilder {
+	return b.Options(WithAsyncDropPolicy(policy))
+}
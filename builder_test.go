@@ -0,0 +1,120 @@
+package slogGorm
+
+import (
+	"go/ast"
+	"go/parser"
+	gotoken "go/token"
+	"io/fs"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("Build", func(t *testing.T) {
+		handler := NewDummyHandler()
+		minLevel := &slog.LevelVar{}
+
+		l := Builder().
+			Handler(handler).
+			SlowThreshold(500*time.Millisecond).
+			TransactionWatchdog(time.Minute).
+			TraceAll().
+			RecordNotFoundError().
+			ParameterizedQueries().
+			StaticMessages().
+			MinLevel(minLevel).
+			Level(DefaultLogType, slog.LevelDebug).
+			SourceField("origin").
+			SourceCacheSize(16).
+			ErrorField("err").
+			KeyCase(KebabCase).
+			Preset("ecs").
+			ContextValue("request_id", "request_id").
+			IgnoredOperations("SELECT").
+			IgnoredTables("sessions").
+			IgnoredQueries(regexp.MustCompile(`^SELECT 1$`)).
+			SamplingRate(0.5).
+			Build().(*logger)
+
+		expected := New(
+			WithHandler(handler),
+			WithSlowThreshold(500*time.Millisecond),
+			WithTransactionWatchdog(time.Minute),
+			WithTraceAll(),
+			WithRecordNotFoundError(),
+			WithParameterizedQueries(),
+			WithStaticMessages(),
+			WithMinLevel(minLevel),
+			SetLogLevel(DefaultLogType, slog.LevelDebug),
+			WithSourceField("origin"),
+			WithSourceCacheSize(16),
+			WithErrorField("err"),
+			WithKeyCase(KebabCase),
+			WithPreset("ecs"),
+			WithContextValue("request_id", "request_id"),
+			WithIgnoredOperations("SELECT"),
+			WithIgnoredTables("sessions"),
+			WithIgnoredQueries(regexp.MustCompile(`^SELECT 1$`)),
+			WithSamplingRate(0.5),
+		).(*logger)
+		assert.Equal(t, expected.Config(), l.Config())
+		assert.Equal(t, expected.sourceCacheSize, l.sourceCacheSize)
+		assert.Len(t, l.contextAttrs, 1)
+	})
+
+	t.Run("BuildE", func(t *testing.T) {
+		l, err := Builder().SlowThreshold(-time.Second).BuildE()
+
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+
+	t.Run("Asynchronous mode", func(t *testing.T) {
+		l := Builder().IgnoreTrace().WithoutSourceField().Async(16).AsyncDropPolicy(DropNewest).Build().(*logger)
+
+		assert.True(t, l.ignoreTrace)
+		assert.Equal(t, "", l.sourceField)
+		require.NotNil(t, l.async)
+		assert.Equal(t, DropNewest, l.asyncDropPolicy)
+		require.NoError(t, l.Close())
+	})
+}
+
+func TestBuilder_Options(t *testing.T) {
+	// deprecated are the options without builder method
+	deprecated := map[string]bool{"WithLogger": true}
+
+	fset := gotoken.NewFileSet()
+	packages, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	builder := reflect.TypeOf(&LoggerBuilder{})
+	for _, file := range packages["slogGorm"].Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "With") || deprecated[fn.Name.Name] {
+				continue
+			}
+			if result, ok := fn.Type.Results.List[0].Type.(*ast.Ident); !ok || result.Name != "Option" {
+				continue
+			}
+
+			// The options named WithoutXxx keep their name, the others drop the With prefix
+			method := strings.TrimPrefix(fn.Name.Name, "With")
+			if strings.HasPrefix(fn.Name.Name, "Without") {
+				method = fn.Name.Name
+			}
+			_, ok = builder.MethodByName(method)
+			assert.True(t, ok, "no builder method %s for the option %s", method, fn.Name.Name)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the path of the slog-gorm module, looked up in the build information of the binary
+const modulePath = "github.com/orandin/slog-gorm"
+
+// buildInfoScope defines the records stamped with the build information, see WithBuildInfo
+type buildInfoScope int
+
+const (
+	buildInfoNone buildInfoScope = iota
+	buildInfoStartup
+	buildInfoAllRecords
+)
+
+// moduleVersion returns the version of the slog-gorm module built in the binary, read once
+var moduleVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return "unknown"
+})
+
+// Version returns the version of slog-gorm built in the binary (e.g. "v1.4.0"), read from its build
+// information: "(devel)" when built from a local checkout, "unknown" when the build information is missing.
+func Version() string {
+	return moduleVersion()
+}
+
+// buildInfoAttr returns the attribute of the build information: the version of slog-gorm and the features enabled
+func (l logger) buildInfoAttr() slog.Attr {
+	return slog.Group(BuildInfoField,
+		slog.String(VersionField, Version()),
+		slog.Any(FeaturesField, l.features()),
+	)
+}
+
+// features returns the names of the features enabled, sorted
+func (l logger) features() []string {
+	features := []string{}
+	add := func(enabled bool, name string) {
+		if enabled {
+			features = append(features, name)
+		}
+	}
+	add(l.async != nil || l.asyncBufferSize > 0, "async")
+	add(l.auditHandler != nil, "audit")
+	add(l.auditChain != nil, "audit_hash_chain")
+	add(l.batchMetrics, "batch_metrics")
+	add(l.bindParamsCount, "bind_params_count")
+	add(l.burstWindow > 0, "burst_capture")
+	add(l.callerFunction, "caller_function")
+	add(l.attrConflict != ConflictKept, "context_attr_conflict")
+	add(l.dangerousWriteDetection, "dangerous_write_detection")
+	add(l.debugFullQueries, "debug_full_queries")
+	add(l.decisionDebug, "decision_debug")
+	add(l.escalationThreshold > 0, "error_escalation")
+	add(l.explainer != nil, "explain_on_slow")
+	add(l.fallbackHandler != nil, "fallback_handler")
+	add(len(l.filters) > 0, "filters")
+	add(l.goroutineID, "goroutine_id")
+	add(l.ignoreTrace, "ignore_trace")
+	add(l.jobStats != nil, "job_stats")
+	add(l.largeResultThreshold > 0, "large_result_threshold")
+	add(l.maintenanceSchedule != nil, "maintenance_schedule")
+	add(len(l.maskedColumns) > 0, "masked_columns")
+	add(l.maxQueryBytes > 0, "max_query_bytes_warn")
+	add(l.minLevel != nil, "min_level")
+	add(len(l.policies) > 0, "model_policies")
+	add(l.noValues, "no_values")
+	add(l.parameterizedQueries, "parameterized_queries")
+	add(l.explainer != nil && l.explainer.plans != nil, "plan_change_detection")
+	add(l.pprofLabels, "pprof_labels")
+	add(l.queryBytes, "query_bytes")
+	add(l.queryLogFile != nil, "query_log_file")
+	add(l.queryStats != nil, "query_stats")
+	add(l.redactionCheck != nil, "redaction_verification")
+	add(len(l.resource) > 0, "resource")
+	add(l.returningRedaction != ReturningKept, "returning_redaction")
+	add(l.routeStats != nil, "route_stats")
+	add(l.samplingRate < 1, "sampling")
+	add(len(l.logTypeSamplingRates) > 0, "sampling_rates")
+	add(l.scrubber != nil, "scrubber")
+	add(l.securityDetection, "security_detection")
+	add(len(l.shardedTables) > 0, "sharded_tables")
+	add(len(l.silencedLogTypes) > 0, "silenced_log_types")
+	add(len(l.sinks) > 0, "sinks")
+	add(l.slowLog != nil, "slow_log")
+	add(l.slowQueryLogFile != nil, "slow_query_log_file")
+	add(len(l.slowThresholds) > 0, "slow_threshold_overrides")
+	add(l.softDeleteFlag, "soft_delete_flag")
+	add(l.spanContext != nil, "span_context")
+	add(l.staticMessages, "static_messages")
+	add(l.statsSummary != nil, "stats_summary")
+	add(l.tables, "tables")
+	add(l.tenant != nil, "tenant")
+	add(l.tenantLevel != nil, "tenant_levels")
+	add(len(l.tenantSamplingRates) > 0, "tenant_sampling_rates")
+	add(l.traceAll, "trace_all")
+	add(l.tracer != nil, "tracer")
+	add(l.txWatchdogThreshold > 0, "transaction_watchdog")
+	add(l.volumeBudget > 0, "volume_budget")
+	add(l.withoutComments, "without_comments")
+	add(l.withoutSpanStatement, "without_span_statement")
+	return features
+}
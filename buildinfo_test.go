@@ -0,0 +1,142 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	// The tests are built from the module itself
+	assert.Equal(t, "(devel)", Version())
+}
+
+func Test_logger_BuildInfo(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("Every record", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithBuildInfo(), WithTraceAll(), WithNoValues()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		require.Equal(t, 2, receiver.Len())
+		for _, r := range receiver.Records {
+			values := startupValues(r)
+			assert.Equal(t, Version(), values["slog_gorm.version"].String())
+			assert.Equal(t, []string{"no_values", "trace_all"}, values["slog_gorm.features"].Any())
+		}
+	})
+
+	t.Run("Startup summary only", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithStartupBuildInfo(), WithTraceAll()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		require.Equal(t, 2, receiver.Len())
+		assert.NotContains(t, startupValues(receiver.Records[0]), "slog_gorm.version")
+		assert.Equal(t, Version(), startupValues(receiver.Records[1])["slog_gorm.version"].String())
+	})
+
+	t.Run("Key casing", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithBuildInfo(), WithTraceAll(), WithKeyCase(CamelCase)})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Contains(t, startupValues(*receiver.Record), "slogGorm.version")
+	})
+
+	t.Run("Features of the derived loggers", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithBuildInfo()})
+
+		gormLogger.with(WithTraceAll()).Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, []string{"trace_all"}, startupValues(*receiver.Record)["slog_gorm.features"].Any())
+	})
+}
+
+func Test_logger_features(t *testing.T) {
+	assert.Empty(t, New(WithHandler(slog.Default().Handler())).(*logger).features())
+}
+
+func Test_logger_features_Sorted(t *testing.T) {
+	l := &logger{
+		asyncBufferSize:         1,
+		auditHandler:            NewDummyHandler(),
+		auditChain:              &auditChain{},
+		batchMetrics:            true,
+		bindParamsCount:         true,
+		burstWindow:             time.Second,
+		callerFunction:          true,
+		attrConflict:            ConflictSkipped,
+		dangerousWriteDetection: true,
+		debugFullQueries:        true,
+		decisionDebug:           true,
+		escalationThreshold:     1,
+		explainer:               &explainer{plans: &planCache{}},
+		fallbackHandler:         NewDummyHandler(),
+		filters:                 []queryFilter{{}},
+		goroutineID:             true,
+		ignoreTrace:             true,
+		jobStats:                &attrStats{},
+		largeResultThreshold:    1,
+		maintenanceSchedule:     func(time.Time) bool { return false },
+		maskedColumns:           []maskedColumn{{}},
+		maxQueryBytes:           1,
+		minLevel:                slog.LevelInfo,
+		policies:                []tablePolicy{{}},
+		noValues:                true,
+		parameterizedQueries:    true,
+		pprofLabels:             true,
+		queryBytes:              true,
+		queryLogFile:            &QueryLogFile{},
+		queryStats:              &queryStats{},
+		redactionCheck:          func(context.Context, RedactionViolation) {},
+		resource:                []slog.Attr{slog.String(ServiceNameField, "api")},
+		returningRedaction:      ReturningStripped,
+		routeStats:              &attrStats{},
+		samplingRate:            0.5,
+		logTypeSamplingRates:    map[LogType]float64{ErrorLogType: 1},
+		scrubber:                ScrubberFunc(nil),
+		securityDetection:       true,
+		shardedTables:           []string{"orders"},
+		silencedLogTypes:        []LogType{SlowQueryLogType},
+		sinks:                   []Sink{nil},
+		slowLog:                 &slowLog{},
+		slowQueryLogFile:        &QueryLogFile{},
+		slowThresholds:          []slowThresholdOverride{{}},
+		softDeleteFlag:          true,
+		spanContext:             func(context.Context) (string, string) { return "", "" },
+		staticMessages:          true,
+		statsSummary:            &statsSummary{},
+		tables:                  true,
+		tenant:                  func(context.Context) (string, bool) { return "", false },
+		tenantLevel:             func(context.Context) (slog.Level, bool) { return 0, false },
+		tenantSamplingRates:     map[string]float64{"acme": 1},
+		traceAll:                true,
+		tracer:                  TracerFunc(nil),
+		txWatchdogThreshold:     time.Second,
+		volumeBudget:            1,
+		withoutComments:         true,
+		withoutSpanStatement:    true,
+	}
+
+	// All the features are enabled, so that a feature added out of order is detected
+	source, err := os.ReadFile("buildinfo.go")
+	require.NoError(t, err)
+	features := l.features()
+	assert.Len(t, features, strings.Count(string(source), "\tadd("))
+	assert.True(t, sort.StringsAreSorted(features), "the features are not sorted: %v", features)
+}
@@ -0,0 +1,54 @@
+package slogGorm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// burstCapture is the end of the burst capture started by the last SQL error, shared with the loggers derived
+// from, see WithBurstCapture
+type burstCapture struct {
+	// until is the end of the burst capture, in nanoseconds since the Unix epoch, 0 if none was started
+	until atomic.Int64
+}
+
+// burstCaptureKey is the key of the burst capture of the context, see ContextWithBurstCapture
+type burstCaptureKey struct{}
+
+// burstContext is the burst capture of a context, started by its first SQL error
+type burstContext struct {
+	started atomic.Bool
+}
+
+// ContextWithBurstCapture scopes a burst capture to the context (e.g. of a request or of a job): after its
+// first SQL error, the remaining SQL queries of the context are traced as with WithTraceAll, so that the
+// workload following the error is captured, whatever the option WithBurstCapture.
+//
+// Usage:
+//
+//	ctx = slogGorm.ContextWithBurstCapture(ctx)
+//	db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { ... })
+func ContextWithBurstCapture(ctx context.Context) context.Context {
+	return context.WithValue(ctx, burstCaptureKey{}, &burstContext{})
+}
+
+// startBurst starts the burst capture of the logger (see WithBurstCapture) and of the context (see
+// ContextWithBurstCapture) after an SQL error
+func (l logger) startBurst(ctx context.Context) {
+	if l.burst != nil {
+		l.burst.until.Store(l.clock.Now().Add(l.burstWindow).UnixNano())
+	}
+	if burst, ok := ctx.Value(burstCaptureKey{}).(*burstContext); ok {
+		burst.started.Store(true)
+	}
+}
+
+// bursting reports whether the SQL queries of the context are traced by a burst capture
+func (l logger) bursting(ctx context.Context) bool {
+	if l.burst != nil && l.clock.Now().Before(time.Unix(0, l.burst.until.Load())) {
+		return true
+	}
+	burst, ok := ctx.Value(burstCaptureKey{}).(*burstContext)
+	return ok && burst.started.Load()
+}
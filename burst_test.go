@@ -0,0 +1,70 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// movingClock is a clock whose time is moved by the tests
+type movingClock struct {
+	now *time.Time
+}
+
+func (c movingClock) Now() time.Time {
+	return *c.now
+}
+
+func (c movingClock) Since(t time.Time) time.Duration {
+	return c.now.Sub(t)
+}
+
+func Test_logger_BurstCapture(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("Window", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithBurstCapture(time.Minute),
+		})
+
+		gormLogger.Trace(context.Background(), now, fc, nil)
+		assert.Equal(t, 0, receiver.Len())
+
+		gormLogger.Trace(context.Background(), now, fc, errors.New("awesome error"))
+		gormLogger.Trace(context.Background(), now, fc, nil)
+		require.Equal(t, 2, receiver.Len())
+		assert.Equal(t, "SQL query executed [0s]", receiver.Record.Message)
+
+		derived := gormLogger.with()
+		now = now.Add(30 * time.Second)
+		derived.Trace(context.Background(), now, fc, nil)
+		assert.Equal(t, 3, receiver.Len(), "the burst capture is shared with the derived loggers")
+
+		now = now.Add(time.Minute)
+		gormLogger.Trace(context.Background(), now, fc, nil)
+		assert.Equal(t, 3, receiver.Len())
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		ctx := ContextWithBurstCapture(context.Background())
+
+		gormLogger.Trace(ctx, time.Now(), fc, nil)
+		assert.Equal(t, 0, receiver.Len())
+
+		gormLogger.Trace(ctx, time.Now(), fc, errors.New("awesome error"))
+		gormLogger.Trace(ctx, time.Now(), fc, nil)
+		assert.Equal(t, 2, receiver.Len())
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		assert.Equal(t, 2, receiver.Len(), "the other contexts are not traced")
+	})
+}
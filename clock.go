@@ -0,0 +1,22 @@
+package slogGorm
+
+import "time"
+
+// Clock provides the current time to the logger, see WithClock
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+// systemClock is the clock of the system, used by default
+type systemClock struct{}
+
+// Now implements Clock
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since implements Clock
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
@@ -0,0 +1,42 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock is a clock at a fixed time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (c fixedClock) Since(t time.Time) time.Duration {
+	return c.now.Sub(t)
+}
+
+func Test_logger_Clock(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	receiver, gormLogger := getReceiverAndLogger([]Option{
+		WithClock(fixedClock{now: now}),
+		WithSlowThreshold(100 * time.Millisecond),
+	})
+	fc := func() (string, int64) { return "SELECT * FROM users", 1 }
+
+	gormLogger.Trace(context.Background(), now.Add(-50*time.Millisecond), fc, nil)
+	assert.Nil(t, receiver.Record)
+
+	gormLogger.Trace(context.Background(), now.Add(-200*time.Millisecond), fc, nil)
+	require.NotNil(t, receiver.Record)
+	assert.Equal(t, now, receiver.Record.Time)
+	assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+	assertHasAttr(t, receiver.Record, slog.Duration(DurationField, 200*time.Millisecond))
+}
@@ -0,0 +1,54 @@
+package slogGorm
+
+import (
+	"strings"
+)
+
+// stripComments removes the comments from the SQL query if enabled, see WithoutComments
+func (l logger) stripComments(sql string) string {
+	if !l.withoutComments {
+		return sql
+	}
+	return stripComments(sql)
+}
+
+// stripComments removes the comments from the SQL query, a comment separating two tokens being replaced
+// by a whitespace
+func stripComments(sql string) string {
+	if !strings.Contains(sql, "--") && !strings.Contains(sql, "/*") {
+		return sql
+	}
+
+	var (
+		b        strings.Builder
+		stripped bool
+		pending  bool // whether a comment was removed since the last token written
+	)
+	b.Grow(len(sql))
+	tokenize(sql, func(t token) bool {
+		if t.kind == tokenComment {
+			stripped, pending = true, true
+			return true
+		}
+		if pending {
+			pending = false
+			last := byte(' ')
+			if b.Len() > 0 {
+				last = b.String()[b.Len()-1]
+			}
+			spaced := last == ' ' || last == '\t' || last == '\n' || last == '\r'
+			switch {
+			case t.kind == tokenSpace && spaced:
+				return true
+			case t.kind != tokenSpace && !spaced:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(t.text)
+		return true
+	})
+	if !stripped {
+		return sql
+	}
+	return strings.TrimSpace(b.String())
+}
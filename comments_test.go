@@ -0,0 +1,44 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_stripComments(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{sql: "SELECT * FROM users /*traceparent='00-4bf9',route='/users'*/", want: "SELECT * FROM users"},
+		{sql: "/* app */ SELECT 1", want: "SELECT 1"},
+		{sql: "SELECT /* columns */ id FROM users", want: "SELECT id FROM users"},
+		{sql: "SELECT id -- the id\nFROM users", want: "SELECT id FROM users"},
+		{sql: "SELECT 1/*x*/+2", want: "SELECT 1 +2"},
+		{sql: "SELECT '/* not a comment */', 'a--b'", want: "SELECT '/* not a comment */', 'a--b'"},
+		{sql: "SELECT 1 - -1", want: "SELECT 1 - -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, stripComments(tt.sql))
+		})
+	}
+}
+
+func Test_logger_WithoutComments(t *testing.T) {
+	receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithoutComments()})
+
+	gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT * FROM users WHERE id = 1 /*controller='users',action='show'*/", 1
+	}, nil)
+
+	require.Equal(t, 1, receiver.Len())
+	r := resolveRecord(*receiver.Record)
+	assertHasAttr(t, &r, slog.String(QueryField, "SELECT * FROM users WHERE id = 1"))
+}
@@ -0,0 +1,652 @@
+package slogGorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Config is the configuration of the logger, an alternative to the list of options.
+// The zero values keep the defaults of New, except the ones explicitly set in a configuration
+// file or an environment variable (e.g. "sampling_rate: 0"), which are applied.
+//
+// It can be loaded from a JSON or YAML file with NewFromConfigFile:
+//
+//	slow_threshold: 500ms
+//	trace_all: true
+//	levels:
+//	  default: DEBUG
+//	  slow_query: ERROR
+//	ignored_queries:
+//	  - ^SELECT 1$
+//	sampling_rate: 0.1
+//	sampling_rates:
+//	  slow_query: 0.5
+//	async_buffer_size: 1024
+//	async_drop_policy: drop_oldest
+type Config struct {
+	// Handler is the slog.Handler writing the records (slog.Default().Handler() by default)
+	Handler slog.Handler `json:"-" yaml:"-"`
+	// Name names the logger and registers it under the name, see WithName
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// SlowThreshold is the threshold above which a sql query is considered slow (disabled with zero)
+	SlowThreshold time.Duration `json:"slow_threshold,omitempty" yaml:"slow_threshold,omitempty"`
+	// TransactionWatchdog is the threshold above which an open transaction is reported (disabled with zero)
+	TransactionWatchdog time.Duration `json:"transaction_watchdog,omitempty" yaml:"transaction_watchdog,omitempty"`
+	// BurstCapture is the window during which all the SQL queries are traced after an SQL error (disabled with zero)
+	BurstCapture time.Duration `json:"burst_capture,omitempty" yaml:"burst_capture,omitempty"`
+
+	// TraceAll logs all SQL messages
+	TraceAll bool `json:"trace_all,omitempty" yaml:"trace_all,omitempty"`
+	// IgnoreTrace disables the tracing of SQL queries
+	IgnoreTrace bool `json:"ignore_trace,omitempty" yaml:"ignore_trace,omitempty"`
+	// RecordNotFoundError logs the gorm.ErrRecordNotFound errors
+	RecordNotFoundError bool `json:"record_not_found_error,omitempty" yaml:"record_not_found_error,omitempty"`
+	// ParameterizedQueries logs the SQL queries without their parameters
+	ParameterizedQueries bool `json:"parameterized_queries,omitempty" yaml:"parameterized_queries,omitempty"`
+	// StaticMessages logs the slow queries and the SQL messages with static messages
+	StaticMessages bool `json:"static_messages,omitempty" yaml:"static_messages,omitempty"`
+	// MetadataMode defines where the duration and the rows are logged (e.g. "message"), see WithMetadataMode
+	MetadataMode MetadataMode `json:"metadata_mode,omitempty" yaml:"metadata_mode,omitempty"`
+	// NoValues guarantees that no value is logged, see WithNoValues
+	NoValues bool `json:"no_values,omitempty" yaml:"no_values,omitempty"`
+	// AllowedContextAttrs are the names of the context attributes logged with NoValues
+	AllowedContextAttrs []string `json:"allowed_context_attrs,omitempty" yaml:"allowed_context_attrs,omitempty"`
+	// MaskedColumns masks the values of the given columns (e.g. "users.email"), see WithMaskedColumns
+	MaskedColumns []string `json:"masked_columns,omitempty" yaml:"masked_columns,omitempty"`
+	// SecurityDetection logs the SQL queries suspicious of a SQL injection, see WithSecurityDetection
+	SecurityDetection bool `json:"security_detection,omitempty" yaml:"security_detection,omitempty"`
+	// DangerousWriteDetection logs the UPDATE and DELETE statements without WHERE clause, see WithDangerousWriteDetection
+	DangerousWriteDetection bool `json:"dangerous_write_detection,omitempty" yaml:"dangerous_write_detection,omitempty"`
+	// DebugFullQueries logs the full SQL queries in companion records, see WithDebugFullQueries
+	DebugFullQueries bool `json:"debug_full_queries,omitempty" yaml:"debug_full_queries,omitempty"`
+	// ReturningRedaction strips or masks the RETURNING clauses (e.g. "masked"), see WithReturningRedaction
+	ReturningRedaction ReturningRedaction `json:"returning_redaction,omitempty" yaml:"returning_redaction,omitempty"`
+	// WithoutComments removes the comments from the SQL queries logged, see WithoutComments
+	WithoutComments bool `json:"without_comments,omitempty" yaml:"without_comments,omitempty"`
+	// DecisionDebug logs why the SQL queries are not logged, see WithDecisionDebug
+	DecisionDebug bool `json:"decision_debug,omitempty" yaml:"decision_debug,omitempty"`
+
+	// QueryBytes logs the byte length of the SQL queries, see WithQueryBytes
+	QueryBytes bool `json:"query_bytes,omitempty" yaml:"query_bytes,omitempty"`
+	// MaxQueryBytesWarn logs the SQL queries longer than the given number of bytes, see WithMaxQueryBytesWarn
+	MaxQueryBytesWarn int `json:"max_query_bytes_warn,omitempty" yaml:"max_query_bytes_warn,omitempty"`
+	// LargeResultThreshold logs the SQL queries returning more rows than the threshold, see WithLargeResultThreshold
+	LargeResultThreshold int64 `json:"large_result_threshold,omitempty" yaml:"large_result_threshold,omitempty"`
+	// Tables logs all the tables of the SQL queries, see WithTables
+	Tables bool `json:"tables,omitempty" yaml:"tables,omitempty"`
+	// ShardedTables logs the shards of the given tables, see WithShardedTables
+	ShardedTables []string `json:"sharded_tables,omitempty" yaml:"sharded_tables,omitempty"`
+	// SoftDeleteFlag flags the statements of the models with soft deletes, see WithSoftDeleteFlag
+	SoftDeleteFlag bool `json:"soft_delete_flag,omitempty" yaml:"soft_delete_flag,omitempty"`
+	// BatchMetrics logs the size of the batch inserts and their duration per row, see WithBatchMetrics
+	BatchMetrics bool `json:"batch_metrics,omitempty" yaml:"batch_metrics,omitempty"`
+	// BindParamsCount logs the number of bind parameters of the SQL queries, see WithBindParamsCount
+	BindParamsCount bool `json:"bind_params_count,omitempty" yaml:"bind_params_count,omitempty"`
+	// GoroutineID logs the ID of the goroutine executing the SQL queries, see WithGoroutineID
+	GoroutineID bool `json:"goroutine_id,omitempty" yaml:"goroutine_id,omitempty"`
+	// PprofLabels sets the pprof labels of the SQL queries executed, see WithPprofLabels
+	PprofLabels bool `json:"pprof_labels,omitempty" yaml:"pprof_labels,omitempty"`
+	// WithoutSpanStatement drops the SQL query from the records linked to a span, see WithoutSpanStatement
+	WithoutSpanStatement bool `json:"without_span_statement,omitempty" yaml:"without_span_statement,omitempty"`
+
+	// MinLevel is the minimum level of the records logged, checked before the handler
+	MinLevel *slog.Level `json:"min_level,omitempty" yaml:"min_level,omitempty"`
+	// Levels overrides the slog.Level of the given log types
+	Levels map[LogType]slog.Level `json:"levels,omitempty" yaml:"levels,omitempty"`
+	// Messages defines the templates of the messages of the given log types, see WithMessage
+	Messages map[LogType]string `json:"messages,omitempty" yaml:"messages,omitempty"`
+	// SilencedLogTypes turns off the records of the given log types, see WithSilencedLogTypes
+	SilencedLogTypes []LogType `json:"silenced_log_types,omitempty" yaml:"silenced_log_types,omitempty"`
+
+	// StatsSummary is the interval of the summary of the records suppressed or failed (disabled with zero), see WithStatsSummary
+	StatsSummary time.Duration `json:"stats_summary,omitempty" yaml:"stats_summary,omitempty"`
+	// QueryStats aggregates the queries by fingerprint, see WithQueryStats
+	QueryStats bool `json:"query_stats,omitempty" yaml:"query_stats,omitempty"`
+	// RouteStats aggregates the queries by the route of the context attribute of the given name, see WithRouteStats
+	RouteStats string `json:"route_stats,omitempty" yaml:"route_stats,omitempty"`
+	// JobStats aggregates the queries by the job of the context attribute of the given name, see WithJobStats
+	JobStats string `json:"job_stats,omitempty" yaml:"job_stats,omitempty"`
+
+	// ErrorEscalationThreshold and ErrorEscalationWindow escalate the SQL errors repeated within the window,
+	// see WithErrorEscalation
+	ErrorEscalationThreshold int           `json:"error_escalation_threshold,omitempty" yaml:"error_escalation_threshold,omitempty"`
+	ErrorEscalationWindow    time.Duration `json:"error_escalation_window,omitempty" yaml:"error_escalation_window,omitempty"`
+
+	// SourceField is the field of the file name and line number (SourceField by default)
+	SourceField string `json:"source_field,omitempty" yaml:"source_field,omitempty"`
+	// SourceCacheSize is the number of sources cached, see WithSourceCacheSize
+	SourceCacheSize int `json:"source_cache_size,omitempty" yaml:"source_cache_size,omitempty"`
+	// WithoutSourceField disables the field of the file name and line number
+	WithoutSourceField bool `json:"without_source_field,omitempty" yaml:"without_source_field,omitempty"`
+	// CallerFunction adds the package and the function calling gorm, see WithCallerFunction
+	CallerFunction bool `json:"caller_function,omitempty" yaml:"caller_function,omitempty"`
+	// ErrorField is the field of the error (ErrorField by default)
+	ErrorField string `json:"error_field,omitempty" yaml:"error_field,omitempty"`
+	// KeyCase is the casing convention of the keys (e.g. "camel"), see WithKeyCase
+	KeyCase KeyCase `json:"key_case,omitempty" yaml:"key_case,omitempty"`
+	// Preset is the name of the field preset naming the attributes (e.g. "otel"), see WithPreset
+	Preset string `json:"preset,omitempty" yaml:"preset,omitempty"`
+
+	// IgnoredOperations ignores the queries with the given operations, except the errors
+	IgnoredOperations []string `json:"ignored_operations,omitempty" yaml:"ignored_operations,omitempty"`
+	// IgnoredTables ignores the queries on the given tables, except the errors
+	IgnoredTables []string `json:"ignored_tables,omitempty" yaml:"ignored_tables,omitempty"`
+	// IgnoredQueries ignores the queries matching one of the given regular expressions, except the errors
+	IgnoredQueries []string `json:"ignored_queries,omitempty" yaml:"ignored_queries,omitempty"`
+
+	// SamplingRate is the rate of the SQL messages traced which are logged, between 0 and 1 (all by default)
+	SamplingRate float64 `json:"sampling_rate,omitempty" yaml:"sampling_rate,omitempty"`
+	// SamplingRates are the sampling rates of the given log types of the SQL queries, see WithSamplingRates
+	SamplingRates map[LogType]float64 `json:"sampling_rates,omitempty" yaml:"sampling_rates,omitempty"`
+	// TenantSamplingRates are the sampling rates of the SQL messages traced of the given tenants, see WithTenantSamplingRates
+	TenantSamplingRates map[string]float64 `json:"tenant_sampling_rates,omitempty" yaml:"tenant_sampling_rates,omitempty"`
+	// VolumeBudget is the maximum number of SQL records logged per minute, the errors excepted (no budget by default)
+	VolumeBudget int `json:"volume_budget,omitempty" yaml:"volume_budget,omitempty"`
+
+	// AsyncBufferSize enables the asynchronous mode with a buffer of the given size
+	AsyncBufferSize int `json:"async_buffer_size,omitempty" yaml:"async_buffer_size,omitempty"`
+	// AsyncDropPolicy defines the behavior of the asynchronous mode when its buffer is full
+	AsyncDropPolicy DropPolicy `json:"async_drop_policy,omitempty" yaml:"async_drop_policy,omitempty"`
+
+	// explicitZeros are the fields explicitly set to zero, applied instead of keeping the defaults
+	explicitZeros map[string]bool
+}
+
+// NewWithConfig creates a new logger for gorm.io/gorm from the given configuration.
+// The options are applied after the configuration.
+func NewWithConfig(config Config, options ...Option) Logger {
+	return New(append(config.options(), options...)...)
+}
+
+// NewFromGormConfig creates a new logger for gorm.io/gorm equivalent to the default logger of gorm
+// created with the given configuration, easing the migration from it. The Colorful option is ignored.
+// The options are applied after the configuration.
+//
+// Usage:
+//
+//	gormLogger := slogGorm.NewFromGormConfig(handler, gormlogger.Config{
+//		SlowThreshold:             200 * time.Millisecond,
+//		IgnoreRecordNotFoundError: true,
+//		LogLevel:                  gormlogger.Warn,
+//	})
+func NewFromGormConfig(handler slog.Handler, config gormlogger.Config, options ...Option) Logger {
+	gormOptions := []Option{WithHandler(handler)}
+
+	switch config.LogLevel {
+	case gormlogger.Silent:
+		gormOptions = append(gormOptions, WithIgnoreTrace())
+	case gormlogger.Error:
+		// Only the errors are logged, not the slow queries
+		config.SlowThreshold = 0
+	case gormlogger.Info:
+		gormOptions = append(gormOptions, WithTraceAll())
+	}
+	if config.SlowThreshold > 0 {
+		gormOptions = append(gormOptions, WithSlowThreshold(config.SlowThreshold))
+	}
+	if !config.IgnoreRecordNotFoundError {
+		gormOptions = append(gormOptions, WithRecordNotFoundError())
+	}
+	if config.ParameterizedQueries {
+		gormOptions = append(gormOptions, WithParameterizedQueries())
+	}
+
+	return New(append(gormOptions, options...)...)
+}
+
+// options returns the options equivalent to the configuration
+func (c Config) options() []Option {
+	var options []Option
+
+	if c.Handler != nil {
+		options = append(options, WithHandler(c.Handler))
+	}
+	if c.Name != "" {
+		options = append(options, WithName(c.Name))
+	}
+	if c.SlowThreshold != 0 || c.explicitZeros["slow_threshold"] {
+		options = append(options, WithSlowThreshold(c.SlowThreshold))
+	}
+	if c.TransactionWatchdog != 0 {
+		options = append(options, WithTransactionWatchdog(c.TransactionWatchdog))
+	}
+	if c.BurstCapture != 0 {
+		options = append(options, WithBurstCapture(c.BurstCapture))
+	}
+	if c.TraceAll {
+		options = append(options, WithTraceAll())
+	}
+	if c.IgnoreTrace {
+		options = append(options, WithIgnoreTrace())
+	}
+	if c.RecordNotFoundError {
+		options = append(options, WithRecordNotFoundError())
+	}
+	if c.ParameterizedQueries {
+		options = append(options, WithParameterizedQueries())
+	}
+	if c.StaticMessages {
+		options = append(options, WithStaticMessages())
+	}
+	if c.MetadataMode != MetadataBoth {
+		options = append(options, WithMetadataMode(c.MetadataMode))
+	}
+	if c.DebugFullQueries {
+		options = append(options, WithDebugFullQueries())
+	}
+	if c.ReturningRedaction != ReturningKept {
+		options = append(options, WithReturningRedaction(c.ReturningRedaction))
+	}
+	if c.WithoutComments {
+		options = append(options, WithoutComments())
+	}
+	if c.DecisionDebug {
+		options = append(options, WithDecisionDebug())
+	}
+	if c.QueryBytes {
+		options = append(options, WithQueryBytes())
+	}
+	if c.MaxQueryBytesWarn != 0 {
+		options = append(options, WithMaxQueryBytesWarn(c.MaxQueryBytesWarn))
+	}
+	if c.LargeResultThreshold != 0 {
+		options = append(options, WithLargeResultThreshold(c.LargeResultThreshold))
+	}
+	if c.Tables {
+		options = append(options, WithTables())
+	}
+	if len(c.ShardedTables) > 0 {
+		options = append(options, WithShardedTables(c.ShardedTables...))
+	}
+	if c.SoftDeleteFlag {
+		options = append(options, WithSoftDeleteFlag())
+	}
+	if c.BatchMetrics {
+		options = append(options, WithBatchMetrics())
+	}
+	if c.BindParamsCount {
+		options = append(options, WithBindParamsCount())
+	}
+	if c.GoroutineID {
+		options = append(options, WithGoroutineID())
+	}
+	if c.PprofLabels {
+		options = append(options, WithPprofLabels())
+	}
+	if c.WithoutSpanStatement {
+		options = append(options, WithoutSpanStatement())
+	}
+	if c.StatsSummary != 0 {
+		options = append(options, WithStatsSummary(c.StatsSummary))
+	}
+	if c.QueryStats {
+		options = append(options, WithQueryStats())
+	}
+	if c.RouteStats != "" {
+		options = append(options, WithRouteStats(c.RouteStats))
+	}
+	if c.JobStats != "" {
+		options = append(options, WithJobStats(c.JobStats))
+	}
+	if c.ErrorEscalationThreshold != 0 || c.ErrorEscalationWindow != 0 {
+		options = append(options, WithErrorEscalation(c.ErrorEscalationThreshold, c.ErrorEscalationWindow))
+	}
+	if c.NoValues {
+		options = append(options, WithNoValues(c.AllowedContextAttrs...))
+	}
+	if c.DangerousWriteDetection {
+		options = append(options, WithDangerousWriteDetection())
+	}
+	if c.SecurityDetection {
+		options = append(options, WithSecurityDetection())
+	}
+	if len(c.MaskedColumns) > 0 {
+		options = append(options, WithMaskedColumns(c.MaskedColumns...))
+	}
+	if c.MinLevel != nil {
+		options = append(options, WithMinLevel(*c.MinLevel))
+	}
+	for logType, level := range c.Levels {
+		options = append(options, SetLogLevel(logType, level))
+	}
+	for logType, template := range c.Messages {
+		options = append(options, WithMessage(logType, template))
+	}
+	if len(c.SilencedLogTypes) > 0 {
+		options = append(options, WithSilencedLogTypes(c.SilencedLogTypes...))
+	}
+	if c.SourceField != "" {
+		options = append(options, WithSourceField(c.SourceField))
+	}
+	if c.SourceCacheSize != 0 {
+		options = append(options, WithSourceCacheSize(c.SourceCacheSize))
+	}
+	if c.WithoutSourceField {
+		options = append(options, WithoutSourceField())
+	}
+	if c.CallerFunction {
+		options = append(options, WithCallerFunction())
+	}
+	if c.ErrorField != "" {
+		options = append(options, WithErrorField(c.ErrorField))
+	}
+	if c.KeyCase != OriginalCase {
+		options = append(options, WithKeyCase(c.KeyCase))
+	}
+	if c.Preset != "" {
+		options = append(options, WithPreset(c.Preset))
+	}
+	if len(c.IgnoredOperations) > 0 {
+		options = append(options, WithIgnoredOperations(c.IgnoredOperations...))
+	}
+	if len(c.IgnoredTables) > 0 {
+		options = append(options, WithIgnoredTables(c.IgnoredTables...))
+	}
+	for _, expr := range c.IgnoredQueries {
+		options = append(options, withIgnoredQueryExpr(expr))
+	}
+	if c.SamplingRate != 0 || c.explicitZeros["sampling_rate"] {
+		options = append(options, WithSamplingRate(c.SamplingRate))
+	}
+	if len(c.SamplingRates) > 0 {
+		options = append(options, WithSamplingRates(c.SamplingRates))
+	}
+	if len(c.TenantSamplingRates) > 0 {
+		options = append(options, WithTenantSamplingRates(c.TenantSamplingRates))
+	}
+	if c.VolumeBudget != 0 || c.explicitZeros["volume_budget"] {
+		options = append(options, WithVolumeBudget(c.VolumeBudget))
+	}
+	if c.AsyncBufferSize != 0 || c.explicitZeros["async_buffer_size"] {
+		options = append(options, WithAsync(c.AsyncBufferSize), WithAsyncDropPolicy(c.AsyncDropPolicy))
+	}
+
+	return options
+}
+
+// withIgnoredQueryExpr ignores the queries matching the given regular expression,
+// or reports the regular expression as an invalid option
+func withIgnoredQueryExpr(expr string) Option {
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return func(l *logger) {
+			l.invalidOption("ignored query %q: %w", expr, err)
+		}
+	}
+	return WithIgnoredQueries(pattern)
+}
+
+// NewFromConfigFile creates a new logger for gorm.io/gorm from the configuration file at the
+// given path, a JSON (.json) or YAML (.yaml, .yml) encoded Config. The durations are written
+// like "500ms". The options are applied after the configuration, e.g. to define the handler.
+// It returns an error wrapping ErrInvalidOption for each invalid setting.
+func NewFromConfigFile(path string, options ...Option) (Logger, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	default:
+		return nil, fmt.Errorf("slog-gorm: unsupported configuration file format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("slog-gorm: configuration file %s: %w", path, err)
+	}
+
+	return NewE(append(config.options(), options...)...)
+}
+
+// Config returns a snapshot of the effective configuration of the logger, e.g. to log it at startup
+// or to expose it in a debug endpoint. The functions given as options (WithQueryFilter, WithFieldPreset...)
+// cannot be described and are not included, nor the handlers, the writers, the files, the sinks, the tracer
+// and the clock (WithAudit, WithSlowLog, WithQueryLogFile, WithSink, WithClock...), the context values, the
+// slow thresholds per context attribute, the table and model policies, the resource, the build information
+// and the conflicts of the context attributes, which are only set with their options.
+func (l logger) Config() Config {
+	l = l.snapshot()
+
+	config := Config{
+		Handler:                  l.sloggerHandler,
+		Name:                     l.name,
+		SlowThreshold:            l.slowThreshold,
+		TransactionWatchdog:      l.txWatchdogThreshold,
+		BurstCapture:             l.burstWindow,
+		TraceAll:                 l.traceAll,
+		IgnoreTrace:              l.ignoreTrace,
+		RecordNotFoundError:      !l.ignoreRecordNotFoundError,
+		ParameterizedQueries:     l.parameterizedQueries,
+		StaticMessages:           l.staticMessages,
+		MetadataMode:             l.metadataMode,
+		DebugFullQueries:         l.debugFullQueries,
+		ReturningRedaction:       l.returningRedaction,
+		WithoutComments:          l.withoutComments,
+		DecisionDebug:            l.decisionDebug,
+		QueryBytes:               l.queryBytes,
+		MaxQueryBytesWarn:        l.maxQueryBytes,
+		LargeResultThreshold:     l.largeResultThreshold,
+		Tables:                   l.tables,
+		ShardedTables:            slices.Clone(l.shardedTables),
+		SoftDeleteFlag:           l.softDeleteFlag,
+		BatchMetrics:             l.batchMetrics,
+		BindParamsCount:          l.bindParamsCount,
+		GoroutineID:              l.goroutineID,
+		PprofLabels:              l.pprofLabels,
+		WithoutSpanStatement:     l.withoutSpanStatement,
+		QueryStats:               l.queryStats != nil,
+		ErrorEscalationThreshold: l.escalationThreshold,
+		ErrorEscalationWindow:    l.escalationWindow,
+		SecurityDetection:        l.securityDetection,
+		DangerousWriteDetection:  l.dangerousWriteDetection,
+		NoValues:                 l.noValues,
+		AllowedContextAttrs:      slices.Clone(l.allowedContextAttrs),
+		Levels:                   maps.Clone(l.logLevel),
+		SilencedLogTypes:         slices.Clone(l.silencedLogTypes),
+		SourceField:              l.sourceField,
+		SourceCacheSize:          l.sourceCacheSize,
+		WithoutSourceField:       l.sourceField == "",
+		CallerFunction:           l.callerFunction,
+		ErrorField:               l.errorField,
+		KeyCase:                  l.keyCase,
+		Preset:                   l.presetName,
+		SamplingRate:             l.samplingRate,
+		SamplingRates:            maps.Clone(l.logTypeSamplingRates),
+		TenantSamplingRates:      maps.Clone(l.tenantSamplingRates),
+		VolumeBudget:             l.volumeBudget,
+		AsyncBufferSize:          l.asyncBufferSize,
+		AsyncDropPolicy:          l.asyncDropPolicy,
+	}
+	if l.statsSummary != nil {
+		config.StatsSummary = l.statsSummary.interval
+	}
+	if l.routeStats != nil {
+		config.RouteStats = l.routeStats.attr
+	}
+	if l.jobStats != nil {
+		config.JobStats = l.jobStats.attr
+	}
+	for _, column := range l.maskedColumns {
+		config.MaskedColumns = append(config.MaskedColumns, column.name)
+	}
+	if l.minLevel != nil {
+		level := l.minLevel.Level()
+		config.MinLevel = &level
+	}
+	for logType, message := range l.messages {
+		if message.template == "" {
+			continue
+		}
+		if config.Messages == nil {
+			config.Messages = make(map[LogType]string)
+		}
+		config.Messages[logType] = message.template
+	}
+	for _, filter := range l.filters {
+		if filter.describe != nil {
+			filter.describe(&config)
+		}
+	}
+	config.markZero("sampling_rate")
+	return config
+}
+
+// markZero records whether the given field, set in a configuration file or an environment variable, is set to zero
+func (c *Config) markZero(field string) {
+	var zero bool
+	switch field {
+	case "slow_threshold":
+		zero = c.SlowThreshold == 0
+	case "sampling_rate":
+		zero = c.SamplingRate == 0
+	case "volume_budget":
+		zero = c.VolumeBudget == 0
+	case "async_buffer_size":
+		zero = c.AsyncBufferSize == 0
+	default:
+		return
+	}
+
+	if !zero {
+		delete(c.explicitZeros, field)
+		return
+	}
+	if c.explicitZeros == nil {
+		c.explicitZeros = make(map[string]bool)
+	}
+	c.explicitZeros[field] = true
+}
+
+// MarshalJSON implements json.Marshaler, encoding the durations like "500ms"
+func (c Config) MarshalJSON() ([]byte, error) {
+	type config Config
+	aux := struct {
+		config
+		SlowThreshold         string   `json:"slow_threshold,omitempty"`
+		TransactionWatchdog   string   `json:"transaction_watchdog,omitempty"`
+		BurstCapture          string   `json:"burst_capture,omitempty"`
+		ErrorEscalationWindow string   `json:"error_escalation_window,omitempty"`
+		StatsSummary          string   `json:"stats_summary,omitempty"`
+		SamplingRate          *float64 `json:"sampling_rate,omitempty"`
+		VolumeBudget          *int     `json:"volume_budget,omitempty"`
+		AsyncBufferSize       *int     `json:"async_buffer_size,omitempty"`
+	}{config: config(c)}
+	if c.SlowThreshold != 0 || c.explicitZeros["slow_threshold"] {
+		aux.SlowThreshold = c.SlowThreshold.String()
+	}
+	if c.TransactionWatchdog != 0 {
+		aux.TransactionWatchdog = c.TransactionWatchdog.String()
+	}
+	if c.BurstCapture != 0 {
+		aux.BurstCapture = c.BurstCapture.String()
+	}
+	if c.ErrorEscalationWindow != 0 {
+		aux.ErrorEscalationWindow = c.ErrorEscalationWindow.String()
+	}
+	if c.StatsSummary != 0 {
+		aux.StatsSummary = c.StatsSummary.String()
+	}
+	if c.SamplingRate != 0 || c.explicitZeros["sampling_rate"] {
+		aux.SamplingRate = &c.SamplingRate
+	}
+	if c.VolumeBudget != 0 || c.explicitZeros["volume_budget"] {
+		aux.VolumeBudget = &c.VolumeBudget
+	}
+	if c.AsyncBufferSize != 0 || c.explicitZeros["async_buffer_size"] {
+		aux.AsyncBufferSize = &c.AsyncBufferSize
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the durations written like "500ms"
+// or as a number of nanoseconds.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type config Config
+	aux := struct {
+		*config
+		SlowThreshold         jsonDuration `json:"slow_threshold,omitempty"`
+		TransactionWatchdog   jsonDuration `json:"transaction_watchdog,omitempty"`
+		BurstCapture          jsonDuration `json:"burst_capture,omitempty"`
+		ErrorEscalationWindow jsonDuration `json:"error_escalation_window,omitempty"`
+		StatsSummary          jsonDuration `json:"stats_summary,omitempty"`
+	}{
+		config:                (*config)(c),
+		SlowThreshold:         jsonDuration(c.SlowThreshold),
+		TransactionWatchdog:   jsonDuration(c.TransactionWatchdog),
+		BurstCapture:          jsonDuration(c.BurstCapture),
+		ErrorEscalationWindow: jsonDuration(c.ErrorEscalationWindow),
+		StatsSummary:          jsonDuration(c.StatsSummary),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.SlowThreshold = time.Duration(aux.SlowThreshold)
+	c.TransactionWatchdog = time.Duration(aux.TransactionWatchdog)
+	c.BurstCapture = time.Duration(aux.BurstCapture)
+	c.ErrorEscalationWindow = time.Duration(aux.ErrorEscalationWindow)
+	c.StatsSummary = time.Duration(aux.StatsSummary)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for field := range fields {
+		c.markZero(field)
+	}
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, recording the fields explicitly set to zero
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	type config Config
+	if err := value.Decode((*config)(c)); err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		c.markZero(value.Content[i].Value)
+	}
+	return nil
+}
+
+// jsonDuration is a time.Duration decoded from a string like "500ms" or a number of nanoseconds
+type jsonDuration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case float64:
+		*d = jsonDuration(value)
+	case string:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*d = jsonDuration(duration)
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+package slogGorm
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestNewWithConfig(t *testing.T) {
+	t.Run("Zero configuration", func(t *testing.T) {
+		assert.Equal(t, New(), NewWithConfig(Config{}))
+	})
+
+	t.Run("Configuration", func(t *testing.T) {
+		handler := NewDummyHandler()
+		l := NewWithConfig(Config{
+			Handler:              handler,
+			SlowThreshold:        time.Second,
+			TransactionWatchdog:  time.Minute,
+			TraceAll:             true,
+			RecordNotFoundError:  true,
+			StaticMessages:       true,
+			ParameterizedQueries: true,
+			Levels:               map[LogType]slog.Level{DefaultLogType: slog.LevelDebug},
+			SourceField:          "source",
+			ErrorField:           "err",
+			IgnoredOperations:    []string{"SELECT"},
+			IgnoredTables:        []string{"sessions"},
+		}).(*logger)
+
+		assert.Equal(t, handler, l.sloggerHandler)
+		assert.Equal(t, time.Second, l.slowThreshold)
+		assert.Equal(t, time.Minute, l.txWatchdogThreshold)
+		assert.True(t, l.traceAll)
+		assert.False(t, l.ignoreRecordNotFoundError)
+		assert.True(t, l.staticMessages)
+		assert.True(t, l.parameterizedQueries)
+		assert.Equal(t, slog.LevelDebug, l.logLevel[DefaultLogType])
+		assert.Equal(t, slog.LevelError, l.logLevel[ErrorLogType])
+		assert.Equal(t, "source", l.sourceField)
+		assert.Equal(t, "err", l.errorField)
+		assert.Len(t, l.filters, 2)
+		assert.Nil(t, l.async)
+	})
+
+	t.Run("Asynchronous configuration", func(t *testing.T) {
+		l := NewWithConfig(Config{
+			AsyncBufferSize:    16,
+			AsyncDropPolicy:    DropNewest,
+			WithoutSourceField: true,
+			IgnoreTrace:        true,
+		}).(*logger)
+		require.NotNil(t, l.async)
+		require.NoError(t, l.Close())
+		assert.Equal(t, DropNewest, l.asyncDropPolicy)
+		assert.Equal(t, "", l.sourceField)
+		assert.True(t, l.ignoreTrace)
+	})
+
+	t.Run("Options applied after the configuration", func(t *testing.T) {
+		l := NewWithConfig(Config{SlowThreshold: time.Second}, WithSlowThreshold(time.Minute)).(*logger)
+
+		assert.Equal(t, time.Minute, l.slowThreshold)
+	})
+
+	t.Run("Negative values", func(t *testing.T) {
+		l, err := NewE(Config{
+			SlowThreshold:       -time.Second,
+			TransactionWatchdog: -time.Minute,
+			BurstCapture:        -5 * time.Second,
+			SamplingRate:        -0.5,
+			VolumeBudget:        -3,
+			AsyncBufferSize:     -3,
+		}.options()...)
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.ErrorContains(t, err, "negative slow threshold")
+		assert.ErrorContains(t, err, "negative transaction watchdog threshold -1m0s")
+		assert.ErrorContains(t, err, "non-positive burst capture window -5s")
+		assert.ErrorContains(t, err, "sampling rate -0.5")
+		assert.ErrorContains(t, err, "non-positive volume budget")
+		assert.ErrorContains(t, err, "negative asynchronous buffer size")
+	})
+}
+
+func TestNewFromGormConfig(t *testing.T) {
+	handler := NewDummyHandler()
+
+	tests := []struct {
+		name   string
+		config gormlogger.Config
+		check  func(t *testing.T, l *logger)
+	}{
+		{
+			name:   "Default configuration of gorm",
+			config: gormlogger.Config{SlowThreshold: 200 * time.Millisecond, IgnoreRecordNotFoundError: true, LogLevel: gormlogger.Warn, Colorful: true},
+			check: func(t *testing.T, l *logger) {
+				assert.Equal(t, 200*time.Millisecond, l.slowThreshold)
+				assert.True(t, l.ignoreRecordNotFoundError)
+				assert.False(t, l.traceAll)
+				assert.False(t, l.ignoreTrace)
+				assert.False(t, l.parameterizedQueries)
+			},
+		},
+		{
+			name:   "Silent",
+			config: gormlogger.Config{LogLevel: gormlogger.Silent},
+			check: func(t *testing.T, l *logger) {
+				assert.True(t, l.ignoreTrace)
+				assert.False(t, l.ignoreRecordNotFoundError)
+			},
+		},
+		{
+			name:   "Only errors",
+			config: gormlogger.Config{SlowThreshold: time.Second, LogLevel: gormlogger.Error},
+			check: func(t *testing.T, l *logger) {
+				assert.Zero(t, l.slowThreshold)
+			},
+		},
+		{
+			name:   "All SQL messages without parameters",
+			config: gormlogger.Config{LogLevel: gormlogger.Info, ParameterizedQueries: true},
+			check: func(t *testing.T, l *logger) {
+				assert.True(t, l.traceAll)
+				assert.True(t, l.parameterizedQueries)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewFromGormConfig(handler, tt.config).(*logger)
+
+			assert.Equal(t, handler, l.sloggerHandler)
+			tt.check(t, l)
+		})
+	}
+}
+
+func TestNewFromConfigFile(t *testing.T) {
+	writeFile := func(t *testing.T, name, content string) string {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	expected := func(t *testing.T, l *logger) {
+		assert.Equal(t, 500*time.Millisecond, l.slowThreshold)
+		assert.True(t, l.traceAll)
+		assert.Equal(t, slog.LevelDebug, l.logLevel[DefaultLogType])
+		assert.Equal(t, slog.LevelError, l.logLevel[SlowQueryLogType])
+		assert.Len(t, l.filters, 2)
+		assert.Equal(t, 0.1, l.samplingRate)
+		assert.Equal(t, DropOldest, l.asyncDropPolicy)
+		require.NoError(t, l.Close())
+	}
+
+	t.Run("YAML", func(t *testing.T) {
+		path := writeFile(t, "logger.yaml", `
+slow_threshold: 500ms
+trace_all: true
+levels:
+  default: DEBUG
+  slow_query: ERROR
+ignored_tables: [sessions]
+ignored_queries:
+  - ^SELECT 1$
+sampling_rate: 0.1
+async_buffer_size: 16
+async_drop_policy: drop_oldest
+`)
+
+		l, err := NewFromConfigFile(path)
+		require.NoError(t, err)
+		expected(t, l.(*logger))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		path := writeFile(t, "logger.json", `{
+	"slow_threshold": "500ms",
+	"trace_all": true,
+	"levels": {"default": "DEBUG", "slow_query": "ERROR"},
+	"ignored_tables": ["sessions"],
+	"ignored_queries": ["^SELECT 1$"],
+	"sampling_rate": 0.1,
+	"async_buffer_size": 16,
+	"async_drop_policy": "drop_oldest"
+}`)
+
+		l, err := NewFromConfigFile(path)
+		require.NoError(t, err)
+		expected(t, l.(*logger))
+	})
+
+	t.Run("Options applied after the configuration", func(t *testing.T) {
+		handler := NewDummyHandler()
+		path := writeFile(t, "logger.yml", "trace_all: true\n")
+
+		l, err := NewFromConfigFile(path, WithHandler(handler))
+		require.NoError(t, err)
+		assert.True(t, l.(*logger).traceAll)
+		assert.Equal(t, handler, l.(*logger).sloggerHandler)
+	})
+
+	t.Run("Invalid settings", func(t *testing.T) {
+		path := writeFile(t, "logger.yaml", "ignored_queries: ['(']\nsampling_rate: 2\n")
+
+		l, err := NewFromConfigFile(path)
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.Contains(t, err.Error(), "ignored query")
+		assert.Contains(t, err.Error(), "sampling rate")
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"logger.yaml": "slow_threshold: -1s\ntransaction_watchdog: -1m\nburst_capture: -5s\nsampling_rate: -0.5\nvolume_budget: -3\nasync_buffer_size: -3\n",
+			"logger.json": `{"slow_threshold": "-1s", "transaction_watchdog": "-1m", "burst_capture": "-5s", "sampling_rate": -0.5, "volume_budget": -3, "async_buffer_size": -3}`,
+		} {
+			l, err := NewFromConfigFile(writeFile(t, name, content))
+			assert.Nil(t, l, name)
+			assert.ErrorIs(t, err, ErrInvalidOption, name)
+			assert.ErrorContains(t, err, "negative slow threshold", name)
+			assert.ErrorContains(t, err, "negative transaction watchdog threshold", name)
+			assert.ErrorContains(t, err, "non-positive burst capture window", name)
+			assert.ErrorContains(t, err, "sampling rate -0.5", name)
+			assert.ErrorContains(t, err, "non-positive volume budget", name)
+			assert.ErrorContains(t, err, "negative asynchronous buffer size", name)
+		}
+	})
+
+	t.Run("Zero values", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"logger.yaml": "slow_threshold: 0s\nsampling_rate: 0\nasync_buffer_size: 0\n",
+			"logger.json": `{"slow_threshold": 0, "sampling_rate": 0, "async_buffer_size": 0}`,
+		} {
+			l, err := NewFromConfigFile(writeFile(t, name, content))
+			require.NoError(t, err, name)
+			assert.Zero(t, l.(*logger).slowThreshold, name)
+			assert.Zero(t, l.(*logger).samplingRate, name)
+			assert.Nil(t, l.(*logger).async, name)
+		}
+
+		for name, content := range map[string]string{
+			"logger.yaml": "volume_budget: 0\n",
+			"logger.json": `{"volume_budget": 0}`,
+		} {
+			_, err := NewFromConfigFile(writeFile(t, name, content))
+			assert.ErrorIs(t, err, ErrInvalidOption, name)
+			assert.ErrorContains(t, err, "non-positive volume budget 0", name)
+		}
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		_, err := NewFromConfigFile(writeFile(t, "logger.json", `{"slow_threshold": "fast"}`))
+		assert.Error(t, err)
+
+		_, err = NewFromConfigFile(writeFile(t, "logger.toml", ""))
+		assert.ErrorContains(t, err, "unsupported configuration file format")
+
+		_, err = NewFromConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestConfig_UnmarshalJSON(t *testing.T) {
+	var config Config
+	require.NoError(t, json.Unmarshal([]byte(`{"slow_threshold": 1000000, "transaction_watchdog": "1m", "burst_capture": "30s", "error_field": "err"}`), &config))
+
+	assert.Equal(t, Config{
+		SlowThreshold:       time.Millisecond,
+		TransactionWatchdog: time.Minute,
+		BurstCapture:        30 * time.Second,
+		ErrorField:          "err",
+	}, config)
+}
+
+func Test_logger_Config(t *testing.T) {
+	handler := NewDummyHandler()
+	l := New(
+		WithHandler(handler),
+		WithSlowThreshold(500*time.Millisecond),
+		WithTraceAll(),
+		SetLogLevel(DefaultLogType, slog.LevelDebug),
+		WithIgnoredOperations("SELECT"),
+		WithIgnoredTables("sessions", "schema_migrations"),
+		WithIgnoredQueries(regexp.MustCompile(`^SELECT 1$`)),
+		WithQueryFilter(func(ctx context.Context, query QueryInfo) bool { return true }),
+		WithSamplingRate(0.5),
+		WithMaskedColumns("users.email"),
+		WithMinLevel(slog.LevelWarn),
+		WithMessage(SlowQueryLogType, "slow query on {table}"),
+		WithMessageFunc(DefaultLogType, func(ctx context.Context, info MessageInfo) string { return info.SQL }),
+	).(*logger)
+
+	config := l.Config()
+	assert.Equal(t, handler, config.Handler)
+	assert.Equal(t, 500*time.Millisecond, config.SlowThreshold)
+	assert.True(t, config.TraceAll)
+	assert.False(t, config.RecordNotFoundError)
+	assert.Equal(t, slog.LevelDebug, config.Levels[DefaultLogType])
+	assert.Equal(t, slog.LevelError, config.Levels[ErrorLogType])
+	assert.Equal(t, SourceField, config.SourceField)
+	assert.Equal(t, ErrorField, config.ErrorField)
+	assert.Equal(t, []string{"SELECT"}, config.IgnoredOperations)
+	assert.Equal(t, []string{"sessions", "schema_migrations"}, config.IgnoredTables)
+	assert.Equal(t, []string{`^SELECT 1$`}, config.IgnoredQueries)
+	assert.Equal(t, 0.5, config.SamplingRate)
+	assert.Equal(t, []string{"users.email"}, config.MaskedColumns)
+	require.NotNil(t, config.MinLevel)
+	assert.Equal(t, slog.LevelWarn, *config.MinLevel)
+	assert.Equal(t, map[LogType]string{SlowQueryLogType: "slow query on {table}"}, config.Messages)
+
+	// The snapshot is not shared with the logger
+	config.Levels[DefaultLogType] = slog.LevelError
+	assert.Equal(t, slog.LevelDebug, l.logLevel[DefaultLogType])
+
+	// The snapshot creates an equivalent logger
+	assert.Equal(t, l.Config(), NewWithConfig(l.Config()).Config())
+
+	// The snapshot follows the runtime reconfiguration
+	l.Apply(WithoutSourceField())
+	assert.True(t, l.Config().WithoutSourceField)
+}
+
+func TestConfig_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Config{
+		Handler:         NewDummyHandler(),
+		SlowThreshold:   500 * time.Millisecond,
+		TraceAll:        true,
+		Levels:          map[LogType]slog.Level{DefaultLogType: slog.LevelDebug},
+		AsyncBufferSize: 16,
+		AsyncDropPolicy: DropOldest,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"slow_threshold": "500ms",
+		"trace_all": true,
+		"levels": {"default": "DEBUG"},
+		"async_buffer_size": 16,
+		"async_drop_policy": "drop_oldest"
+	}`, string(data))
+
+	var config Config
+	require.NoError(t, json.Unmarshal(data, &config))
+	assert.Equal(t, 500*time.Millisecond, config.SlowThreshold)
+	assert.Equal(t, DropOldest, config.AsyncDropPolicy)
+}
+
+func TestConfig_RoundTrip(t *testing.T) {
+	l := New(
+		WithTransactionWatchdog(time.Minute),
+		WithBurstCapture(30*time.Second),
+		WithReturningRedaction(ReturningMasked),
+		WithoutComments(),
+		WithDecisionDebug(),
+		WithQueryBytes(),
+		WithMaxQueryBytesWarn(1024),
+		WithLargeResultThreshold(1000),
+		WithTables(),
+		WithShardedTables("events"),
+		WithSoftDeleteFlag(),
+		WithBatchMetrics(),
+		WithBindParamsCount(),
+		WithGoroutineID(),
+		WithPprofLabels(),
+		WithoutSpanStatement(),
+		WithStatsSummary(time.Minute),
+		WithQueryStats(),
+		WithRouteStats("http.route"),
+		WithJobStats("job"),
+		WithErrorEscalation(5, time.Minute),
+		WithSourceCacheSize(16),
+		WithTenantSamplingRates(map[string]float64{"acme": 0.01}),
+		WithVolumeBudget(10000),
+	).(*logger)
+	config := l.Config()
+
+	assert.Equal(t, config, NewWithConfig(config).Config())
+
+	// The snapshot is encoded and decoded without losing any setting
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"returning_redaction":"masked"`)
+	assert.Contains(t, string(data), `"error_escalation_window":"1m0s"`)
+	assert.Contains(t, string(data), `"stats_summary":"1m0s"`)
+
+	var decoded Config
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, config, NewWithConfig(decoded).Config())
+
+	yamlData, err := yaml.Marshal(config)
+	require.NoError(t, err)
+	decoded = Config{}
+	require.NoError(t, yaml.Unmarshal(yamlData, &decoded))
+	assert.Equal(t, config, NewWithConfig(decoded).Config())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"returning_redaction": "hidden"}`), &decoded))
+}
+
+func TestConfig_SamplingRates(t *testing.T) {
+	rates := map[LogType]float64{ErrorLogType: 1, SlowQueryLogType: 0.5, DefaultLogType: 0.01}
+	l := New(WithSamplingRates(rates)).(*logger)
+
+	config := l.Config()
+	assert.Equal(t, rates, config.SamplingRates)
+
+	// The snapshot is not shared with the logger
+	config.SamplingRates[DefaultLogType] = 1
+	assert.Equal(t, 0.01, l.logTypeSamplingRates[DefaultLogType])
+
+	// JSON round trip
+	data, err := json.Marshal(l.Config())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"sampling_rates":{"default":0.01,"slow_query":0.5,"sql_error":1}`)
+
+	var decoded Config
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, rates, NewWithConfig(decoded).(*logger).logTypeSamplingRates)
+
+	// Configuration file
+	path := filepath.Join(t.TempDir(), "logger.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sampling_rates:\n  sql_error: 1\n  slow_query: 0.5\n  default: 0.01\n"), 0o600))
+	fromFile, err := NewFromConfigFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, rates, fromFile.(*logger).logTypeSamplingRates)
+
+	// Invalid rates
+	require.NoError(t, os.WriteFile(path, []byte("sampling_rates:\n  slow_query: 2\n"), 0o600))
+	_, err = NewFromConfigFile(path)
+	assert.ErrorIs(t, err, ErrInvalidOption)
+}
+
+func TestConfig_ExplicitZeros(t *testing.T) {
+	var config Config
+	require.NoError(t, json.Unmarshal([]byte(`{"sampling_rate": 0}`), &config))
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sampling_rate": 0}`, string(data))
+
+	// The snapshot keeps the explicit zero
+	l := NewWithConfig(config).(*logger)
+	assert.Zero(t, l.samplingRate)
+	assert.Zero(t, NewWithConfig(l.Config()).(*logger).samplingRate)
+
+	// The zero value keeps the default
+	assert.Equal(t, 1.0, NewWithConfig(Config{}).(*logger).samplingRate)
+}
@@ -0,0 +1,100 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+// ContextAttrConflict defines how the context attributes whose key is also an attribute of the handler are
+// logged, see WithContextAttrConflict
+type ContextAttrConflict int
+
+const (
+	// ConflictKept logs the context attribute along with the attribute of the handler, the record having
+	// duplicate keys (default)
+	ConflictKept ContextAttrConflict = iota
+	// ConflictSkipped does not log the context attribute, the attribute of the handler being kept
+	ConflictSkipped
+	// ConflictOverridden logs the context attribute instead of the resource attribute of the same key
+	ConflictOverridden
+	// ConflictRenamed logs the context attribute with the ConflictSuffix appended to its key
+	ConflictRenamed
+)
+
+// ConflictSuffix is the suffix appended to the keys of the context attributes renamed, see ConflictRenamed
+const ConflictSuffix = "_ctx"
+
+// contextAttrConflictNames are the names of the resolutions of the conflicts of the context attributes
+var contextAttrConflictNames = map[ContextAttrConflict]string{
+	ConflictKept:       "kept",
+	ConflictSkipped:    "skipped",
+	ConflictOverridden: "overridden",
+	ConflictRenamed:    "renamed",
+}
+
+// String returns the name of the resolution of the conflicts of the context attributes
+func (c ContextAttrConflict) String() string {
+	if name, ok := contextAttrConflictNames[c]; ok {
+		return name
+	}
+	return "ContextAttrConflict(" + strconv.Itoa(int(c)) + ")"
+}
+
+// isHandlerKey reports whether the key is the key of an attribute of the handler: a resource attribute
+// (see WithResource) or a key declared with WithContextAttrConflict
+func (l logger) isHandlerKey(key string) bool {
+	if slices.Contains(l.handlerKeys, key) {
+		return true
+	}
+	return slices.ContainsFunc(l.resource, func(attr slog.Attr) bool { return attr.Key == key })
+}
+
+// isContextKey reports whether the key is the key of a context attribute, the tenant included
+func (l logger) isContextKey(key string) bool {
+	if l.tenant != nil && key == TenantField {
+		return true
+	}
+	return slices.ContainsFunc(l.contextAttrs, func(attr contextAttr) bool { return attr.name == key })
+}
+
+// splitResource returns the resource attributes added to the handler, and those overridden by the context
+// attributes added to each record instead, see ConflictOverridden
+func (l logger) splitResource() (handler, record []slog.Attr) {
+	if l.attrConflict != ConflictOverridden {
+		return l.resource, nil
+	}
+	for _, attr := range l.resource {
+		if l.isContextKey(attr.Key) {
+			record = append(record, attr)
+		} else {
+			handler = append(handler, attr)
+		}
+	}
+	return handler, record
+}
+
+// resolveConflict returns the context attribute to log, following the resolution of the conflicts with the
+// attributes of the handler, false if it is not logged
+func (l logger) resolveConflict(attr slog.Attr) (slog.Attr, bool) {
+	if l.attrConflict == ConflictKept || l.attrConflict == ConflictOverridden || !l.isHandlerKey(attr.Key) {
+		return attr, true
+	}
+	if l.attrConflict == ConflictSkipped {
+		return attr, false
+	}
+	attr.Key += ConflictSuffix
+	return attr, true
+}
+
+// appendOverriddenResource adds the resource attributes overridden by the context attributes which are
+// missing from the attributes added since the given index, see ConflictOverridden
+func (l logger) appendOverriddenResource(args []slog.Attr, from int) []slog.Attr {
+	added := args[from:]
+	for _, attr := range l.recordResource {
+		if !slices.ContainsFunc(added, func(a slog.Attr) bool { return a.Key == attr.Key }) {
+			args = append(args, attr)
+		}
+	}
+	return args
+}
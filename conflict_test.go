@@ -0,0 +1,113 @@
+package slogGorm
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_ContextAttrConflict(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM orders", 1
+	}
+	ctx := context.WithValue(context.Background(), ctxKey1, "checkout")
+
+	// trace logs a SQL query with the logger writing with the text handler, and returns the record written
+	trace := func(t *testing.T, ctx context.Context, options ...Option) string {
+		var buf bytes.Buffer
+		handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.SourceKey) {
+					return slog.Attr{}
+				}
+				return a
+			},
+		})
+		options = append(options,
+			WithHandler(slog.New(handler).With("team", "payments").Handler()),
+			WithResource("orders", "", ""),
+			WithContextValue("team", ctxKey1),
+			WithContextValue(ServiceNameField, ctxKey1),
+			WithoutSourceField(),
+			WithTraceAll(),
+		)
+		gormLogger, err := NewE(options...)
+		require.NoError(t, err)
+
+		gormLogger.Trace(ctx, time.Now(), fc, nil)
+		return strings.TrimSpace(buf.String())
+	}
+
+	t.Run("Kept", func(t *testing.T) {
+		record := trace(t, ctx, WithContextAttrConflict(ConflictKept, "team"))
+
+		assert.Contains(t, record, "team=payments service.name=orders")
+		assert.Contains(t, record, "team=checkout service.name=checkout")
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		record := trace(t, ctx, WithContextAttrConflict(ConflictSkipped, "team"))
+
+		assert.Contains(t, record, "team=payments service.name=orders")
+		assert.NotContains(t, record, "checkout")
+	})
+
+	t.Run("Renamed", func(t *testing.T) {
+		record := trace(t, ctx, WithContextAttrConflict(ConflictRenamed, "team"))
+
+		assert.Contains(t, record, "team=payments service.name=orders")
+		assert.Contains(t, record, "team_ctx=checkout service.name_ctx=checkout")
+	})
+
+	t.Run("Overridden", func(t *testing.T) {
+		record := trace(t, ctx, WithContextAttrConflict(ConflictOverridden, "team"))
+
+		assert.NotContains(t, record, "service.name=orders")
+		assert.Contains(t, record, "team=payments")
+		assert.Contains(t, record, "team=checkout service.name=checkout")
+	})
+
+	t.Run("Overridden without the context attribute", func(t *testing.T) {
+		record := trace(t, context.Background(), WithContextAttrConflict(ConflictOverridden))
+
+		assert.Equal(t, 1, strings.Count(record, "service.name=orders"))
+	})
+
+	t.Run("Tenant", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTenant(func(context.Context) (string, bool) { return "acme", true }),
+			WithContextAttrConflict(ConflictRenamed, TenantField),
+			WithTraceAll(),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(TenantField+ConflictSuffix, "acme"))
+	})
+
+	t.Run("Startup summary", func(t *testing.T) {
+		var buf bytes.Buffer
+		gormLogger := New(
+			WithHandler(slog.NewTextHandler(&buf, nil)),
+			WithResource("orders", "", ""),
+			WithContextValue(ServiceNameField, ctxKey1),
+			WithContextAttrConflict(ConflictOverridden),
+		)
+
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		assert.Equal(t, 1, strings.Count(buf.String(), "service.name=orders"))
+	})
+}
+
+func TestContextAttrConflict_String(t *testing.T) {
+	assert.Equal(t, "renamed", ConflictRenamed.String())
+	assert.Equal(t, "ContextAttrConflict(42)", ContextAttrConflict(42).String())
+}
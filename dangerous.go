@@ -0,0 +1,42 @@
+package slogGorm
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// isDangerousWrite reports whether the statement is an UPDATE or a DELETE without WHERE clause,
+// executed or blocked by gorm (gorm.ErrMissingWhereClause)
+func isDangerousWrite(query *lazyQuery, err error) bool {
+	if errors.Is(err, gorm.ErrMissingWhereClause) {
+		return true
+	}
+	switch query.Operation() {
+	case "UPDATE", "DELETE":
+		return !hasWhereClause(query.SQL())
+	}
+	return false
+}
+
+// hasWhereClause reports whether the SQL query has a WHERE clause outside of its parentheses,
+// i.e. neither in a subquery nor in a common table expression
+func hasWhereClause(sql string) bool {
+	var (
+		depth int
+		found bool
+	)
+	tokenize(sql, func(t token) bool {
+		switch {
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case depth == 0 && t.isKeyword("WHERE"):
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
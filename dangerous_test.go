@@ -0,0 +1,108 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_isDangerousWrite(t *testing.T) {
+	tests := []struct {
+		sql  string
+		err  error
+		want bool
+	}{
+		{sql: "UPDATE users SET name = 'john'", want: true},
+		{sql: `DELETE FROM "users"`, want: true},
+		{sql: "DELETE FROM users WHERE id = 1"},
+		{sql: "update users set name = 'john' where id in (select id from admins)"},
+		{sql: "UPDATE users SET name = (SELECT name FROM admins WHERE id = 1)", want: true},
+		{sql: "WITH admins AS (SELECT id FROM users WHERE admin) DELETE FROM sessions", want: true},
+		{sql: "SELECT * FROM users"},
+		{sql: "INSERT INTO users (name) VALUES ('john')"},
+		{sql: "DELETE FROM users", err: fmt.Errorf("delete: %w", gorm.ErrMissingWhereClause), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			query := newLazyQuery(func() (string, int64) { return tt.sql, 0 })
+			assert.Equal(t, tt.want, isDangerousWrite(query, tt.err))
+		})
+	}
+}
+
+func Test_logger_DangerousWriteDetection(t *testing.T) {
+	trace := func(l *logger, sql string, err error) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 3 }, err)
+	}
+
+	t.Run("Unscoped write", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithDangerousWriteDetection(), WithIgnoredTables("users")})
+
+		trace(gormLogger, "UPDATE users SET admin = true", nil)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelError, receiver.Record.Level)
+		assert.Equal(t, "unscoped UPDATE on users", receiver.Record.Message)
+		assertHasAttr(t, receiver.Record, slog.Bool(DangerousWriteField, true))
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "UPDATE users SET admin = true"))
+		assertHasAttr(t, &resolved, slog.Int64(RowsField, 3))
+		assertNoAttr(t, receiver.Record, ErrorField)
+	})
+
+	t.Run("Blocked by gorm", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithDangerousWriteDetection(),
+			WithStaticMessages(),
+			SetLogLevel(DangerousWriteLogType, slog.LevelError+4),
+		})
+
+		trace(gormLogger, "DELETE FROM users", gorm.ErrMissingWhereClause)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelError+4, receiver.Record.Level)
+		assert.Equal(t, "unscoped write", receiver.Record.Message)
+		assertHasAttr(t, receiver.Record, slog.Any(ErrorField, gorm.ErrMissingWhereClause))
+	})
+
+	t.Run("Scoped write", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithDangerousWriteDetection()})
+
+		trace(gormLogger, "UPDATE users SET admin = true WHERE id = 1", nil)
+
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+
+		trace(gormLogger, "UPDATE users SET admin = true", nil)
+
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Plugin mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithDangerousWriteDetection()})
+		db := openTestDB(t, gormLogger)
+
+		type user struct {
+			ID   int
+			Name string
+		}
+		require.ErrorIs(t, db.Model(&user{}).Update("name", "john").Error, gorm.ErrMissingWhereClause)
+
+		// The transaction of the update is rolled back before the statement is logged
+		require.Equal(t, 2, receiver.Len())
+		dangerous := receiver.Records[1]
+		assertHasAttr(t, &dangerous, slog.Bool(DangerousWriteField, true))
+		assertHasAttr(t, &dangerous, slog.Any(ErrorField, gorm.ErrMissingWhereClause))
+		assert.Equal(t, "unscoped UPDATE on users", dangerous.Message)
+	})
+}
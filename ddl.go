@@ -0,0 +1,54 @@
+package slogGorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ddlContextKey is the context key under which the DDL operation of a query is stored
+type ddlContextKey struct{}
+
+// ddlOperations are the operations of the DDL statements, logged with DDLLogType
+var ddlOperations = []string{"CREATE", "ALTER", "DROP", "TRUNCATE"}
+
+// detectDDL stores the DDL operation executed by the query, if any, into the statement
+// context so that Trace can retrieve it without explaining the SQL query.
+func detectDDL(db *gorm.DB) {
+	operation, ok := parseDDL(db.Statement.SQL.String())
+	if !ok {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db.Statement.Context = context.WithValue(ctx, ddlContextKey{}, operation)
+}
+
+// parseDDL returns the DDL operation executed by the given query (CREATE, ALTER, DROP or TRUNCATE), if any
+func parseDDL(sql string) (string, bool) {
+	var (
+		operation string
+		found     bool
+	)
+	tokenize(sql, func(t token) bool {
+		if t.kind == tokenSpace || t.kind == tokenComment {
+			return true
+		}
+		for _, ddl := range ddlOperations {
+			if t.isKeyword(ddl) {
+				operation, found = ddl, true
+			}
+		}
+		return false
+	})
+	return operation, found
+}
+
+// ddlFromContext returns the DDL operation stored into the given context, if any
+func ddlFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(ddlContextKey{}).(string)
+	return operation
+}
@@ -0,0 +1,70 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDDL(t *testing.T) {
+	tests := []struct {
+		sql       string
+		operation string
+		ok        bool
+	}{
+		{sql: "CREATE TABLE `users` (`id` integer)", operation: "CREATE", ok: true},
+		{sql: "  alter table users add column name text", operation: "ALTER", ok: true},
+		{sql: "/* migration */ DROP INDEX idx_users_name", operation: "DROP", ok: true},
+		{sql: "TRUNCATE sessions", operation: "TRUNCATE", ok: true},
+		{sql: "SELECT * FROM users WHERE name = 'DROP'"},
+		{sql: "INSERT INTO migrations (name) VALUES ('create users')"},
+		{sql: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			operation, ok := parseDDL(tt.sql)
+			assert.Equal(t, tt.operation, operation)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func Test_logger_DDL(t *testing.T) {
+	t.Run("DDL statement", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("CREATE TABLE users (id integer)").Error)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assert.Contains(t, receiver.Record.Message, "DDL statement executed [")
+		assertHasAttr(t, receiver.Record, slog.String(OperationField, "CREATE"))
+	})
+
+	t.Run("Other statement", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Custom level", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			SetLogLevel(DDLLogType, slog.LevelInfo),
+			WithStaticMessages(),
+		})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DROP TABLE users").Error)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelInfo, receiver.Record.Level)
+		assert.Equal(t, "DDL statement executed", receiver.Record.Message)
+	})
+}
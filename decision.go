@@ -0,0 +1,47 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// The decisions of the logger not to log a SQL query, logged as the decision attribute, see WithDecisionDebug
+const (
+	decisionNotTraced          = "not_traced"
+	decisionBelowSlowThreshold = "below_slow_threshold"
+	decisionLevelDisabled      = "level_disabled"
+	decisionSampledOut         = "sampled_out"
+	decisionFiltered           = "filtered"
+	decisionSilenced           = "silenced"
+	decisionOverBudget         = "over_budget"
+)
+
+// logDecision logs why the SQL query is not logged, at the DecisionLogType level. The query is sanitized
+// like the SQL records, the logger no longer evaluating it once it decided not to log it.
+func (l logger) logDecision(ctx context.Context, decision string, logType LogType, elapsed time.Duration, query *lazyQuery, attrs ...slog.Attr) {
+	level := l.logLevel[DecisionLogType]
+	if l.silenced(DecisionLogType) || !l.enabled(ctx, level) {
+		return
+	}
+	if l.sanitizesSQL() {
+		query.replaceSQL(l.sanitizeSQL)
+	}
+
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+
+	*attributes = append(*attributes, slog.String(DecisionField, decision))
+	if logType != "" {
+		*attributes = append(*attributes, slog.String(LogTypeField, string(logType)))
+	}
+	*attributes = append(*attributes, attrs...)
+	*attributes = append(*attributes,
+		slog.Any(QueryField, sqlValuer{query}),
+		slog.Duration(DurationField, elapsed),
+	)
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(query, *attributes)
+
+	l.logAttrs(ctx, level, DecisionMessage, *attributes...)
+}
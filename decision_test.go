@@ -0,0 +1,101 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_DecisionDebug(t *testing.T) {
+	trace := func(l *logger, sql string, elapsed time.Duration) {
+		l.Trace(context.Background(), time.Now().Add(-elapsed), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	tests := []struct {
+		name     string
+		options  []Option
+		expected []slog.Attr
+	}{
+		{
+			name:    "Filtered",
+			options: []Option{WithTraceAll(), WithIgnoredQueries(regexp.MustCompile(`^SELECT 1$`))},
+			expected: []slog.Attr{
+				slog.String(DecisionField, "filtered"),
+				slog.String(LogTypeField, string(DefaultLogType)),
+				slog.String(FilterField, "ignored query ^SELECT 1$"),
+			},
+		},
+		{
+			name:    "Sampled out",
+			options: []Option{WithTraceAll(), WithSamplingRate(0)},
+			expected: []slog.Attr{
+				slog.String(DecisionField, "sampled_out"),
+				slog.Float64(SamplingRateField, 0),
+			},
+		},
+		{
+			name:    "Below the slow threshold",
+			options: []Option{WithSlowThreshold(time.Hour)},
+			expected: []slog.Attr{
+				slog.String(DecisionField, "below_slow_threshold"),
+				slog.Duration(ThresholdField, time.Hour),
+			},
+		},
+		{
+			name:     "Not traced",
+			options:  nil,
+			expected: []slog.Attr{slog.String(DecisionField, "not_traced")},
+		},
+		{
+			name:    "Level disabled",
+			options: []Option{WithTraceAll(), SetLogLevel(DefaultLogType, slog.LevelDebug-4)},
+			expected: []slog.Attr{
+				slog.String(DecisionField, "level_disabled"),
+				slog.String(RecordLevelField, "DEBUG-4"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver, gormLogger := getReceiverAndLogger(append(tt.options, WithDecisionDebug(), WithMinLevel(slog.LevelDebug)))
+
+			trace(gormLogger, "SELECT 1", time.Millisecond)
+
+			require.Equal(t, 1, receiver.Len())
+			r := resolveRecord(receiver.Records[0])
+			assert.Equal(t, DecisionMessage, r.Message)
+			assert.Equal(t, slog.LevelDebug, r.Level)
+			assertHasAttr(t, &r, slog.String(QueryField, "SELECT 1"))
+			for _, attr := range tt.expected {
+				assertHasAttr(t, &r, attr)
+			}
+		})
+	}
+
+	t.Run("Values removed", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithDecisionDebug(), WithNoValues(), WithIgnoredTables("users"), WithTraceAll(),
+		})
+
+		trace(gormLogger, "SELECT * FROM users WHERE email = 'jane@example.com'", time.Millisecond)
+
+		require.Equal(t, 1, receiver.Len())
+		r := resolveRecord(receiver.Records[0])
+		assertHasAttr(t, &r, slog.String(FilterField, "ignored tables users"))
+		assertHasAttr(t, &r, slog.String(QueryField, "SELECT * FROM users WHERE email = ?"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSamplingRate(0)})
+
+		trace(gormLogger, "SELECT 1", time.Millisecond)
+
+		assert.Zero(t, receiver.Len())
+	})
+}
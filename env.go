@@ -0,0 +1,204 @@
+package slogGorm
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is the prefix of the environment variables read by NewFromEnv
+const EnvPrefix = "SLOG_GORM_"
+
+// The prefixes of the environment variables defining the level, the message template and the sampling rate of a LogType
+const (
+	envLevelPrefix        = EnvPrefix + "LEVEL_"
+	envMessagePrefix      = EnvPrefix + "MESSAGE_"
+	envSamplingRatePrefix = EnvPrefix + "SAMPLING_RATE_"
+)
+
+// NewFromEnv creates a new logger for gorm.io/gorm configured by the environment variables,
+// so that the logging of the SQL queries can be tuned per deployment:
+//
+//	SLOG_GORM_TRACE_ALL=true                     WithTraceAll
+//	SLOG_GORM_IGNORE_TRACE=true                  WithIgnoreTrace
+//	SLOG_GORM_RECORD_NOT_FOUND_ERROR=true        WithRecordNotFoundError
+//	SLOG_GORM_PARAMETERIZED_QUERIES=true         WithParameterizedQueries
+//	SLOG_GORM_STATIC_MESSAGES=true               WithStaticMessages
+//	SLOG_GORM_METADATA_MODE=message              WithMetadataMode
+//	SLOG_GORM_DEBUG_FULL_QUERIES=true            WithDebugFullQueries
+//	SLOG_GORM_NO_VALUES=true                     WithNoValues
+//	SLOG_GORM_ALLOWED_CONTEXT_ATTRS=request_id   WithNoValues (the allowed context attributes)
+//	SLOG_GORM_MASKED_COLUMNS=users.email         WithMaskedColumns
+//	SLOG_GORM_SECURITY_DETECTION=true            WithSecurityDetection
+//	SLOG_GORM_DANGEROUS_WRITE_DETECTION=true     WithDangerousWriteDetection
+//	SLOG_GORM_SLOW_THRESHOLD=500ms               WithSlowThreshold
+//	SLOG_GORM_TRANSACTION_WATCHDOG=1m            WithTransactionWatchdog
+//	SLOG_GORM_BURST_CAPTURE=30s                  WithBurstCapture
+//	SLOG_GORM_SOURCE_FIELD=origin                WithSourceField
+//	SLOG_GORM_CALLER_FUNCTION=true               WithCallerFunction
+//	SLOG_GORM_ERROR_FIELD=err                    WithErrorField
+//	SLOG_GORM_KEY_CASE=camel                     WithKeyCase
+//	SLOG_GORM_PRESET=otel                        WithPreset
+//	SLOG_GORM_IGNORED_OPERATIONS=SELECT,INSERT   WithIgnoredOperations
+//	SLOG_GORM_IGNORED_TABLES=sessions            WithIgnoredTables
+//	SLOG_GORM_SAMPLING_RATE=0.1                  WithSamplingRate
+//	SLOG_GORM_SAMPLING_RATE_<LOG TYPE>=0.5       WithSamplingRates (e.g. SLOG_GORM_SAMPLING_RATE_SLOW_QUERY)
+//	SLOG_GORM_VOLUME_BUDGET=10000                WithVolumeBudget
+//	SLOG_GORM_ASYNC_BUFFER_SIZE=1024             WithAsync
+//	SLOG_GORM_MIN_LEVEL=WARN                     WithMinLevel
+//	SLOG_GORM_LEVEL_<LOG TYPE>=DEBUG             SetLogLevel (e.g. SLOG_GORM_LEVEL_ERROR, SLOG_GORM_LEVEL_SLOW_QUERY)
+//	SLOG_GORM_MESSAGE_<LOG TYPE>=slow {table}    WithMessage (e.g. SLOG_GORM_MESSAGE_SLOW_QUERY)
+//	SLOG_GORM_SILENCED_LOG_TYPES=slow_query      WithSilencedLogTypes
+//
+// The values are validated like the options, explicit zeros included (e.g. SLOG_GORM_SAMPLING_RATE=0 logs none
+// of the SQL messages traced). The options are applied after the environment variables. It returns an error
+// wrapping ErrInvalidOption for each invalid variable or option.
+func NewFromEnv(options ...Option) (Logger, error) {
+	config, err := configFromEnv(os.Environ())
+	if err != nil {
+		return nil, err
+	}
+	return NewE(append(config.options(), options...)...)
+}
+
+// configFromEnv returns the configuration defined by the given environment variables ("key=value")
+func configFromEnv(environ []string) (Config, error) {
+	var (
+		config Config
+		errs   []error
+	)
+
+	for _, variable := range environ {
+		key, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(key, EnvPrefix) {
+			continue
+		}
+
+		var err error
+		switch key {
+		case EnvPrefix + "TRACE_ALL":
+			config.TraceAll, err = strconv.ParseBool(value)
+		case EnvPrefix + "IGNORE_TRACE":
+			config.IgnoreTrace, err = strconv.ParseBool(value)
+		case EnvPrefix + "RECORD_NOT_FOUND_ERROR":
+			config.RecordNotFoundError, err = strconv.ParseBool(value)
+		case EnvPrefix + "PARAMETERIZED_QUERIES":
+			config.ParameterizedQueries, err = strconv.ParseBool(value)
+		case EnvPrefix + "STATIC_MESSAGES":
+			config.StaticMessages, err = strconv.ParseBool(value)
+		case EnvPrefix + "METADATA_MODE":
+			err = config.MetadataMode.UnmarshalText([]byte(value))
+		case EnvPrefix + "NO_VALUES":
+			config.NoValues, err = strconv.ParseBool(value)
+		case EnvPrefix + "ALLOWED_CONTEXT_ATTRS":
+			config.AllowedContextAttrs = splitEnvList(value)
+		case EnvPrefix + "DANGEROUS_WRITE_DETECTION":
+			config.DangerousWriteDetection, err = strconv.ParseBool(value)
+		case EnvPrefix + "SECURITY_DETECTION":
+			config.SecurityDetection, err = strconv.ParseBool(value)
+		case EnvPrefix + "MASKED_COLUMNS":
+			config.MaskedColumns = splitEnvList(value)
+		case EnvPrefix + "DEBUG_FULL_QUERIES":
+			config.DebugFullQueries, err = strconv.ParseBool(value)
+		case EnvPrefix + "SLOW_THRESHOLD":
+			config.SlowThreshold, err = time.ParseDuration(value)
+		case EnvPrefix + "TRANSACTION_WATCHDOG":
+			config.TransactionWatchdog, err = time.ParseDuration(value)
+		case EnvPrefix + "BURST_CAPTURE":
+			config.BurstCapture, err = time.ParseDuration(value)
+		case EnvPrefix + "SOURCE_FIELD":
+			config.SourceField = value
+		case EnvPrefix + "CALLER_FUNCTION":
+			config.CallerFunction, err = strconv.ParseBool(value)
+		case EnvPrefix + "ERROR_FIELD":
+			config.ErrorField = value
+		case EnvPrefix + "KEY_CASE":
+			err = config.KeyCase.UnmarshalText([]byte(value))
+		case EnvPrefix + "PRESET":
+			config.Preset = value
+		case EnvPrefix + "IGNORED_OPERATIONS":
+			config.IgnoredOperations = splitEnvList(value)
+		case EnvPrefix + "IGNORED_TABLES":
+			config.IgnoredTables = splitEnvList(value)
+		case EnvPrefix + "SILENCED_LOG_TYPES":
+			for _, name := range splitEnvList(value) {
+				config.SilencedLogTypes = append(config.SilencedLogTypes, envLogType(name))
+			}
+		case EnvPrefix + "SAMPLING_RATE":
+			config.SamplingRate, err = strconv.ParseFloat(value, 64)
+		case EnvPrefix + "VOLUME_BUDGET":
+			config.VolumeBudget, err = strconv.Atoi(value)
+		case EnvPrefix + "ASYNC_BUFFER_SIZE":
+			config.AsyncBufferSize, err = strconv.Atoi(value)
+		case EnvPrefix + "MIN_LEVEL":
+			var level slog.Level
+			if err = level.UnmarshalText([]byte(value)); err == nil {
+				config.MinLevel = &level
+			}
+		default:
+			if strings.HasPrefix(key, envMessagePrefix) {
+				if config.Messages == nil {
+					config.Messages = make(map[LogType]string)
+				}
+				config.Messages[envLogType(strings.TrimPrefix(key, envMessagePrefix))] = value
+				break
+			}
+			if strings.HasPrefix(key, envSamplingRatePrefix) {
+				var rate float64
+				if rate, err = strconv.ParseFloat(value, 64); err != nil {
+					break
+				}
+				if config.SamplingRates == nil {
+					config.SamplingRates = make(map[LogType]float64)
+				}
+				config.SamplingRates[envLogType(strings.TrimPrefix(key, envSamplingRatePrefix))] = rate
+				break
+			}
+			if !strings.HasPrefix(key, envLevelPrefix) {
+				err = errors.New("unknown variable")
+				break
+			}
+
+			var level slog.Level
+			if err = level.UnmarshalText([]byte(value)); err != nil {
+				break
+			}
+			if config.Levels == nil {
+				config.Levels = make(map[LogType]slog.Level)
+			}
+			config.Levels[envLogType(strings.TrimPrefix(key, envLevelPrefix))] = level
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: environment variable %s=%q: %w", ErrInvalidOption, key, value, err))
+			continue
+		}
+		config.markZero(strings.ToLower(strings.TrimPrefix(key, EnvPrefix)))
+	}
+
+	return config, errors.Join(errs...)
+}
+
+// envLogType returns the LogType named by an environment variable (e.g. SLOW_QUERY)
+func envLogType(name string) LogType {
+	logType := LogType(strings.ToLower(name))
+	if logType == "error" {
+		return ErrorLogType
+	}
+	return logType
+}
+
+// splitEnvList splits a comma-separated list
+func splitEnvList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
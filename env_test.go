@@ -0,0 +1,181 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("Environment variables", func(t *testing.T) {
+		t.Setenv("SLOG_GORM_TRACE_ALL", "true")
+		t.Setenv("SLOG_GORM_SLOW_THRESHOLD", "500ms")
+		t.Setenv("SLOG_GORM_LEVEL_ERROR", "WARN")
+		t.Setenv("SLOG_GORM_LEVEL_DEFAULT", "debug")
+		t.Setenv("SLOG_GORM_IGNORED_TABLES", "sessions, schema_migrations")
+
+		handler := NewDummyHandler()
+		l, err := NewFromEnv(WithHandler(handler))
+
+		require.NoError(t, err)
+		assert.Equal(t, handler, l.(*logger).sloggerHandler)
+		assert.True(t, l.(*logger).traceAll)
+		assert.Equal(t, 500*time.Millisecond, l.(*logger).slowThreshold)
+		assert.Equal(t, slog.LevelWarn, l.(*logger).logLevel[ErrorLogType])
+		assert.Equal(t, slog.LevelDebug, l.(*logger).logLevel[DefaultLogType])
+		assert.Len(t, l.(*logger).filters, 1)
+	})
+
+	t.Run("Invalid environment variables", func(t *testing.T) {
+		t.Setenv("SLOG_GORM_TRACE_ALL", "sometimes")
+		t.Setenv("SLOG_GORM_LEVEL_DEFAULT", "verbose")
+
+		l, err := NewFromEnv()
+
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.Contains(t, err.Error(), "SLOG_GORM_TRACE_ALL")
+		assert.Contains(t, err.Error(), "SLOG_GORM_LEVEL_DEFAULT")
+	})
+
+	t.Run("Negative values", func(t *testing.T) {
+		t.Setenv("SLOG_GORM_SLOW_THRESHOLD", "-1s")
+		t.Setenv("SLOG_GORM_TRANSACTION_WATCHDOG", "-1m")
+		t.Setenv("SLOG_GORM_BURST_CAPTURE", "-5s")
+		t.Setenv("SLOG_GORM_SAMPLING_RATE", "-0.5")
+		t.Setenv("SLOG_GORM_VOLUME_BUDGET", "-3")
+		t.Setenv("SLOG_GORM_ASYNC_BUFFER_SIZE", "-3")
+
+		l, err := NewFromEnv()
+
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.ErrorContains(t, err, "negative slow threshold")
+		assert.ErrorContains(t, err, "negative transaction watchdog threshold -1m0s")
+		assert.ErrorContains(t, err, "non-positive burst capture window -5s")
+		assert.ErrorContains(t, err, "sampling rate -0.5")
+		assert.ErrorContains(t, err, "non-positive volume budget -3")
+		assert.ErrorContains(t, err, "negative asynchronous buffer size -3")
+	})
+
+	t.Run("Zero values", func(t *testing.T) {
+		t.Setenv("SLOG_GORM_SLOW_THRESHOLD", "0s")
+		t.Setenv("SLOG_GORM_SAMPLING_RATE", "0")
+		t.Setenv("SLOG_GORM_ASYNC_BUFFER_SIZE", "0")
+
+		l, err := NewFromEnv()
+
+		require.NoError(t, err)
+		assert.Zero(t, l.(*logger).slowThreshold)
+		assert.Zero(t, l.(*logger).samplingRate)
+		assert.Nil(t, l.(*logger).async)
+
+		t.Setenv("SLOG_GORM_VOLUME_BUDGET", "0")
+
+		_, err = NewFromEnv()
+
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.ErrorContains(t, err, "non-positive volume budget 0")
+	})
+
+	t.Run("Unknown LogType", func(t *testing.T) {
+		t.Setenv("SLOG_GORM_LEVEL_UNKNOWN", "INFO")
+
+		_, err := NewFromEnv()
+
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.Contains(t, err.Error(), `unknown log type "unknown"`)
+	})
+}
+
+func Test_configFromEnv(t *testing.T) {
+	t.Run("All variables", func(t *testing.T) {
+		minLevel := slog.LevelWarn
+		config, err := configFromEnv([]string{
+			"HOME=/root",
+			"SLOG_GORM_TRACE_ALL=1",
+			"SLOG_GORM_IGNORE_TRACE=false",
+			"SLOG_GORM_RECORD_NOT_FOUND_ERROR=true",
+			"SLOG_GORM_PARAMETERIZED_QUERIES=true",
+			"SLOG_GORM_STATIC_MESSAGES=true",
+			"SLOG_GORM_DEBUG_FULL_QUERIES=true",
+			"SLOG_GORM_NO_VALUES=true",
+			"SLOG_GORM_ALLOWED_CONTEXT_ATTRS=request_id",
+			"SLOG_GORM_MASKED_COLUMNS=users.email,card_number",
+			"SLOG_GORM_SECURITY_DETECTION=true",
+			"SLOG_GORM_DANGEROUS_WRITE_DETECTION=true",
+			"SLOG_GORM_SLOW_THRESHOLD=1s",
+			"SLOG_GORM_TRANSACTION_WATCHDOG=1m",
+			"SLOG_GORM_BURST_CAPTURE=30s",
+			"SLOG_GORM_SOURCE_FIELD=origin",
+			"SLOG_GORM_CALLER_FUNCTION=true",
+			"SLOG_GORM_ERROR_FIELD=err",
+			"SLOG_GORM_KEY_CASE=camel",
+			"SLOG_GORM_PRESET=otel",
+			"SLOG_GORM_IGNORED_OPERATIONS=SELECT,,INSERT",
+			"SLOG_GORM_IGNORED_TABLES=sessions",
+			"SLOG_GORM_SAMPLING_RATE=0.25",
+			"SLOG_GORM_SAMPLING_RATE_SLOW_QUERY=0.5",
+			"SLOG_GORM_SAMPLING_RATE_ERROR=1",
+			"SLOG_GORM_VOLUME_BUDGET=10000",
+			"SLOG_GORM_ASYNC_BUFFER_SIZE=1024",
+			"SLOG_GORM_MIN_LEVEL=warn",
+			"SLOG_GORM_LEVEL_SLOW_QUERY=ERROR",
+			"SLOG_GORM_LEVEL_LONG_TRANSACTION=INFO+2",
+			"SLOG_GORM_MESSAGE_SLOW_QUERY=slow query on {table}",
+			"SLOG_GORM_SILENCED_LOG_TYPES=SLOW_QUERY,large_result",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, Config{
+			TraceAll:                true,
+			RecordNotFoundError:     true,
+			ParameterizedQueries:    true,
+			StaticMessages:          true,
+			DebugFullQueries:        true,
+			NoValues:                true,
+			AllowedContextAttrs:     []string{"request_id"},
+			MaskedColumns:           []string{"users.email", "card_number"},
+			SecurityDetection:       true,
+			DangerousWriteDetection: true,
+			SlowThreshold:           time.Second,
+			TransactionWatchdog:     time.Minute,
+			BurstCapture:            30 * time.Second,
+			SourceField:             "origin",
+			CallerFunction:          true,
+			ErrorField:              "err",
+			KeyCase:                 CamelCase,
+			Preset:                  "otel",
+			IgnoredOperations:       []string{"SELECT", "INSERT"},
+			IgnoredTables:           []string{"sessions"},
+			SamplingRate:            0.25,
+			SamplingRates:           map[LogType]float64{SlowQueryLogType: 0.5, ErrorLogType: 1},
+			VolumeBudget:            10000,
+			AsyncBufferSize:         1024,
+			MinLevel:                &minLevel,
+			Levels: map[LogType]slog.Level{
+				SlowQueryLogType:       slog.LevelError,
+				LongTransactionLogType: slog.LevelInfo + 2,
+			},
+			Messages:         map[LogType]string{SlowQueryLogType: "slow query on {table}"},
+			SilencedLogTypes: []LogType{SlowQueryLogType, LargeResultLogType},
+		}, config)
+	})
+
+	t.Run("Invalid sampling rate", func(t *testing.T) {
+		_, err := configFromEnv([]string{"SLOG_GORM_SAMPLING_RATE_SLOW_QUERY=half"})
+
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.Contains(t, err.Error(), "SLOG_GORM_SAMPLING_RATE_SLOW_QUERY")
+	})
+
+	t.Run("Unknown variable", func(t *testing.T) {
+		_, err := configFromEnv([]string{"SLOG_GORM_TRACEALL=true"})
+
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.Contains(t, err.Error(), "SLOG_GORM_TRACEALL")
+	})
+}
@@ -0,0 +1,71 @@
+package slogGorm
+
+import (
+	"sync"
+	"time"
+)
+
+// maxEscalationFingerprints is the number of error fingerprints counted by WithErrorEscalation, the errors
+// with other fingerprints not being escalated
+const maxEscalationFingerprints = 1024
+
+// errorEscalation counts the SQL errors per fingerprint within the window, shared with the loggers derived
+// from, see WithErrorEscalation
+type errorEscalation struct {
+	mu      sync.Mutex
+	windows map[string]errorWindow
+}
+
+// errorWindow is the number of SQL errors of a fingerprint since the start of its window
+type errorWindow struct {
+	start time.Time
+	count int
+}
+
+// newErrorEscalation returns an empty error escalation
+func newErrorEscalation() *errorEscalation {
+	return &errorEscalation{windows: make(map[string]errorWindow)}
+}
+
+// count counts the SQL error of the fingerprint, and returns the number of errors of the fingerprint in its
+// window, a window starting with the first error after the end of the previous one. It returns 0 once the
+// maximum number of fingerprints is reached.
+func (e *errorEscalation) count(key string, now time.Time, window time.Duration) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	w, ok := e.windows[key]
+	if !ok && len(e.windows) >= maxEscalationFingerprints {
+		e.evict(now, window)
+		if len(e.windows) >= maxEscalationFingerprints {
+			return 0
+		}
+	}
+	if !ok || now.Sub(w.start) >= window {
+		w = errorWindow{start: now}
+	}
+	w.count++
+	e.windows[key] = w
+	return w.count
+}
+
+// evict removes the fingerprints whose window ended
+func (e *errorEscalation) evict(now time.Time, window time.Duration) {
+	for key, w := range e.windows {
+		if now.Sub(w.start) >= window {
+			delete(e.windows, key)
+		}
+	}
+}
+
+// errorFingerprint returns the fingerprint of the SQL error: the fingerprints of the SQL query and of the
+// error message, their values being removed
+func errorFingerprint(sql string, err error) string {
+	return fingerprint(sql) + "\n" + fingerprint(err.Error())
+}
+
+// escalatedErrors returns the number of errors of the fingerprint of the SQL error in its window, and whether
+// the record is escalated to the EscalatedErrorLogType level, see WithErrorEscalation
+func (l logger) escalatedErrors(query *lazyQuery, err error) (int, bool) {
+	count := l.escalation.count(errorFingerprint(query.SQL(), err), l.clock.Now(), l.escalationWindow)
+	return count, count > l.escalationThreshold
+}
@@ -0,0 +1,71 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_ErrorEscalation(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	trace := func(l *logger, id int, err error) {
+		l.Trace(context.Background(), now, func() (string, int64) {
+			return fmt.Sprintf("SELECT * FROM users WHERE id = %d", id), 0
+		}, err)
+	}
+
+	t.Run("Escalated", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithErrorEscalation(2, time.Minute),
+		})
+
+		for id := 1; id <= 2; id++ {
+			trace(gormLogger, id, fmt.Errorf("connection %d refused", id))
+			require.Equal(t, slog.LevelError, receiver.Record.Level)
+			assertNoAttr(t, receiver.Record, EscalatedField)
+		}
+
+		trace(gormLogger.with(), 3, errors.New("connection 3 refused"))
+		assert.Equal(t, slog.LevelError+4, receiver.Record.Level, "the escalation is shared with the derived loggers")
+		assertHasAttr(t, receiver.Record, slog.Bool(EscalatedField, true))
+		assertHasAttr(t, receiver.Record, slog.Int(ErrorCountField, 3))
+
+		trace(gormLogger, 4, errors.New("duplicated key"))
+		assert.Equal(t, slog.LevelError, receiver.Record.Level, "the other fingerprints are not escalated")
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithErrorEscalation(1, time.Minute),
+			SetLogLevel(EscalatedErrorLogType, slog.LevelError+8),
+		})
+
+		trace(gormLogger, 1, errors.New("awesome error"))
+		trace(gormLogger, 1, errors.New("awesome error"))
+		assert.Equal(t, slog.LevelError+8, receiver.Record.Level)
+
+		now = now.Add(time.Minute)
+		trace(gormLogger, 1, errors.New("awesome error"))
+		assert.Equal(t, slog.LevelError, receiver.Record.Level, "a new window is started")
+	})
+}
+
+func Test_errorEscalation_count(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	e := newErrorEscalation()
+	for i := 0; i < maxEscalationFingerprints; i++ {
+		e.count(fmt.Sprintf("fingerprint %d", i), now, time.Minute)
+	}
+
+	assert.Equal(t, 0, e.count("other", now, time.Minute), "the maximum number of fingerprints is reached")
+	assert.Equal(t, 1, e.count("other", now.Add(time.Minute), time.Minute), "the ended windows are evicted")
+	assert.Len(t, e.windows, 1)
+}
@@ -0,0 +1,274 @@
+package slogGorm
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// explainer runs EXPLAIN for the slow queries, see WithExplainOnSlow
+type explainer struct {
+	db            *gorm.DB
+	prefix        string
+	analyzePrefix string
+	interval      time.Duration
+	timeout       time.Duration
+	record        bool
+	plans         *planCache
+
+	// next is the time before which no query is explained, in Unix nanoseconds
+	next atomic.Int64
+}
+
+// ExplainOption is an option of the capture of the query plans, see WithExplainOnSlow
+type ExplainOption func(e *explainer)
+
+// WithExplainPrefix defines the statement prefixed to the slow queries to explain them ("EXPLAIN " by default,
+// "EXPLAIN QUERY PLAN " for SQLite), e.g. "EXPLAIN (FORMAT JSON) " for PostgreSQL
+func WithExplainPrefix(prefix string) ExplainOption {
+	return func(e *explainer) {
+		e.prefix = prefix
+	}
+}
+
+// WithExplainAnalyzePrefix defines the statement prefixed to the queries of the requests flagged with
+// ContextWithExplainAnalyze ("EXPLAIN ANALYZE " by default, "EXPLAIN QUERY PLAN " for SQLite), e.g.
+// "EXPLAIN (ANALYZE, BUFFERS) " for PostgreSQL
+func WithExplainAnalyzePrefix(prefix string) ExplainOption {
+	return func(e *explainer) {
+		e.analyzePrefix = prefix
+	}
+}
+
+// WithExplainInterval defines the minimum interval between two queries explained (one minute by default)
+func WithExplainInterval(interval time.Duration) ExplainOption {
+	return func(e *explainer) {
+		e.interval = interval
+	}
+}
+
+// WithExplainTimeout defines the timeout of the EXPLAIN statements (one second by default)
+func WithExplainTimeout(timeout time.Duration) ExplainOption {
+	return func(e *explainer) {
+		e.timeout = timeout
+	}
+}
+
+// WithExplainRecord logs the query plans in companion records at the ExplainLogType level, rather than as
+// the plan attribute of the slow queries
+func WithExplainRecord() ExplainOption {
+	return func(e *explainer) {
+		e.record = true
+	}
+}
+
+// newExplainer creates the explainer running EXPLAIN with the given database
+func newExplainer(db *gorm.DB, options []ExplainOption) *explainer {
+	e := &explainer{
+		db:            db,
+		prefix:        "EXPLAIN ",
+		analyzePrefix: "EXPLAIN ANALYZE ",
+		interval:      time.Minute,
+		timeout:       time.Second,
+	}
+	if db.Config != nil && db.Dialector != nil && db.Dialector.Name() == "sqlite" {
+		e.prefix = "EXPLAIN QUERY PLAN "
+		e.analyzePrefix = "EXPLAIN QUERY PLAN "
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// explainSlowQuery returns the plan of the slow query, if it can be explained safely: a single SELECT
+// statement with its values, outside of a transaction (whose connection may be the only one available),
+// and no other query explained during the interval. The plans are not captured when the values are
+// removed or masked, as they may contain them.
+func (l logger) explainSlowQuery(ctx context.Context, query *lazyQuery) (string, bool) {
+	if l.explainer == nil || l.noValues || len(l.maskedColumns) > 0 || transactionFromContext(ctx) != nil {
+		return "", false
+	}
+	statement := query.SQL()
+	if !isExplainable(statement) || !l.explainer.allow(l.clock.Now()) {
+		return "", false
+	}
+	return l.explainer.explain(l.explainer.prefix, statement)
+}
+
+// explainAnalyzeKey is the context key of the requests whose queries are explained with EXPLAIN ANALYZE
+type explainAnalyzeKey struct{}
+
+// explainAnalyzeRequest is a request flagged with ContextWithExplainAnalyze
+type explainAnalyzeRequest struct {
+	// done is set once a query of the request is explained
+	done atomic.Bool
+}
+
+// ContextWithExplainAnalyze flags the request of the context (e.g. with a debug header set by an engineer)
+// so that its first SELECT statement is explained with EXPLAIN ANALYZE, the plan being logged in a companion
+// record at the ExplainLogType level with the analyze attribute. It requires WithExplainOnSlow.
+//
+// As EXPLAIN ANALYZE executes the query again, only one query per request is explained, and only a single
+// SELECT statement without locking clause (FOR UPDATE, FOR SHARE...) nor INTO clause. The functions with side
+// effects called by the query cannot be detected: flag only the requests whose queries are known to be safe.
+//
+// Usage:
+//
+//	if r.Header.Get("X-Debug-Explain") == token {
+//		ctx = slogGorm.ContextWithExplainAnalyze(ctx)
+//	}
+//	db.WithContext(ctx).Find(&users)
+func ContextWithExplainAnalyze(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainAnalyzeKey{}, &explainAnalyzeRequest{})
+}
+
+// explainAnalyze returns the plan of the query with EXPLAIN ANALYZE, if its request is flagged with
+// ContextWithExplainAnalyze and no other query of the request was explained, with the limits of
+// explainSlowQuery
+func (l logger) explainAnalyze(ctx context.Context, query *lazyQuery) (string, bool) {
+	request, _ := ctx.Value(explainAnalyzeKey{}).(*explainAnalyzeRequest)
+	if request == nil || request.done.Load() || l.explainer == nil || l.noValues || len(l.maskedColumns) > 0 ||
+		transactionFromContext(ctx) != nil {
+		return "", false
+	}
+	statement := query.SQL()
+	if !isAnalyzable(statement) || !request.done.CompareAndSwap(false, true) {
+		return "", false
+	}
+	return l.explainer.explain(l.explainer.analyzePrefix, statement)
+}
+
+// logPlan logs the plan of the query in a companion record, at the ExplainLogType level
+func (l logger) logPlan(ctx context.Context, query *lazyQuery, source *sourceValuer, plan string, attrs ...slog.Attr) {
+	level := l.logLevel[ExplainLogType]
+	if l.silenced(ExplainLogType) || !l.enabled(ctx, level) {
+		return
+	}
+
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+
+	*attributes = append(*attributes,
+		slog.Any(QueryField, sqlValuer{query}),
+		slog.String(PlanField, plan),
+	)
+	*attributes = append(*attributes, attrs...)
+	*attributes = l.appendSourceAttribute(*attributes, source)
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(query, *attributes)
+
+	l.logAttrs(ctx, level, ExplainMessage, *attributes...)
+}
+
+// allow reports whether a query can be explained at the given time, only one caller being allowed per interval
+func (e *explainer) allow(now time.Time) bool {
+	next := e.next.Load()
+	if now.UnixNano() < next {
+		return false
+	}
+	return e.next.CompareAndSwap(next, now.Add(e.interval).UnixNano())
+}
+
+// explain runs the EXPLAIN statement with the given prefix for the SQL query and returns the plan: a line
+// per row, the columns being written as column=value when there are several
+func (e *explainer) explain(prefix, query string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	// The EXPLAIN statement is not logged, to never explain it in turn
+	db := e.db.Session(&gorm.Session{NewDB: true, Context: ctx, Logger: gormlogger.Discard})
+	rows, err := db.Raw(prefix + query).Rows()
+	if err != nil {
+		return "", false
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil || len(columns) == 0 {
+		return "", false
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var plan strings.Builder
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return "", false
+		}
+		if plan.Len() > 0 {
+			plan.WriteByte('\n')
+		}
+		if len(columns) == 1 {
+			plan.WriteString(values[0].String)
+			continue
+		}
+		for i, column := range columns {
+			if i > 0 {
+				plan.WriteByte(' ')
+			}
+			plan.WriteString(column)
+			plan.WriteByte('=')
+			plan.WriteString(values[i].String)
+		}
+	}
+	if rows.Err() != nil || plan.Len() == 0 {
+		return "", false
+	}
+	return plan.String(), true
+}
+
+// isExplainable reports whether the SQL query is a single SELECT statement with its values, i.e. without
+// placeholders
+func isExplainable(sql string) bool {
+	explainable, first, ended := true, true, false
+	tokenize(sql, func(t token) bool {
+		switch {
+		case t.kind == tokenSpace || t.kind == tokenComment:
+			return true
+		case ended || t.kind == tokenPlaceholder:
+			// A stacked statement, or a parameterized query
+			explainable = false
+			return false
+		case first:
+			first = false
+			explainable = t.isKeyword("SELECT")
+			return explainable
+		case t.kind == tokenPunctuation && t.text == ";":
+			ended = true
+		}
+		return true
+	})
+	return explainable && !first
+}
+
+// analyzeForbidden are the keywords of the clauses which forbid EXPLAIN ANALYZE, as it executes the query:
+// the locking clauses and the SELECT INTO creating a table or writing to a file
+var analyzeForbidden = []string{"INTO", "UPDATE", "SHARE", "LOCK"}
+
+// isAnalyzable reports whether the SQL query can be explained with EXPLAIN ANALYZE, see isExplainable
+func isAnalyzable(sql string) bool {
+	if !isExplainable(sql) {
+		return false
+	}
+	analyzable := true
+	tokenize(sql, func(t token) bool {
+		for _, keyword := range analyzeForbidden {
+			if t.isKeyword(keyword) {
+				analyzable = false
+				return false
+			}
+		}
+		return true
+	})
+	return analyzable
+}
@@ -0,0 +1,228 @@
+package slogGorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_ExplainOnSlow(t *testing.T) {
+	db := openTestDB(t, New(WithHandler(NewDummyHandler())).(*logger))
+	traceSlow := func(l *logger, ctx context.Context, sql string) {
+		l.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Plan attribute", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithExplainOnSlow(db),
+		})
+
+		traceSlow(gormLogger, context.Background(), "SELECT * FROM users WHERE id = 1")
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(PlanField, "Seq Scan on users\n  Filter: (id = 1)"))
+	})
+
+	t.Run("Companion record", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithExplainOnSlow(db, WithExplainRecord(), WithExplainPrefix("EXPLAIN QUERY PLAN ")),
+		})
+
+		traceSlow(gormLogger, context.Background(), "SELECT * FROM users WHERE id = 1")
+
+		require.Equal(t, 2, receiver.Len())
+		assertNoAttr(t, &receiver.Records[0], PlanField)
+		plan := resolveRecord(receiver.Records[1])
+		assert.Equal(t, ExplainMessage, plan.Message)
+		assert.Equal(t, slog.LevelWarn, plan.Level)
+		assertHasAttr(t, &plan, slog.String(QueryField, "SELECT * FROM users WHERE id = 1"))
+		assertHasAttr(t, &plan, slog.String(PlanField, "id=2 detail=SCAN users"))
+	})
+
+	t.Run("Rate limited", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithExplainOnSlow(db, WithExplainInterval(time.Hour)),
+		})
+
+		traceSlow(gormLogger, context.Background(), "SELECT * FROM users WHERE id = 1")
+		traceSlow(gormLogger, context.Background(), "SELECT * FROM users WHERE id = 2")
+
+		require.Equal(t, 2, receiver.Len())
+		assertHasAttr(t, &receiver.Records[0], slog.String(PlanField, "Seq Scan on users\n  Filter: (id = 1)"))
+		assertNoAttr(t, &receiver.Records[1], PlanField)
+	})
+
+	t.Run("Not explained", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			options []Option
+			sql     string
+		}{
+			{name: "Write", sql: "DELETE FROM users WHERE id = 1"},
+			{name: "Stacked statements", sql: "SELECT 1; DROP TABLE users"},
+			{name: "Parameterized query", sql: "SELECT * FROM users WHERE id = ?"},
+			{name: "No values", options: []Option{WithNoValues()}, sql: "SELECT * FROM users WHERE id = 1"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				receiver, gormLogger := getReceiverAndLogger(append(tt.options,
+					WithSlowThreshold(time.Millisecond),
+					WithExplainOnSlow(db),
+				))
+
+				traceSlow(gormLogger, context.Background(), tt.sql)
+
+				require.Equal(t, 1, receiver.Len())
+				assertNoAttr(t, receiver.Record, PlanField)
+			})
+		}
+	})
+
+	t.Run("Invalid database", func(t *testing.T) {
+		_, err := NewE(WithExplainOnSlow(nil))
+
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+}
+
+func Test_logger_ExplainAnalyze(t *testing.T) {
+	db := openTestDB(t, New(WithHandler(NewDummyHandler())).(*logger))
+	trace := func(l *logger, ctx context.Context, sql string) {
+		l.Trace(ctx, time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Flagged request", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithExplainOnSlow(db)})
+		ctx := ContextWithExplainAnalyze(context.Background())
+
+		trace(gormLogger, ctx, "SELECT * FROM users WHERE id = 1")
+		trace(gormLogger, ctx, "SELECT * FROM users WHERE id = 2")
+
+		require.Equal(t, 1, receiver.Len())
+		plan := resolveRecord(receiver.Records[0])
+		assert.Equal(t, ExplainMessage, plan.Message)
+		assertHasAttr(t, &plan, slog.String(QueryField, "SELECT * FROM users WHERE id = 1"))
+		assertHasAttr(t, &plan, slog.String(PlanField, "Seq Scan on users\n  Filter: (id = 1)"))
+		assertHasAttr(t, &plan, slog.Bool(AnalyzeField, true))
+	})
+
+	t.Run("Not flagged", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithExplainOnSlow(db)})
+
+		trace(gormLogger, context.Background(), "SELECT * FROM users WHERE id = 1")
+
+		assert.Equal(t, 0, receiver.Len())
+	})
+
+	t.Run("Unsafe query skipped", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithExplainOnSlow(db)})
+		ctx := ContextWithExplainAnalyze(context.Background())
+
+		trace(gormLogger, ctx, "SELECT * FROM users WHERE id = 1 FOR UPDATE")
+		trace(gormLogger, ctx, "SELECT * FROM users WHERE id = 2")
+
+		require.Equal(t, 1, receiver.Len())
+		plan := resolveRecord(receiver.Records[0])
+		assertHasAttr(t, &plan, slog.String(QueryField, "SELECT * FROM users WHERE id = 2"))
+	})
+
+	t.Run("Without explainer", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+
+		trace(gormLogger, ContextWithExplainAnalyze(context.Background()), "SELECT * FROM users WHERE id = 1")
+
+		assert.Equal(t, 0, receiver.Len())
+	})
+}
+
+func Test_isAnalyzable(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{sql: "SELECT * FROM users WHERE name = 'for update'", want: true},
+		{sql: "SELECT * FROM users FOR UPDATE", want: false},
+		{sql: "SELECT * FROM users FOR SHARE", want: false},
+		{sql: "SELECT * FROM users LOCK IN SHARE MODE", want: false},
+		{sql: "SELECT * INTO archive FROM users", want: false},
+		{sql: "DELETE FROM users", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, isAnalyzable(tt.sql))
+		})
+	}
+}
+
+func Test_isExplainable(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{sql: "SELECT * FROM users WHERE name = 'a;b'", want: true},
+		{sql: "/* list */ select * from users;", want: true},
+		{sql: "SELECT * FROM users WHERE id = $1", want: false},
+		{sql: "UPDATE users SET name = 'john'", want: false},
+		{sql: "SELECT 1; SELECT 2", want: false},
+		{sql: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, isExplainable(tt.sql))
+		})
+	}
+}
+
+// Mock
+
+// fakePlanRows are the rows of an EXPLAIN statement of the fake driver: the plan of a sequential scan like
+// PostgreSQL, or like SQLite for EXPLAIN QUERY PLAN
+type fakePlanRows struct {
+	query string
+	lines []string
+	read  bool
+}
+
+func (r *fakePlanRows) Columns() []string {
+	if strings.HasPrefix(r.query, "EXPLAIN QUERY PLAN ") {
+		return []string{"id", "detail"}
+	}
+	return []string{"QUERY PLAN"}
+}
+
+func (r *fakePlanRows) Close() error {
+	return nil
+}
+
+func (r *fakePlanRows) Next(dest []driver.Value) error {
+	if len(dest) == 2 {
+		if r.read {
+			return io.EOF
+		}
+		r.read = true
+		dest[0], dest[1] = int64(2), "SCAN users"
+		return nil
+	}
+	if !r.read {
+		r.read = true
+		r.lines = []string{"Seq Scan on users", "  Filter: (id = " + r.query[len(r.query)-1:] + ")"}
+	}
+	if len(r.lines) == 0 {
+		return io.EOF
+	}
+	dest[0], r.lines = r.lines[0], r.lines[1:]
+	return nil
+}
@@ -0,0 +1,85 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// failoverHandler writes the records with the primary handler, and the ones it fails to write with the
+// fallback handler, if any, see WithFallbackHandler
+type failoverHandler struct {
+	primary  slog.Handler
+	fallback slog.Handler
+	// failures counts the records the primary handler failed to write, the last error being kept in lastError
+	failures  *atomic.Uint64
+	lastError *handlerError
+	clock     Clock
+}
+
+// handlerError is the last error of the handler, shared by the copies of the logger, see Health
+type handlerError struct {
+	mu  sync.Mutex
+	err error
+	at  time.Time
+}
+
+// set records the error of the handler at the given time
+func (e *handlerError) set(err error, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err, e.at = err, at
+}
+
+// get returns the last error of the handler and its time, nil if the handler never failed
+func (e *handlerError) get() (error, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err, e.at
+}
+
+// Enabled implements slog.Handler, following the primary handler
+func (h *failoverHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.primary.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *failoverHandler) Handle(ctx context.Context, r slog.Record) error {
+	// The record is cloned, as the primary handler may have retained it before failing
+	var fallback slog.Record
+	if h.fallback != nil {
+		fallback = r.Clone()
+	}
+	err := h.primary.Handle(ctx, r)
+	if err == nil {
+		return nil
+	}
+	h.failures.Add(1)
+	h.lastError.set(err, h.clock.Now())
+	if h.fallback == nil || !h.fallback.Enabled(ctx, r.Level) {
+		return err
+	}
+	return h.fallback.Handle(ctx, fallback)
+}
+
+// WithAttrs implements slog.Handler
+func (h *failoverHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.primary = h.primary.WithAttrs(attrs)
+	if h.fallback != nil {
+		c.fallback = h.fallback.WithAttrs(attrs)
+	}
+	return &c
+}
+
+// WithGroup implements slog.Handler
+func (h *failoverHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.primary = h.primary.WithGroup(name)
+	if h.fallback != nil {
+		c.fallback = h.fallback.WithGroup(name)
+	}
+	return &c
+}
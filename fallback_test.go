@@ -0,0 +1,76 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_FallbackHandler(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("Failing handler", func(t *testing.T) {
+		fallback := NewDummyHandler()
+		gormLogger := New(WithHandler(failingHandler{}), WithFallbackHandler(fallback), WithTraceAll()).(*logger)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, fallback.Record)
+		resolved := resolveRecord(*fallback.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT * FROM users"))
+		assert.Equal(t, uint64(1), gormLogger.Stats().HandlerFailures)
+	})
+
+	t.Run("Handler succeeding", func(t *testing.T) {
+		fallback := NewDummyHandler()
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithFallbackHandler(fallback), WithTraceAll()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		assert.NotNil(t, receiver.Record)
+		assert.Nil(t, fallback.Record)
+		assert.Zero(t, gormLogger.Stats().HandlerFailures)
+	})
+
+	t.Run("Without fallback handler", func(t *testing.T) {
+		gormLogger := New(WithHandler(failingHandler{}), WithTraceAll()).(*logger)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		assert.Equal(t, uint64(1), gormLogger.Stats().HandlerFailures)
+	})
+
+	t.Run("Asynchronous mode", func(t *testing.T) {
+		fallback := NewDummyHandler()
+		gormLogger := New(WithHandler(failingHandler{}), WithFallbackHandler(fallback), WithTraceAll(), WithAsync(4)).(*logger)
+		defer gormLogger.Close()
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		gormLogger.Flush()
+
+		assert.Equal(t, 1, fallback.Len())
+		assert.Equal(t, uint64(1), gormLogger.Stats().HandlerFailures)
+	})
+}
+
+// Mock
+
+// failingHandler fails to write the records
+type failingHandler struct{}
+
+func (failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (failingHandler) Handle(context.Context, slog.Record) error {
+	return errors.New("connection refused")
+}
+
+func (h failingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h failingHandler) WithGroup(string) slog.Handler { return h }
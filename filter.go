@@ -0,0 +1,139 @@
+package slogGorm
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueryInfo describes a traced SQL query, given to the filters
+type QueryInfo struct {
+	Type      LogType
+	SQL       string
+	Operation string
+	Table     string
+	Tables    []string // all the tables referenced by the SQL query, including its joins and subqueries
+	Rows      int64
+	Duration  time.Duration
+}
+
+// The costs of the filters: the filter chain evaluates the cheapest filters first
+const (
+	operationFilterCost = iota + 1
+	tableFilterCost
+	regexpFilterCost
+	funcFilterCost
+)
+
+// queryFilter is a compiled filter of the filter chain
+type queryFilter struct {
+	cost int
+	// name describes the filter in the decisions logged, see WithDecisionDebug
+	name string
+	// ignore reports whether the query must not be logged
+	ignore func(ctx context.Context, logType LogType, elapsed time.Duration, q *lazyQuery) bool
+	// describe adds the filter to the configuration, if it can be described
+	describe func(c *Config)
+}
+
+// compileFilters sorts the filter chain, the cheapest filters first. The filters
+// with the same cost keep the order in which they were registered.
+func (l *logger) compileFilters() {
+	sort.SliceStable(l.filters, func(i, j int) bool {
+		return l.filters[i].cost < l.filters[j].cost
+	})
+}
+
+// filteredBy evaluates the filter chain and reports whether the query must not be logged, with the name
+// of the filter ignoring it. The evaluation stops at the first filter ignoring the query.
+func (l logger) filteredBy(ctx context.Context, logType LogType, elapsed time.Duration, q *lazyQuery) (string, bool) {
+	for i := range l.filters {
+		if l.filters[i].ignore(ctx, logType, elapsed, q) {
+			return l.filters[i].name, true
+		}
+	}
+	return "", false
+}
+
+// newOperationFilter returns a filter ignoring the queries with the given operations
+func newOperationFilter(operations []string) queryFilter {
+	set := make(map[string]struct{}, len(operations))
+	for _, operation := range operations {
+		set[strings.ToUpper(operation)] = struct{}{}
+	}
+
+	return queryFilter{
+		cost: operationFilterCost,
+		name: "ignored operations " + strings.Join(operations, ", "),
+		describe: func(c *Config) {
+			c.IgnoredOperations = append(c.IgnoredOperations, operations...)
+		},
+		ignore: func(_ context.Context, _ LogType, _ time.Duration, q *lazyQuery) bool {
+			_, ok := set[q.Operation()]
+			return ok
+		},
+	}
+}
+
+// newTableFilter returns a filter ignoring the queries on the given tables
+func newTableFilter(tables []string) queryFilter {
+	set := make(map[string]struct{}, len(tables))
+	for _, table := range tables {
+		set[strings.ToLower(table)] = struct{}{}
+	}
+
+	return queryFilter{
+		cost: tableFilterCost,
+		name: "ignored tables " + strings.Join(tables, ", "),
+		describe: func(c *Config) {
+			c.IgnoredTables = append(c.IgnoredTables, tables...)
+		},
+		ignore: func(_ context.Context, _ LogType, _ time.Duration, q *lazyQuery) bool {
+			table := strings.ToLower(q.Table())
+			if _, ok := set[table]; ok {
+				return true
+			}
+			// Also match the unqualified name of the table
+			if i := strings.LastIndexByte(table, '.'); i >= 0 {
+				_, ok := set[table[i+1:]]
+				return ok
+			}
+			return false
+		},
+	}
+}
+
+// newRegexpFilter returns a filter ignoring the queries matching the pattern
+func newRegexpFilter(pattern *regexp.Regexp) queryFilter {
+	return queryFilter{
+		cost: regexpFilterCost,
+		name: "ignored query " + pattern.String(),
+		describe: func(c *Config) {
+			c.IgnoredQueries = append(c.IgnoredQueries, pattern.String())
+		},
+		ignore: func(_ context.Context, _ LogType, _ time.Duration, q *lazyQuery) bool {
+			return pattern.MatchString(q.SQL())
+		},
+	}
+}
+
+// newFuncFilter returns a filter ignoring the queries for which fn returns false
+func newFuncFilter(fn func(ctx context.Context, query QueryInfo) bool) queryFilter {
+	return queryFilter{
+		cost: funcFilterCost,
+		name: "query filter",
+		ignore: func(ctx context.Context, logType LogType, elapsed time.Duration, q *lazyQuery) bool {
+			return !fn(ctx, QueryInfo{
+				Type:      logType,
+				SQL:       q.SQL(),
+				Operation: q.Operation(),
+				Table:     q.Table(),
+				Tables:    q.Tables(),
+				Rows:      q.Rows(),
+				Duration:  elapsed,
+			})
+		},
+	}
+}
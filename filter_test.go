@@ -0,0 +1,117 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Trace_Filters(t *testing.T) {
+	fc := func(sql string) func() (string, int64) {
+		return func() (string, int64) {
+			return sql, 1
+		}
+	}
+
+	tests := []struct {
+		name         string
+		options      []Option
+		sql          string
+		err          error
+		wantNoRecord bool
+	}{
+		{
+			name:         "Ignored operation",
+			options:      []Option{WithIgnoredOperations("select")},
+			sql:          "SELECT * FROM users",
+			wantNoRecord: true,
+		},
+		{
+			name:    "Operation not ignored",
+			options: []Option{WithIgnoredOperations("SELECT")},
+			sql:     "DELETE FROM users",
+		},
+		{
+			name:         "Ignored table",
+			options:      []Option{WithIgnoredTables("Users")},
+			sql:          "SELECT * FROM `users`",
+			wantNoRecord: true,
+		},
+		{
+			name:         "Ignored unqualified table",
+			options:      []Option{WithIgnoredTables("users")},
+			sql:          `SELECT * FROM "public"."users"`,
+			wantNoRecord: true,
+		},
+		{
+			name:    "Table not ignored",
+			options: []Option{WithIgnoredTables("users")},
+			sql:     "SELECT * FROM orders",
+		},
+		{
+			name:         "Ignored query",
+			options:      []Option{WithIgnoredQueries(regexp.MustCompile(`^SELECT 1$`))},
+			sql:          "SELECT 1",
+			wantNoRecord: true,
+		},
+		{
+			name: "Ignored by a function",
+			options: []Option{WithQueryFilter(func(ctx context.Context, query QueryInfo) bool {
+				return query.Table != "users"
+			})},
+			sql:          "SELECT * FROM users",
+			wantNoRecord: true,
+		},
+		{
+			name:    "Errors are never ignored",
+			options: []Option{WithIgnoredTables("users")},
+			sql:     "SELECT * FROM users",
+			err:     fmt.Errorf("awesome error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver, gormLogger := getReceiverAndLogger(append(tt.options, WithTraceAll()))
+
+			gormLogger.Trace(context.Background(), time.Now(), fc(tt.sql), tt.err)
+
+			if tt.wantNoRecord {
+				assert.Nil(t, receiver.Record)
+			} else {
+				assert.NotNil(t, receiver.Record)
+			}
+		})
+	}
+}
+
+func Test_logger_compileFilters(t *testing.T) {
+	var evaluated []string
+	_, gormLogger := getReceiverAndLogger([]Option{
+		WithTraceAll(),
+		WithQueryFilter(func(ctx context.Context, query QueryInfo) bool {
+			evaluated = append(evaluated, "func")
+			return true
+		}),
+		WithIgnoredQueries(regexp.MustCompile(`never`)),
+		WithIgnoredTables("never"),
+		WithIgnoredOperations("NEVER"),
+	})
+
+	require.Len(t, gormLogger.filters, 4)
+	for i, cost := range []int{operationFilterCost, tableFilterCost, regexpFilterCost, funcFilterCost} {
+		assert.Equal(t, cost, gormLogger.filters[i].cost)
+	}
+
+	// Short-circuit: the function is not evaluated once the query is ignored
+	gormLogger.filters = append([]queryFilter{newOperationFilter([]string{"SELECT"})}, gormLogger.filters...)
+	gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+	assert.Empty(t, evaluated)
+}
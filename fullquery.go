@@ -0,0 +1,74 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// fullQueryContextKey is the context key under which the full SQL query of a statement is captured
+type fullQueryContextKey struct{}
+
+// fullQuery captures the SQL query and the parameters of a statement filtered by ParamsFilter,
+// to log the full SQL query in a companion record
+type fullQuery struct {
+	dialector gorm.Dialector
+	captured  bool
+	sql       string
+	vars      []any
+}
+
+// fullQueryFromContext returns the full SQL query captured for the statement, if any
+func fullQueryFromContext(ctx context.Context) *fullQuery {
+	q, _ := ctx.Value(fullQueryContextKey{}).(*fullQuery)
+	return q
+}
+
+// captureFullQuery returns a callback preparing the capture of the full SQL query of the
+// statement, when it is logged by a companion record.
+func (l logger) captureFullQuery() func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		l := l.snapshot()
+		if !l.debugFullQueries || l.noValues || db.Dialector == nil {
+			return
+		}
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if l.silenced(FullQueryLogType) || !l.enabled(ctx, l.logLevel[FullQueryLogType]) {
+			return
+		}
+		db.Statement.Context = context.WithValue(ctx, fullQueryContextKey{}, &fullQuery{dialector: db.Dialector})
+	}
+}
+
+// fullQueryAttrs returns the attributes of the companion record of the query, with its full SQL
+// query, or nil if it is not logged. The full SQL query is interpolated before Trace returns, as
+// gorm reuses the parameters of its statement.
+func (l logger) fullQueryAttrs(ctx context.Context, query *lazyQuery, attrs []slog.Attr) []slog.Attr {
+	captured := fullQueryFromContext(ctx)
+	if captured == nil || l.silenced(FullQueryLogType) || !l.enabled(ctx, l.logLevel[FullQueryLogType]) {
+		return nil
+	}
+
+	// Resolving the query calls ParamsFilter, which captures its parameters
+	query.resolve()
+	if !captured.captured {
+		return nil
+	}
+
+	sql := l.scrubSQL(l.redactReturning(l.maskSQL(l.stripComments(captured.dialector.Explain(captured.sql, captured.vars...)))))
+	l.verifyRedactionSQL(ctx, FullQueryLogType, sql)
+	full := slog.StringValue(sql)
+	companion := slices.Clone(attrs)
+	for i := range companion {
+		if companion[i].Key == QueryField {
+			companion[i].Value = full
+		}
+	}
+	return companion
+}
@@ -0,0 +1,58 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_DebugFullQueries(t *testing.T) {
+	t.Run("Companion record", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithDebugFullQueries(), WithAsync(8)})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE id = ?", 42).Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.Equal(t, 2, receiver.Len())
+		parameterized, full := receiver.Records[0], receiver.Records[1]
+		assert.Equal(t, slog.LevelInfo, parameterized.Level)
+		assertHasAttr(t, &parameterized, slog.String(QueryField, "DELETE FROM users WHERE id = ?"))
+		assert.Equal(t, slog.LevelDebug, full.Level)
+		assert.Equal(t, "full SQL query", full.Message)
+		assertHasAttr(t, &full, slog.String(QueryField, "DELETE FROM users WHERE id = 42"))
+	})
+
+	t.Run("Debug level disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithDebugFullQueries(),
+			WithAsync(8),
+			WithMinLevel(slog.LevelInfo),
+		})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE id = ?", 42).Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(QueryField, "DELETE FROM users WHERE id = ?"))
+	})
+
+	t.Run("Without plugin mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithDebugFullQueries()})
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			sql, _ := gormLogger.ParamsFilter(context.Background(), "DELETE FROM users WHERE id = ?", 42)
+			return sql, 1
+		}, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "DELETE FROM users WHERE id = ?"))
+	})
+}
@@ -0,0 +1,31 @@
+package slogGorm
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// goroutineID returns the ID of the calling goroutine, read from the header of its stack trace
+// ("goroutine 42 [running]:"), 0 if it cannot be read. The runtime does not expose it on purpose: it is
+// only logged to debug the concurrency issues, see WithGoroutineID.
+func goroutineID() uint64 {
+	var buf [64]byte
+	return parseGoroutineID(buf[:runtime.Stack(buf[:], false)])
+}
+
+// parseGoroutineID parses the ID of the goroutine from the header of its stack trace, 0 if it cannot be parsed
+func parseGoroutineID(stack []byte) uint64 {
+	stack, ok := bytes.CutPrefix(stack, []byte("goroutine "))
+	if !ok {
+		return 0
+	}
+	if i := bytes.IndexByte(stack, ' '); i >= 0 {
+		stack = stack[:i]
+	}
+	id, err := strconv.ParseUint(string(stack), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,75 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseGoroutineID(t *testing.T) {
+	tests := map[string]struct {
+		stack    string
+		expected uint64
+	}{
+		"Header":        {stack: "goroutine 42 [running]:\nmain.main()", expected: 42},
+		"Truncated":     {stack: "goroutine 1234567", expected: 1234567},
+		"Missing":       {stack: "", expected: 0},
+		"Not an ID":     {stack: "goroutine abc [running]:", expected: 0},
+		"Other prefix":  {stack: "thread 42 [running]:", expected: 0},
+		"Truncated ID":  {stack: "goroutine ", expected: 0},
+		"Negative":      {stack: "goroutine -1 [running]:", expected: 0},
+		"Leading space": {stack: " goroutine 42 [running]:", expected: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseGoroutineID([]byte(tt.stack)))
+		})
+	}
+}
+
+func Test_goroutineID(t *testing.T) {
+	ids := make(chan uint64, 2)
+	for range [2]struct{}{} {
+		go func() { ids <- goroutineID() }()
+	}
+
+	first, second := <-ids, <-ids
+
+	assert.NotZero(t, first)
+	assert.NotZero(t, second)
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, goroutineID(), first)
+}
+
+func Test_logger_GoroutineID(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithGoroutineID(), WithTraceAll()})
+
+		ids := make(chan uint64)
+		go func() {
+			gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+			ids <- goroutineID()
+		}()
+		id := <-ids
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.Uint64(GoroutineIDField, id))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.NotContains(t, startupValues(*receiver.Record), GoroutineIDField)
+	})
+}
@@ -0,0 +1,55 @@
+package slogGorm
+
+import "time"
+
+// Health reports whether the SQL logging is functioning, e.g. for the readiness probes and the dashboards
+type Health struct {
+	// Processed is the number of SQL queries traced by gorm, logged or not
+	Processed uint64
+	// SampledOut is the number of SQL records discarded by the sampling, see WithSamplingRate and WithVolumeBudget
+	SampledOut uint64
+	// Filtered is the number of SQL records suppressed by the filters, see WithIgnoredOperations and WithQueryFilter
+	Filtered uint64
+	// Dropped is the number of records discarded by the asynchronous mode because its buffer was full
+	Dropped uint64
+	// QueueDepth is the number of records waiting to be written in asynchronous mode, out of QueueCapacity
+	QueueDepth    int
+	QueueCapacity int
+	// HandlerFailures is the number of records the handler failed to write
+	HandlerFailures uint64
+	// LastHandlerError is the last error of the handler, at LastHandlerErrorTime, nil if it never failed
+	LastHandlerError     error
+	LastHandlerErrorTime time.Time
+}
+
+// Health reports the internal health metrics of the logger, shared by its copies and the loggers derived with With
+//
+// Usage:
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//		health := gormLogger.Health()
+//		if health.LastHandlerError != nil && time.Since(health.LastHandlerErrorTime) < time.Minute {
+//			http.Error(w, health.LastHandlerError.Error(), http.StatusServiceUnavailable)
+//		}
+//	})
+func (l logger) Health() Health {
+	l = l.snapshot()
+	stats := l.counterStats()
+	health := Health{
+		SampledOut:      stats.SampledOut,
+		Filtered:        stats.Filtered,
+		Dropped:         stats.Dropped,
+		HandlerFailures: stats.HandlerFailures,
+	}
+	if l.counters != nil {
+		health.Processed = l.counters.processed.Load()
+	}
+	if l.async != nil {
+		health.QueueDepth = len(l.async.queue)
+		health.QueueCapacity = cap(l.async.queue)
+	}
+	if l.lastHandlerError != nil {
+		health.LastHandlerError, health.LastHandlerErrorTime = l.lastHandlerError.get()
+	}
+	return health
+}
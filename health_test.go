@@ -0,0 +1,59 @@
+package slogGorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Health(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Counters", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithIgnoredTables("sessions")})
+
+		trace(gormLogger, "SELECT * FROM sessions")
+		trace(gormLogger, "SELECT * FROM users")
+		trace(gormLogger.with(WithSamplingRate(0)), "SELECT * FROM users")
+
+		health := gormLogger.Health()
+		assert.Equal(t, uint64(3), health.Processed)
+		assert.Equal(t, uint64(1), health.Filtered)
+		assert.Equal(t, uint64(1), health.SampledOut)
+		assert.Zero(t, health.QueueCapacity)
+		assert.NoError(t, health.LastHandlerError)
+	})
+
+	t.Run("Last handler error", func(t *testing.T) {
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		gormLogger := New(WithHandler(failingHandler{}), WithTraceAll(), WithClock(fixedClock{now: now})).(*logger)
+
+		trace(gormLogger, "SELECT * FROM users")
+
+		health := gormLogger.Health()
+		assert.Equal(t, uint64(1), health.HandlerFailures)
+		assert.EqualError(t, health.LastHandlerError, "connection refused")
+		assert.Equal(t, now, health.LastHandlerErrorTime)
+	})
+
+	t.Run("Queue depth", func(t *testing.T) {
+		blocking := newBlockingHandler()
+		gormLogger := New(WithHandler(blocking), WithTraceAll(), WithAsync(4)).(*logger)
+		defer gormLogger.Close()
+		defer close(blocking.release)
+
+		trace(gormLogger, "SELECT 1")
+		<-blocking.entered
+		trace(gormLogger, "SELECT 2")
+		trace(gormLogger, "SELECT 3")
+
+		health := gormLogger.Health()
+		require.Equal(t, 4, health.QueueCapacity)
+		assert.Equal(t, 2, health.QueueDepth)
+	})
+}
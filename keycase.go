@@ -0,0 +1,177 @@
+package slogGorm
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// KeyCase defines the casing convention of the keys of the attributes, see WithKeyCase
+type KeyCase int
+
+const (
+	// OriginalCase keeps the keys as defined (default)
+	OriginalCase KeyCase = iota
+	// SnakeCase converts the keys like "slow_threshold"
+	SnakeCase
+	// CamelCase converts the keys like "slowThreshold"
+	CamelCase
+	// KebabCase converts the keys like "slow-threshold"
+	KebabCase
+)
+
+// keyCaseNames are the names of the key casing conventions in the configuration files
+var keyCaseNames = map[KeyCase]string{
+	OriginalCase: "original",
+	SnakeCase:    "snake",
+	CamelCase:    "camel",
+	KebabCase:    "kebab",
+}
+
+// String returns the name of the key casing convention
+func (c KeyCase) String() string {
+	if name, ok := keyCaseNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("KeyCase(%d)", int(c))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (c KeyCase) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (c *KeyCase) UnmarshalText(text []byte) error {
+	for keyCase, name := range keyCaseNames {
+		if strings.EqualFold(string(text), name) {
+			*c = keyCase
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key case %q", text)
+}
+
+// keyCaseCache caches the converted keys, as the same keys are logged by each record
+var keyCaseCache sync.Map
+
+// keyCaseCacheKey is the key of a converted key in keyCaseCache
+type keyCaseCacheKey struct {
+	keyCase KeyCase
+	key     string
+}
+
+// convert returns the key following the casing convention. The segments of the namespaced
+// keys (e.g. "db.statement") are converted separately.
+func (c KeyCase) convert(key string) string {
+	if c == OriginalCase || key == "" {
+		return key
+	}
+	if converted, ok := keyCaseCache.Load(keyCaseCacheKey{c, key}); ok {
+		return converted.(string)
+	}
+
+	var b strings.Builder
+	b.Grow(len(key))
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		if i < len(key) && key[i] != '.' && key[i] != '/' {
+			continue
+		}
+		c.convertSegment(&b, key[start:i])
+		if i < len(key) {
+			b.WriteByte(key[i])
+		}
+		start = i + 1
+	}
+
+	converted := b.String()
+	keyCaseCache.Store(keyCaseCacheKey{c, key}, converted)
+	return converted
+}
+
+// convertSegment writes the words of the segment following the casing convention
+func (c KeyCase) convertSegment(b *strings.Builder, segment string) {
+	for i, word := range splitWords(segment) {
+		switch c {
+		case SnakeCase:
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteString(strings.ToLower(word))
+		case KebabCase:
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteString(strings.ToLower(word))
+		case CamelCase:
+			word = strings.ToLower(word)
+			if i > 0 {
+				runes := []rune(word)
+				runes[0] = unicode.ToUpper(runes[0])
+				word = string(runes)
+			}
+			b.WriteString(word)
+		}
+	}
+}
+
+// splitWords splits a key into its words, separated by underscores, hyphens or spaces, or by
+// the changes of case (e.g. "requestID" and "HTTPStatus").
+func splitWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(s)
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = word[:0]
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(word))
+				word = word[:0]
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// convertAttrs converts the keys of the attributes in place, including the attributes of groups
+func (c KeyCase) convertAttrs(attrs []slog.Attr) []slog.Attr {
+	if c == OriginalCase {
+		return attrs
+	}
+	for i, attr := range attrs {
+		attrs[i] = c.convertAttr(attr)
+	}
+	return attrs
+}
+
+// convertAttr converts the key of the attribute, and the keys of its attributes for a group
+func (c KeyCase) convertAttr(attr slog.Attr) slog.Attr {
+	attr.Key = c.convert(attr.Key)
+	if attr.Value.Kind() == slog.KindGroup {
+		// The attributes of the group may be shared, they are copied
+		group := attr.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = c.convertAttr(a)
+		}
+		attr.Value = slog.GroupValue(attrs...)
+	}
+	return attr
+}
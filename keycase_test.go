@@ -0,0 +1,100 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCase_convert(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+		kebab string
+	}{
+		{key: "query", snake: "query", camel: "query", kebab: "query"},
+		{key: "slow_threshold", snake: "slow_threshold", camel: "slowThreshold", kebab: "slow-threshold"},
+		{key: "requestID", snake: "request_id", camel: "requestId", kebab: "request-id"},
+		{key: "HTTPStatus", snake: "http_status", camel: "httpStatus", kebab: "http-status"},
+		{key: "user-agent", snake: "user_agent", camel: "userAgent", kebab: "user-agent"},
+		{key: "db.operation_name", snake: "db.operation_name", camel: "db.operationName", kebab: "db.operation-name"},
+		{key: "", snake: "", camel: "", kebab: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.key, OriginalCase.convert(tt.key))
+			assert.Equal(t, tt.snake, SnakeCase.convert(tt.key))
+			assert.Equal(t, tt.camel, CamelCase.convert(tt.key))
+			assert.Equal(t, tt.kebab, KebabCase.convert(tt.key))
+		})
+	}
+}
+
+func TestKeyCase_Text(t *testing.T) {
+	for _, keyCase := range []KeyCase{OriginalCase, SnakeCase, CamelCase, KebabCase} {
+		text, err := keyCase.MarshalText()
+		require.NoError(t, err)
+
+		var actual KeyCase
+		require.NoError(t, actual.UnmarshalText(text))
+		assert.Equal(t, keyCase, actual)
+	}
+
+	assert.Equal(t, "camel", CamelCase.String())
+	assert.Equal(t, "KeyCase(42)", KeyCase(42).String())
+	assert.Error(t, new(KeyCase).UnmarshalText([]byte("unknown")))
+}
+
+func Test_logger_KeyCase(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "request_id", "42")
+	options := []Option{
+		WithTraceAll(),
+		WithKeyCase(CamelCase),
+		WithContextValue("request_id", "request_id"),
+		WithContextFunc("request", func(ctx context.Context) (slog.Value, bool) {
+			return slog.GroupValue(slog.String("user_agent", "curl")), true
+		}),
+	}
+
+	t.Run("Trace", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "SELECT * FROM users", 1
+		}, errors.New("an error"))
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String("requestId", "42"))
+		assertHasAttr(t, &resolved, slog.Group("request", slog.String("userAgent", "curl")))
+		assertHasAttr(t, &resolved, slog.Int64(RowsField, 1))
+		assertNoAttr(t, &resolved, "request_id")
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Info(ctx, "an info message")
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("requestId", "42"))
+	})
+
+	t.Run("Field preset", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(append(options, WithOtelSemconvFields()))
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "SELECT * FROM users", 1
+		}, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("request_id", "42"))
+	})
+}
@@ -0,0 +1,61 @@
+package slogGorm
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// liveConfig holds the configuration of a logger and its copies (e.g. the copies made by
+// gorm with LogMode) once reconfigured by Apply, replaced as a whole by each call.
+type liveConfig struct {
+	// mu serializes the calls to Apply
+	mu      sync.Mutex
+	current atomic.Pointer[logger]
+}
+
+// snapshot returns the current configuration of the logger, as updated by Apply.
+// The gorm level set by LogMode on this copy of the logger is kept.
+func (l logger) snapshot() logger {
+	if l.live == nil {
+		return l
+	}
+	current := l.live.current.Load()
+	if current == nil {
+		// Never reconfigured
+		return l
+	}
+	s := *current
+	s.gormLevel = l.gormLevel
+	return s
+}
+
+// Apply applies the given options to the logger and all its copies (e.g. the gorm DB
+// and its sessions) at runtime, without recreating the gorm DB. It is safe for concurrent
+// use: the queries being logged keep the previous configuration. The invalid options are
+// ignored. The loggers derived with With are not updated.
+//
+// If the options change the handler or the asynchronous mode, the previous background
+// worker is closed once its queued records are written.
+//
+// Usage:
+//
+//	gormLogger.Apply(slogGorm.WithTraceAll(), slogGorm.WithSlowThreshold(time.Second))
+func (l logger) Apply(options ...Option) {
+	if l.live == nil {
+		return
+	}
+
+	l.live.mu.Lock()
+	current := l.live.current.Load()
+	if current == nil {
+		current = &l
+	}
+	next := current.with(options...)
+	next.live = l.live
+	l.live.current.Store(next)
+	l.live.mu.Unlock()
+
+	if current.async != nil && current.async != next.async {
+		current.async.close()
+	}
+}
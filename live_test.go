@@ -0,0 +1,101 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func Test_logger_Apply(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	t.Run("Reconfigure the gorm DB", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+		assert.Nil(t, receiver.Record)
+
+		gormLogger.Apply(WithTraceAll(), SetLogLevel(DefaultLogType, slog.LevelDebug))
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, slog.LevelDebug, receiver.Record.Level)
+
+		// The transactions follow the new configuration
+		receiver.Reset()
+		require.NoError(t, db.Begin().Commit().Error)
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "transaction committed", receiver.Record.Message)
+	})
+
+	t.Run("Copies made by LogMode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		warn := gormLogger.LogMode(gormlogger.Warn)
+		debug := gormLogger.LogMode(gormlogger.Info)
+
+		gormLogger.Apply(WithSlowThreshold(time.Nanosecond))
+
+		warn.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+
+		// The gorm level of the copy is kept
+		assert.Equal(t, gormlogger.Info, debug.(logger).snapshot().gormLevel)
+	})
+
+	t.Run("Derived loggers are not updated", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger(nil)
+		derived := gormLogger.with()
+
+		gormLogger.Apply(WithTraceAll())
+
+		assert.True(t, gormLogger.snapshot().traceAll)
+		assert.False(t, derived.snapshot().traceAll)
+	})
+
+	t.Run("Replaced asynchronous mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithAsync(8)})
+		previous := gormLogger.async
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		gormLogger.Apply(WithAsync(0))
+
+		// The records queued by the previous background worker are written
+		assert.Equal(t, 1, receiver.Len())
+		assert.True(t, previous.closed)
+		assert.Nil(t, gormLogger.snapshot().async)
+	})
+
+	t.Run("Concurrent use", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger(nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+				}
+			}()
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					gormLogger.Apply(WithSlowThreshold(time.Duration(i*j)*time.Millisecond), WithTraceAll())
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.True(t, gormLogger.snapshot().traceAll)
+	})
+}
@@ -5,20 +5,47 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"runtime"
+	"slices"
+	"sync/atomic"
 	"time"
 
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
-	"gorm.io/gorm/utils"
 )
 
 type LogType string
 
 const (
-	ErrorLogType     LogType = "sql_error"
-	SlowQueryLogType LogType = "slow_query"
-	DefaultLogType   LogType = "default"
+	ErrorLogType           LogType = "sql_error"
+	SlowQueryLogType       LogType = "slow_query"
+	DefaultLogType         LogType = "default"
+	CommitLogType          LogType = "commit"
+	RollbackLogType        LogType = "rollback"
+	SavepointLogType       LogType = "savepoint"
+	LongTransactionLogType LogType = "long_transaction"
+	AsyncDropLogType       LogType = "async_drop"
+	FullQueryLogType       LogType = "full_query"
+	AuditLogType           LogType = "audit"
+	SecurityLogType        LogType = "security"
+	DangerousWriteLogType  LogType = "dangerous_write"
+	DDLLogType             LogType = "ddl"
+	StatsLogType           LogType = "stats"
+	StartupLogType         LogType = "startup"
+	DecisionLogType        LogType = "decision"
+	ExplainLogType         LogType = "explain"
+	PlanChangeLogType      LogType = "plan_change"
+	LargeQueryLogType      LogType = "large_query"
+	LargeResultLogType     LogType = "large_result"
+	// The log types of the messages of gorm itself, logged with Info, Warn and Error
+	GormInfoLogType  LogType = "gorm_info"
+	GormWarnLogType  LogType = "gorm_warn"
+	GormErrorLogType LogType = "gorm_error"
+	// EscalatedErrorLogType is the level of the SQL errors repeated above the threshold, see WithErrorEscalation
+	EscalatedErrorLogType LogType = "escalated_error"
+	// MaintenanceLogType is the level of the slow queries during the maintenance windows, see SuppressSlowUntil
+	MaintenanceLogType LogType = "maintenance"
 
 	SourceField    = "file"
 	ErrorField     = "error"
@@ -26,20 +53,144 @@ const (
 	DurationField  = "duration"
 	SlowQueryField = "slow_query"
 	RowsField      = "rows"
+	OperationField = "operation"
+	TableField     = "table"
+	ActorField     = "actor"
+	AuditSeqField  = "audit_seq"
+	AuditHashField = "audit_hash"
+	AnomaliesField = "anomalies"
+	PackageField   = "package"
+	FunctionField  = "function"
+
+	DiscardedStatementsField = "discarded_statements"
+	DangerousWriteField      = "dangerous_write"
+	SavepointField           = "savepoint"
+	StatementsField          = "statements"
+	TxStmtIndexField         = "tx_stmt_index"
+	DroppedField             = "dropped"
+	DroppedTotalField        = "dropped_total"
+	FilteredField            = "filtered"
+	SampledOutField          = "sampled_out"
+	SubscriptionDroppedField = "subscription_dropped"
+	HandlerFailuresField     = "handler_failures"
+	DecisionField            = "decision"
+	LogTypeField             = "log_type"
+	ThresholdField           = "threshold"
+	RecordLevelField         = "record_level"
+	SamplingRateField        = "sampling_rate"
+	FilterField              = "filter"
+	PlanField                = "plan"
+	AnalyzeField             = "analyze"
+	PreviousPlanField        = "previous_plan"
+	BindParamsField          = "bind_params"
+	QueryBytesField          = "query_bytes"
+	BatchSizeField           = "batch_size"
+	TablesField              = "tables"
+	RowLatencyField          = "row_latency"
+	FingerprintField         = "fingerprint"
+	BuildInfoField           = "slog_gorm"
+	VersionField             = "version"
+	FeaturesField            = "features"
+	TenantField              = "tenant_id"
+	NameField                = "logger_name"
+	GoroutineIDField         = "goroutine_id"
+	EscalatedField           = "escalated"
+	ErrorCountField          = "error_count"
+	MaintenanceField         = "maintenance"
 )
 
-// New creates a new logger for gorm.io/gorm
-func New(options ...Option) *logger {
+// Logger is the logger for gorm.io/gorm created by New, also usable as a gorm plugin (see Initialize),
+// so that the applications can inject a fake in their unit tests.
+//
+// The records of the SQL queries depend on the tracing (see WithTraceAll), the sampling, the filters and the
+// minimum level of the logger. The captures bypass them all and see every SQL query: WithAudit, WithSlowLog,
+// WithQueryLogFile, WithSlowQueryLogFile, WithQueryStats, WithRouteStats, WithJobStats, WithSink and WithTracer.
+// The detections bypass the tracing, so that they log even the SQL queries not traced: WithMaxQueryBytesWarn,
+// WithLargeResultThreshold, WithSecurityDetection (also bypassing the sampling) and WithDangerousWriteDetection
+// (also bypassing the sampling and the filters).
+type Logger interface {
+	gormlogger.Interface
+	gorm.Plugin
+
+	// With returns a copy of the logger with the given options applied
+	With(options ...Option) Logger
+	// Apply applies the given options to the logger and all its copies at runtime
+	Apply(options ...Option)
+	// Config returns a snapshot of the effective configuration of the logger
+	Config() Config
+	// Stats returns the internal counters of the logger
+	Stats() Stats
+	// Health reports whether the SQL logging is functioning
+	Health() Health
+	// Subscribe returns a channel receiving the events of the SQL queries traced
+	Subscribe(buffer int) (<-chan QueryEvent, func())
+	// LogStartupSummary logs the effective configuration of the logger and verifies the handler writes it
+	LogStartupSummary(ctx context.Context) error
+	// Flush waits until the records queued by the asynchronous mode are written
+	Flush()
+	// Close writes the records queued by the asynchronous mode and stops its background worker
+	Close() error
+	// SuppressSlowUntil downgrades the slow queries to the MaintenanceLogType level until the given time
+	SuppressSlowUntil(t time.Time)
+}
+
+// New creates a new logger for gorm.io/gorm. The invalid options are ignored, see NewE to report them.
+func New(options ...Option) Logger {
+	l := newLogger(options)
+	l.init()
+	register(l)
+	return l
+}
+
+// NewE creates a new logger for gorm.io/gorm, like New, but returns an error wrapping ErrInvalidOption
+// for each invalid option (e.g. a negative threshold, a nil pattern or an unknown LogType).
+func NewE(options ...Option) (Logger, error) {
+	l := newLogger(options)
+	if err := errors.Join(l.errs...); err != nil {
+		return nil, err
+	}
+	l.init()
+	register(l)
+	return l, nil
+}
+
+// newLogger creates a logger with the default configuration and applies the given options
+func newLogger(options []Option) *logger {
 	l := logger{
 		ignoreRecordNotFoundError: true,
 		errorField:                ErrorField,
 		sourceField:               SourceField,
+		sourceCacheSize:           defaultSourceCacheSize,
+		samplingRate:              1,
+		clock:                     systemClock{},
 
 		// log levels
 		logLevel: map[LogType]slog.Level{
-			ErrorLogType:     slog.LevelError,
-			SlowQueryLogType: slog.LevelWarn,
-			DefaultLogType:   slog.LevelInfo,
+			ErrorLogType:           slog.LevelError,
+			SlowQueryLogType:       slog.LevelWarn,
+			DefaultLogType:         slog.LevelInfo,
+			CommitLogType:          slog.LevelInfo,
+			RollbackLogType:        slog.LevelWarn,
+			SavepointLogType:       slog.LevelInfo,
+			LongTransactionLogType: slog.LevelWarn,
+			AsyncDropLogType:       slog.LevelWarn,
+			FullQueryLogType:       slog.LevelDebug,
+			AuditLogType:           slog.LevelInfo,
+			SecurityLogType:        slog.LevelWarn,
+			DangerousWriteLogType:  slog.LevelError,
+			DDLLogType:             slog.LevelWarn,
+			StatsLogType:           slog.LevelInfo,
+			StartupLogType:         slog.LevelInfo,
+			DecisionLogType:        slog.LevelDebug,
+			ExplainLogType:         slog.LevelWarn,
+			PlanChangeLogType:      slog.LevelWarn,
+			LargeQueryLogType:      slog.LevelWarn,
+			LargeResultLogType:     slog.LevelWarn,
+			GormInfoLogType:        slog.LevelInfo,
+			GormWarnLogType:        slog.LevelWarn,
+			GormErrorLogType:       slog.LevelError,
+			EscalatedErrorLogType:  slog.LevelError + 4,
+			MaintenanceLogType:     slog.LevelInfo,
 		},
 		// The default logger of gorm uses warn as its default level,
 		// see https://github.com/go-gorm/gorm/blob/master/logger/logger.go
@@ -51,27 +202,264 @@ func New(options ...Option) *logger {
 		option(&l)
 	}
 
+	return &l
+}
+
+// init prepares the logger once configured
+func (l *logger) init() {
 	if l.sloggerHandler == nil {
 		// If no sloggerHandler is defined, use the default Handler
 		l.sloggerHandler = slog.Default().Handler()
 	}
 
-	return &l
+	l.compileFilters()
+
+	// The counters of the failures of the handler and of the suppressed records, and the last error of the
+	// handler, are shared with the logger derived from
+	if l.handlerFailures == nil {
+		l.handlerFailures = &atomic.Uint64{}
+	}
+	if l.lastHandlerError == nil {
+		l.lastHandlerError = &handlerError{}
+	}
+	if l.counters == nil {
+		l.counters = &counters{}
+	}
+	if l.maintenance == nil {
+		l.maintenance = &maintenanceWindow{}
+	}
+	if l.burstWindow <= 0 {
+		l.burst = nil
+	} else if l.burst == nil {
+		l.burst = &burstCapture{}
+	}
+	if l.escalationThreshold <= 0 {
+		l.escalation = nil
+	} else if l.escalation == nil {
+		l.escalation = newErrorEscalation()
+	}
+	if l.volumeBudget <= 0 {
+		l.budget = nil
+	} else if l.budget == nil || l.budget.limit != l.volumeBudget {
+		l.budget = newVolumeBudget(l.volumeBudget)
+	}
+	l.stamp = nil
+	if l.name != "" {
+		l.stamp = append(l.stamp, slog.String(NameField, l.name))
+	}
+	if l.buildInfo == buildInfoAllRecords {
+		l.stamp = append(l.stamp, l.buildInfoAttr())
+	}
+	l.stamp = l.convertKeys(l.stamp)
+
+	_, l.recordResource = l.splitResource()
+	l.output = &failoverHandler{
+		primary:   l.withResource(l.sloggerHandler),
+		fallback:  l.withResource(l.fallbackHandler),
+		failures:  l.handlerFailures,
+		lastError: l.lastHandlerError,
+		clock:     l.clock,
+	}
+
+	// The source cache, the asynchronous emitter and the subscriptions already set are shared with the
+	// logger derived from
+	if !l.capturesSource() || l.sourceCacheSize <= 0 {
+		l.sourceCache = nil
+	} else if l.sourceCache == nil {
+		l.sourceCache = newSourceCache(l.sourceCacheSize)
+	}
+
+	if l.asyncBufferSize <= 0 {
+		l.async = nil
+	} else if l.async == nil {
+		l.async = newAsyncEmitter(l.output, l.asyncBufferSize, l.asyncDropPolicy, l.logLevel[AsyncDropLogType], l.dropKeyCase())
+	}
+
+	if l.subscriptions == nil {
+		l.subscriptions = &subscriptions{}
+	}
+
+	l.live = &liveConfig{}
+}
+
+// With returns a copy of the logger with the given options applied, so that a base configuration
+// can be specialized (e.g. per gorm session or per database). The invalid options are ignored.
+//
+// The copy shares the asynchronous mode of the logger, unless the options change its handler, its resource
+// (see WithResource) or its asynchronous mode: the copy then has its own background worker, to close with
+// Close. A copy renamed with WithName is registered under its new name, see Get.
+//
+// Usage:
+//
+//	db.Session(&gorm.Session{Logger: gormLogger.With(slogGorm.WithTraceAll())})
+func (l logger) With(options ...Option) Logger {
+	c := l.with(options...)
+	if c.name != l.snapshot().name {
+		register(c)
+	}
+	return c
+}
+
+// with returns a copy of the logger with the given options applied, see With
+func (l logger) with(options ...Option) *logger {
+	l = l.snapshot()
+	c := l
+	c.logLevel = maps.Clone(l.logLevel)
+	c.messages = maps.Clone(l.messages)
+	c.contextAttrs = slices.Clone(l.contextAttrs)
+	c.filters = slices.Clone(l.filters)
+	c.maskedColumns = slices.Clone(l.maskedColumns)
+	c.sinks = slices.Clone(l.sinks)
+	c.resource = slices.Clone(l.resource)
+	c.handlerKeys = slices.Clone(l.handlerKeys)
+	c.slowThresholds = slices.Clone(l.slowThresholds)
+	c.policies = slices.Clone(l.policies)
+	c.shardedTables = slices.Clone(l.shardedTables)
+	c.silencedLogTypes = slices.Clone(l.silencedLogTypes)
+	c.errs = nil
+
+	// The handler is unset to detect whether the options define one
+	c.sloggerHandler = nil
+	for _, option := range options {
+		option(&c)
+	}
+	if c.sloggerHandler == nil {
+		c.sloggerHandler = l.sloggerHandler
+	} else {
+		c.async = nil
+	}
+	if c.fallbackHandler != l.fallbackHandler || !slices.EqualFunc(c.resource, l.resource, slog.Attr.Equal) ||
+		c.attrConflict != l.attrConflict {
+		c.async = nil
+	}
+
+	if c.sourceCacheSize != l.sourceCacheSize {
+		c.sourceCache = nil
+	}
+	if c.asyncBufferSize != l.asyncBufferSize || c.asyncDropPolicy != l.asyncDropPolicy ||
+		c.logLevel[AsyncDropLogType] != l.logLevel[AsyncDropLogType] || c.dropKeyCase() != l.dropKeyCase() {
+		c.async = nil
+	}
+
+	c.init()
+	return &c
 }
 
 type logger struct {
 	sloggerHandler            slog.Handler
+	fallbackHandler           slog.Handler
 	ignoreTrace               bool
 	ignoreRecordNotFoundError bool
 	traceAll                  bool
+	staticMessages            bool
+	metadataMode              MetadataMode
+	parameterizedQueries      bool
+	debugFullQueries          bool
 	slowThreshold             time.Duration
+	slowThresholds            []slowThresholdOverride
+	policies                  []tablePolicy
+	txWatchdogThreshold       time.Duration
+	clock                     Clock
 	logLevel                  map[LogType]slog.Level
 	gormLevel                 gormlogger.LogLevel
-	contextKeys               map[string]any
-	contextFuncs              map[string]func(context.Context) (slog.Value, bool)
+	contextAttrs              []contextAttr
+	filters                   []queryFilter
+	samplingRate              float64
+	minLevel                  slog.Leveler
+	preset                    FieldPreset
+	presetName                string
+	keyCase                   KeyCase
+	messages                  map[LogType]customMessage
+	scrubber                  Scrubber
+	noValues                  bool
+	allowedContextAttrs       []string
+	maskedColumns             []maskedColumn
+	redactionCheck            func(ctx context.Context, violation RedactionViolation)
+	auditHandler              slog.Handler
+	auditActor                func(ctx context.Context) (slog.Value, bool)
+	auditChain                *auditChain
+	slowLog                   *slowLog
+	queryLogFile              *QueryLogFile
+	slowQueryLogFile          *QueryLogFile
+	queryStats                *queryStats
+	routeStats                *attrStats
+	jobStats                  *attrStats
+	spanContext               func(ctx context.Context) (traceID, spanID string)
+	withoutSpanStatement      bool
+	tracer                    Tracer
+	shardedTables             []string
+	softDeleteFlag            bool
+	silencedLogTypes          []LogType
+	sinks                     []Sink
+	subscriptions             *subscriptions
+	securityDetection         bool
+	pprofLabels               bool
+	dangerousWriteDetection   bool
+	buildInfo                 buildInfoScope
+	name                      string
+	resource                  []slog.Attr
+	attrConflict              ContextAttrConflict
+	handlerKeys               []string
+	decisionDebug             bool
+	explainer                 *explainer
+	bindParamsCount           bool
+	queryBytes                bool
+	maxQueryBytes             int
+	largeResultThreshold      int64
+	batchMetrics              bool
+	returningRedaction        ReturningRedaction
+	withoutComments           bool
+	tables                    bool
+	goroutineID               bool
+	tenantLevel               func(ctx context.Context) (slog.Level, bool)
+	tenant                    func(ctx context.Context) (string, bool)
+	tenantSamplingRates       map[string]float64
+	logTypeSamplingRates      map[LogType]float64
+	burstWindow               time.Duration
+	escalationThreshold       int
+	escalationWindow          time.Duration
+	volumeBudget              int
+	maintenanceSchedule       func(now time.Time) bool
+
+	sourceField     string
+	callerFunction  bool
+	sourceCacheSize int
+	sourceCache     *sourceCache
+	errorField      string
 
-	sourceField string
-	errorField  string
+	asyncBufferSize int
+	asyncDropPolicy DropPolicy
+	async           *asyncEmitter
+
+	// output writes the records with the handler, or the fallback handler when it fails
+	output           *failoverHandler
+	handlerFailures  *atomic.Uint64
+	lastHandlerError *handlerError
+
+	// counters count the records suppressed, logged periodically by statsSummary if not nil
+	counters     *counters
+	statsSummary *statsSummary
+
+	// burst is the burst capture started by the last SQL error, nil without WithBurstCapture
+	burst *burstCapture
+	// escalation counts the SQL errors per fingerprint, nil without WithErrorEscalation
+	escalation *errorEscalation
+	// budget adapts the sampling to the volume budget, nil without WithVolumeBudget
+	budget *volumeBudget
+	// maintenance is the maintenance window declared with SuppressSlowUntil
+	maintenance *maintenanceWindow
+
+	// recordResource are the resource attributes overridden by the context attributes, see ConflictOverridden
+	recordResource []slog.Attr
+
+	// stamp are the attributes added to every record: the name of the logger and the build information, if enabled
+	stamp []slog.Attr
+
+	// errs are the errors of the invalid options, reported by NewE
+	errs []error
+
+	// live is the current configuration, shared by the copies of the logger
+	live *liveConfig
 }
 
 // LogMode log mode
@@ -86,27 +474,46 @@ func (l logger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
 	return l
 }
 
-// Info logs info
+// ParamsFilter implements gorm.ParamsFilter, removing the parameters from the SQL queries
+// logged when the parameterized queries are enabled. The parameters are captured for the
+// companion record of the full SQL query, see WithDebugFullQueries.
+func (l logger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	l = l.snapshot()
+	if l.debugFullQueries && !l.noValues {
+		if captured := fullQueryFromContext(ctx); captured != nil {
+			captured.sql, captured.vars, captured.captured = sql, params, true
+		}
+		return sql, nil
+	}
+	if l.parameterizedQueries || l.noValues {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// Info logs info, at the GormInfoLogType level
 func (l logger) Info(ctx context.Context, format string, args ...any) {
-	l.log(ctx, slog.LevelInfo, format, args...)
+	l.log(ctx, GormInfoLogType, format, args...)
 }
 
-// Warn logs warn messages
+// Warn logs warn messages, at the GormWarnLogType level
 func (l logger) Warn(ctx context.Context, format string, args ...any) {
-	l.log(ctx, slog.LevelWarn, format, args...)
+	l.log(ctx, GormWarnLogType, format, args...)
 }
 
-// Error logs error messages
+// Error logs error messages, at the GormErrorLogType level
 func (l logger) Error(ctx context.Context, format string, args ...any) {
-	l.log(ctx, slog.LevelError, format, args...)
+	l.log(ctx, GormErrorLogType, format, args...)
 }
 
-// log adds context attributes and logs a message with the given slog level
-func (l logger) log(ctx context.Context, level slog.Level, format string, args ...any) {
+// log adds context attributes and logs a message with the slog level of the given LogType
+func (l logger) log(ctx context.Context, logType LogType, format string, args ...any) {
+	l = l.snapshot()
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if !l.sloggerHandler.Enabled(ctx, level) {
+	level := l.logLevel[logType]
+	if l.silenced(logType) || !l.enabled(ctx, level) {
 		return
 	}
 
@@ -116,18 +523,27 @@ func (l logger) log(ctx context.Context, level slog.Level, format string, args .
 	// skip [runtime.Callers, this function, this function's caller]
 	runtime.Callers(3, pcs[:])
 	pc = pcs[0]
-	r := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, args...), pc)
-	r.Add(l.appendContextAttributes(ctx, nil)...)
+	// The message is only formatted when there are arguments, which may be values
+	msg := format
+	if len(args) > 0 && !l.noValues {
+		msg = fmt.Sprintf(format, args...)
+	}
+	r := slog.NewRecord(l.clock.Now(), level, msg, pc)
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(nil, *attributes)
+	r.AddAttrs(l.convertKeys(*attributes)...)
 
-	_ = l.sloggerHandler.Handle(ctx, r)
+	l.handle(ctx, r)
 }
 
-// log adds context attributes and logs a message with the given slog level
-func (l logger) logAttrs(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+// logAttrs logs a message with the given slog level and attributes
+func (l logger) logAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if !l.sloggerHandler.Enabled(ctx, level) {
+	if !l.enabled(ctx, level) {
 		return
 	}
 
@@ -137,75 +553,524 @@ func (l logger) logAttrs(ctx context.Context, level slog.Level, msg string, attr
 	// skip [runtime.Callers, this function, this function's caller]
 	runtime.Callers(3, pcs[:])
 	pc = pcs[0]
-	r := slog.NewRecord(time.Now(), level, msg, pc)
-	r.Add(attrs...)
+	r := slog.NewRecord(l.clock.Now(), level, msg, pc)
+	r.AddAttrs(l.convertKeys(attrs)...)
+
+	l.handle(ctx, r)
+}
+
+// convertKeys converts the keys of the attributes following the key casing convention,
+// except with a field preset whose schema defines the keys
+func (l logger) convertKeys(attrs []slog.Attr) []slog.Attr {
+	if l.preset != nil {
+		return attrs
+	}
+	return l.keyCase.convertAttrs(attrs)
+}
 
-	_ = l.sloggerHandler.Handle(ctx, r)
+// dropKeyCase returns the key casing convention of the records of the asynchronous mode
+func (l logger) dropKeyCase() KeyCase {
+	if l.preset != nil {
+		return OriginalCase
+	}
+	return l.keyCase
+}
+
+// enabled reports whether the records of the given level are logged, checking the
+// minimum level of the logger before the handler
+func (l logger) enabled(ctx context.Context, level slog.Level) bool {
+	if tenantLevel, ok := l.tenantLevelOf(ctx); ok {
+		if level < tenantLevel {
+			return false
+		}
+	} else if l.minLevel != nil && level < l.minLevel.Level() {
+		return false
+	}
+	return l.sloggerHandler.Enabled(ctx, level)
+}
+
+// tenantOf returns the tenant of the context, if any, see WithTenant
+func (l logger) tenantOf(ctx context.Context) (string, bool) {
+	if l.tenant == nil {
+		return "", false
+	}
+	return l.tenant(ctx)
+}
+
+// tracesTenant reports whether all the SQL queries of the tenant of the context are traced, its level
+// enabling the DefaultLogType level, see WithTenantLevels
+func (l logger) tracesTenant(ctx context.Context) bool {
+	tenantLevel, ok := l.tenantLevelOf(ctx)
+	return ok && l.logLevel[DefaultLogType] >= tenantLevel
+}
+
+// tenantLevelOf returns the level of the tenant of the context, if any, see WithTenantLevels
+func (l logger) tenantLevelOf(ctx context.Context) (slog.Level, bool) {
+	if l.tenantLevel == nil {
+		return 0, false
+	}
+	return l.tenantLevel(ctx)
+}
+
+// handle writes the record with the handler, from the background worker in asynchronous mode
+func (l logger) handle(ctx context.Context, r slog.Record) {
+	r.AddAttrs(l.stamp...)
+	if l.async != nil {
+		l.async.emit(ctx, r)
+		return
+	}
+	_ = l.output.Handle(ctx, r)
 }
 
 // Trace logs sql message
 func (l logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
-	if l.ignoreTrace {
+	l = l.snapshot()
+	l.reportStats()
+	l.counters.processed.Add(1)
+	if l.ignoreTrace && l.auditHandler == nil && !l.hasSinks() {
 		return // Silent
 	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// The slow threshold of the copy is the one of the context, see WithSlowThresholdFor
+	if len(l.slowThresholds) > 0 {
+		l.slowThreshold = l.slowThresholdOf(ctx)
+	}
+
+	var txIndex int64
+	if tx := transactionFromContext(ctx); tx != nil {
+		// The records not found which are ignored are not the causes of the rollbacks
+		if l.isError(err) {
+			txIndex = tx.track(err)
+		} else {
+			txIndex = tx.track(nil)
+		}
+	}
+
+	// The statements modifying the data are audited, and the queries handed to the sinks, whatever the tracing
+	elapsed := l.clock.Since(begin)
+	var (
+		query  *lazyQuery
+		source *sourceValuer
+	)
+	if l.auditHandler != nil || l.securityDetection || l.hasSinks() {
+		query = newLazyQuery(fc)
+	}
+	if (l.auditHandler != nil || l.securityDetection || l.hasSinks()) && l.capturesSource() {
+		source = newSource(l.sourceCache)
+	}
+	if l.auditHandler != nil {
+		l.audit(ctx, elapsed, query, source, txIndex, err)
+	}
+	if l.hasSinks() {
+		l.emitQueryEvent(ctx, begin, elapsed, query, source, txIndex, err)
+	}
+	if l.ignoreTrace {
+		return
+	}
+	if l.securityDetection {
+		l.detectSecurityAnomalies(ctx, elapsed, query, source, txIndex)
+	}
+	if l.explainer != nil {
+		// The query of the flagged request is explained with a copy, not to scrub the query logged
+		query = orNewLazyQuery(query, fc)
+		if plan, ok := l.explainAnalyze(ctx, query); ok {
+			statement := query.SQL()
+			if source == nil && l.capturesSource() {
+				source = newSource(l.sourceCache)
+			}
+			l.logPlan(ctx, newLazyQuery(func() (string, int64) { return statement, 0 }), source, plan,
+				slog.Bool(AnalyzeField, true))
+		}
+	}
+
+	// Identify the type of log before doing anything costly, so that nothing
+	// is computed nor allocated when the log is disabled.
+	sp := savepointFromContext(ctx)
+	ddl := ddlFromContext(ctx)
+
+	var dangerous bool
+	if l.dangerousWriteDetection {
+		if query == nil {
+			query = newLazyQuery(fc)
+		}
+		dangerous = isDangerousWrite(query, err)
+	}
+
+	// The size of the SQL query is checked with its values, before it is sanitized
+	var large bool
+	if l.maxQueryBytes > 0 {
+		query = orNewLazyQuery(query, fc)
+		large = len(query.SQL()) > l.maxQueryBytes
+	}
+	var largeResult bool
+	if l.largeResultThreshold > 0 {
+		query = orNewLazyQuery(query, fc)
+		largeResult = query.Rows() > l.largeResultThreshold
+	}
+	var policy *ModelPolicy
+	if len(l.policies) > 0 {
+		query = orNewLazyQuery(query, fc)
+		policy = l.policyOf(query)
+	}
 
-	elapsed := time.Since(begin)
+	// The SQL queries following an error are traced by the burst capture, see WithBurstCapture
+	failed := l.isError(err)
+	bursting := l.bursting(ctx)
+	if failed {
+		l.startBurst(ctx)
+	}
+
+	var logType LogType
 	switch {
-	case err != nil && (!errors.Is(err, gorm.ErrRecordNotFound) || !l.ignoreRecordNotFoundError):
-		sql, rows := fc()
+	case dangerous:
+		logType = DangerousWriteLogType
+	case failed:
+		logType = ErrorLogType
+	case sp != nil:
+		logType = SavepointLogType
+	case ddl != "":
+		logType = DDLLogType
+	case l.slowThreshold != 0 && elapsed > l.slowThreshold:
+		logType = SlowQueryLogType
+	case large:
+		logType = LargeQueryLogType
+	case largeResult:
+		logType = LargeResultLogType
+	case l.traceAll || l.gormLevel == gormlogger.Info || l.tracesTenant(ctx) || (policy != nil && policy.Trace) || bursting:
+		logType = DefaultLogType
+	default:
+		if l.decisionDebug {
+			decision := decisionNotTraced
+			var attrs []slog.Attr
+			if l.slowThreshold != 0 {
+				decision = decisionBelowSlowThreshold
+				attrs = append(attrs, slog.Duration(ThresholdField, l.slowThreshold))
+			}
+			l.logDecision(ctx, decision, "", elapsed, orNewLazyQuery(query, fc), attrs...)
+		}
+		return
+	}
+	if l.noValues {
+		err = withoutValues(err)
+	}
+
+	// The slow queries of the maintenance windows are logged at the MaintenanceLogType level
+	maintenance := logType == SlowQueryLogType && l.inMaintenance(l.clock.Now())
+	if l.silenced(logType) || (maintenance && l.silenced(MaintenanceLogType)) {
+		if l.decisionDebug {
+			l.logDecision(ctx, decisionSilenced, logType, elapsed, orNewLazyQuery(query, fc))
+		}
+		return
+	}
+	level := l.logLevel[logType]
+	if policyLevel, ok := policy.level(logType); ok {
+		level = policyLevel
+	}
+	if maintenance {
+		level = l.logLevel[MaintenanceLogType]
+	}
+	var (
+		errorCount int
+		escalated  bool
+	)
+	if logType == ErrorLogType && l.escalation != nil {
+		query = orNewLazyQuery(query, fc)
+		if errorCount, escalated = l.escalatedErrors(query, err); escalated {
+			level = max(level, l.logLevel[EscalatedErrorLogType])
+		}
+	}
+	if !l.enabled(ctx, level) {
+		if l.decisionDebug {
+			l.logDecision(ctx, decisionLevelDisabled, logType, elapsed, orNewLazyQuery(query, fc),
+				slog.String(RecordLevelField, level.String()))
+		}
+		return
+	}
+	samplingRate, sampled := l.sampled(ctx, logType)
+	if !sampled {
+		l.counters.sampledOut.Add(1)
+		if l.decisionDebug {
+			l.logDecision(ctx, decisionSampledOut, logType, elapsed, orNewLazyQuery(query, fc),
+				slog.Float64(SamplingRateField, samplingRate))
+		}
+		return
+	}
+
+	// The SQL query and the source are only resolved when the handler formats the record
+	if query == nil {
+		query = newLazyQuery(fc)
+	}
+	if logType != ErrorLogType && logType != DangerousWriteLogType {
+		var (
+			filter   string
+			filtered bool
+		)
+		if policy != nil && policy.Suppress {
+			filter, filtered = "model policy "+query.Table(), true
+		} else {
+			filter, filtered = l.filteredBy(ctx, logType, elapsed, query)
+		}
+		if filtered {
+			l.counters.filtered.Add(1)
+			if l.decisionDebug {
+				l.logDecision(ctx, decisionFiltered, logType, elapsed, query, slog.String(FilterField, filter))
+			}
+			return
+		}
+	}
+	if l.budget != nil {
+		budgetRate, allowed := l.budget.allow(l.clock.Now(), logType)
+		samplingRate *= budgetRate
+		if !allowed {
+			l.counters.sampledOut.Add(1)
+			if l.decisionDebug {
+				l.logDecision(ctx, decisionOverBudget, logType, elapsed, query, slog.Float64(SamplingRateField, samplingRate))
+			}
+			return
+		}
+	}
+	// The slow queries are explained with their values, before they are sanitized
+	var (
+		plan   string
+		change *planChange
+	)
+	if logType == SlowQueryLogType {
+		plan, _ = l.explainSlowQuery(ctx, query)
+		if plan != "" && l.explainer.plans != nil {
+			change = l.explainer.plans.planChanged(query.SQL(), plan)
+		}
+	}
+	queryBytes := -1
+	if l.queryBytes || large {
+		queryBytes = len(query.SQL())
+	}
+	var batch int
+	if l.batchMetrics {
+		batch = batchSize(query.SQL())
+	}
+	if l.sanitizesSQL() {
+		query.replaceSQL(l.sanitizeSQL)
+	}
 
-		// Append context attributes
-		attributes := l.appendContextAttributes(ctx, []any{
+	if source == nil && l.capturesSource() {
+		source = newSource(l.sourceCache)
+	}
+
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+
+	switch logType {
+	case ErrorLogType:
+		*attributes = append(*attributes,
 			slog.Any(l.errorField, err),
-			slog.String(QueryField, sql),
+			slog.Any(QueryField, sqlValuer{query}),
 			slog.Duration(DurationField, elapsed),
-			slog.Int64(RowsField, rows),
-			slog.String(l.sourceField, utils.FileWithLineNum()),
-		})
+			slog.Any(RowsField, rowsValuer{query}),
+		)
+		if escalated {
+			*attributes = append(*attributes, slog.Bool(EscalatedField, true), slog.Int(ErrorCountField, errorCount))
+		}
+
+	case DangerousWriteLogType:
+		*attributes = append(*attributes,
+			slog.Bool(DangerousWriteField, true),
+			slog.Any(QueryField, sqlValuer{query}),
+		)
+		*attributes = l.appendMetadataAttributes(*attributes, elapsed, query)
+		if err != nil {
+			*attributes = append(*attributes, slog.Any(l.errorField, err))
+		}
 
-		l.logAttrs(ctx, l.logLevel[ErrorLogType], err.Error(), attributes...)
+	case SavepointLogType:
+		*attributes = append(*attributes,
+			slog.String(SavepointField, sp.name),
+			slog.Any(QueryField, sqlValuer{query}),
+			slog.Duration(DurationField, elapsed),
+		)
 
-	case l.slowThreshold != 0 && elapsed > l.slowThreshold:
-		sql, rows := fc()
+	case DDLLogType:
+		*attributes = append(*attributes,
+			slog.String(OperationField, ddl),
+			slog.Any(QueryField, sqlValuer{query}),
+		)
+		*attributes = l.appendMetadataAttributes(*attributes, elapsed, query)
 
-		// Append context attributes
-		attributes := l.appendContextAttributes(ctx, []any{
+	case SlowQueryLogType:
+		*attributes = append(*attributes,
 			slog.Bool(SlowQueryField, true),
-			slog.String(QueryField, sql),
-			slog.Duration(DurationField, elapsed),
-			slog.Int64(RowsField, rows),
-			slog.String(l.sourceField, utils.FileWithLineNum()),
-		})
-		l.logAttrs(ctx, l.logLevel[SlowQueryLogType], fmt.Sprintf("slow sql query [%s >= %v]", elapsed, l.slowThreshold), attributes...)
+			slog.Any(QueryField, sqlValuer{query}),
+		)
+		*attributes = l.appendMetadataAttributes(*attributes, elapsed, query)
+		if plan != "" && !l.explainer.record {
+			*attributes = append(*attributes, slog.String(PlanField, plan))
+		}
+		if maintenance {
+			*attributes = append(*attributes, slog.Bool(MaintenanceField, true))
+		}
 
-	case l.traceAll || l.gormLevel == gormlogger.Info:
-		sql, rows := fc()
+	case LargeQueryLogType:
+		*attributes = append(*attributes,
+			slog.Any(QueryField, sqlValuer{query}),
+		)
+		*attributes = l.appendMetadataAttributes(*attributes, elapsed, query)
 
-		// Append context attributes
-		attributes := l.appendContextAttributes(ctx, []any{
-			slog.String(QueryField, sql),
-			slog.Duration(DurationField, elapsed),
-			slog.Int64(RowsField, rows),
-			slog.String(l.sourceField, utils.FileWithLineNum()),
+	case LargeResultLogType:
+		*attributes = append(*attributes,
+			slog.Any(QueryField, sqlValuer{query}),
+		)
+		*attributes = l.appendMetadataAttributes(*attributes, elapsed, query)
+
+	case DefaultLogType:
+		*attributes = append(*attributes,
+			slog.Any(QueryField, sqlValuer{query}),
+		)
+		*attributes = l.appendMetadataAttributes(*attributes, elapsed, query)
+	}
+
+	// Append size, batch, tables, shards, soft delete, bind parameters, sampling rate, goroutine, source, transaction,
+	// span and context attributes
+	if queryBytes >= 0 {
+		*attributes = append(*attributes, slog.Int(QueryBytesField, queryBytes))
+	}
+	*attributes = appendBatchAttributes(*attributes, batch, elapsed)
+	if l.tables {
+		*attributes = append(*attributes, slog.Any(TablesField, tablesValuer{query}))
+	}
+	if len(l.shardedTables) > 0 {
+		*attributes = l.appendShardAttributes(*attributes, query)
+	}
+	*attributes = l.appendSoftDeleteAttribute(ctx, *attributes, query)
+	*attributes = l.appendBindParamsAttribute(ctx, *attributes)
+	if samplingRate < 1 {
+		// The rate of the records kept lets the downstream statistics weight them, e.g. 10 queries per record at 0.1
+		*attributes = append(*attributes, slog.Float64(SamplingRateField, samplingRate))
+	}
+	if l.goroutineID {
+		*attributes = append(*attributes, slog.Uint64(GoroutineIDField, goroutineID()))
+	}
+	*attributes = l.appendSourceAttribute(*attributes, source)
+	*attributes = appendTransactionAttributes(txIndex, *attributes)
+	traceID, spanID, spanned := l.spanOf(ctx)
+	if spanned {
+		*attributes = append(*attributes, slog.String(TraceIDField, traceID), slog.String(SpanIDField, spanID))
+	}
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(query, *attributes)
+	l.verifyRedaction(ctx, logType, query)
+
+	// The messages are formatted once the attributes are scrubbed
+	event := messageEvent{
+		logType:   logType,
+		query:     query,
+		elapsed:   elapsed,
+		threshold: l.slowThreshold,
+		savepoint: savepointName(sp),
+		operation: savepointOperation(sp),
+		err:       err,
+	}
+	msg, custom := l.formatMessage(ctx, event)
+	if !custom {
+		msg = l.defaultMessage(ctx, event)
+	}
+
+	// The companion record is prepared first, as the attributes are converted when logged
+	var companion []slog.Attr
+	if l.debugFullQueries && !l.noValues {
+		companion = l.fullQueryAttrs(ctx, query, *attributes)
+	}
+	if spanned && l.withoutSpanStatement {
+		*attributes = withoutQuery(*attributes)
+	}
+
+	l.logAttrs(ctx, level, msg, l.applyPreset(ctx, logType, level, query, *attributes)...)
+
+	if plan != "" && l.explainer.record {
+		l.logPlan(ctx, query, source, plan)
+	}
+	if change != nil {
+		l.logPlanChange(ctx, query, source, plan, change)
+	}
+
+	if companion != nil {
+		fullMsg, custom := l.formatMessage(ctx, messageEvent{
+			logType: FullQueryLogType,
+			query:   query,
+			elapsed: elapsed,
+			err:     err,
 		})
+		if !custom {
+			fullMsg = FullQueryMessage
+		}
+		fullLevel := l.logLevel[FullQueryLogType]
+		l.logAttrs(ctx, fullLevel, fullMsg, l.applyPreset(ctx, FullQueryLogType, fullLevel, query, companion)...)
+	}
+}
+
+// isError reports whether the error of the SQL query is logged as an error, the gorm.ErrRecordNotFound
+// errors being ignored unless WithRecordNotFoundError
+func (l logger) isError(err error) bool {
+	return err != nil && (!errors.Is(err, gorm.ErrRecordNotFound) || !l.ignoreRecordNotFoundError)
+}
 
-		l.logAttrs(ctx, l.logLevel[DefaultLogType], fmt.Sprintf("SQL query executed [%s]", elapsed), attributes...)
+// appendMetadataAttributes adds the duration and the number of rows of the query, unless they are
+// only logged in the message
+func (l logger) appendMetadataAttributes(args []slog.Attr, elapsed time.Duration, query *lazyQuery) []slog.Attr {
+	if l.metadataMode == MetadataMessageOnly {
+		return args
 	}
+	return append(args,
+		slog.Duration(DurationField, elapsed),
+		slog.Any(RowsField, rowsValuer{query}),
+	)
 }
 
-func (l logger) appendContextAttributes(ctx context.Context, args []any) []any {
-	if args == nil {
-		args = []any{}
+// contextAttr is an attribute whose value is extracted from the context of each log,
+// either with a context key or a function.
+type contextAttr struct {
+	name string
+	key  any
+	fn   func(context.Context) (slog.Value, bool)
+}
+
+// appendContextAttributes adds the tenant (see WithTenant) and the attributes extracted from
+// the context, in the order in which they were registered, resolving their conflicts with the
+// attributes of the handler (see WithContextAttrConflict)
+func (l logger) appendContextAttributes(ctx context.Context, args []slog.Attr) []slog.Attr {
+	from := len(args)
+	add := func(attr slog.Attr) {
+		if attr, ok := l.resolveConflict(attr); ok {
+			args = append(args, attr)
+		}
 	}
-	for k, v := range l.contextKeys {
-		if value := ctx.Value(v); value != nil {
-			args = append(args, slog.Any(k, value))
+
+	if tenant, ok := l.tenantOf(ctx); ok {
+		add(slog.String(TenantField, tenant))
+	}
+	for i := range l.contextAttrs {
+		attr := &l.contextAttrs[i]
+		if !l.allowedContextAttr(attr.name) {
+			continue
+		}
+		if attr.fn != nil {
+			if value, ok := attr.fn(ctx); ok {
+				add(slog.Attr{Key: attr.name, Value: value})
+			}
+		} else if value := ctx.Value(attr.key); value != nil {
+			add(slog.Any(attr.name, value))
 		}
 	}
-	for k, f := range l.contextFuncs {
-		if value, ok := f(ctx); ok {
-			args = append(args, slog.Any(k, value))
+	return l.appendOverriddenResource(args, from)
+}
+
+// addContextAttr registers the context attribute, replacing the one with the same name if any
+func (l *logger) addContextAttr(attr contextAttr) {
+	for i := range l.contextAttrs {
+		if l.contextAttrs[i].name == attr.name {
+			l.contextAttrs[i] = attr
+			return
 		}
 	}
-	return args
+	l.contextAttrs = append(l.contextAttrs, attr)
 }
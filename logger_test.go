@@ -3,9 +3,12 @@ package slogGorm
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"runtime"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,7 +20,7 @@ import (
 
 func TestNew(t *testing.T) {
 	t.Run("Without options", func(t *testing.T) {
-		l := New()
+		l := New().(*logger)
 
 		require.NotNil(t, l.sloggerHandler)
 		assert.Equal(t, slog.Default().Handler(), l.sloggerHandler)
@@ -26,7 +29,7 @@ func TestNew(t *testing.T) {
 	t.Run("WithLogger(nil)", func(t *testing.T) {
 		l := New(
 			WithLogger(nil),
-		)
+		).(*logger)
 
 		require.NotNil(t, l.sloggerHandler)
 		assert.Equal(t, slog.Default().Handler(), l.sloggerHandler)
@@ -35,17 +38,155 @@ func TestNew(t *testing.T) {
 	t.Run("WithHandler(nil)", func(t *testing.T) {
 		l := New(
 			WithHandler(nil),
-		)
+		).(*logger)
 
 		require.NotNil(t, l.sloggerHandler)
 		assert.Equal(t, slog.Default().Handler(), l.sloggerHandler)
 	})
 }
 
+func TestNewE(t *testing.T) {
+	t.Run("Valid options", func(t *testing.T) {
+		l, err := NewE(WithSlowThreshold(time.Second), SetLogLevel(DefaultLogType, slog.LevelDebug))
+
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, time.Second, l.(*logger).slowThreshold)
+		assert.NotNil(t, l.(*logger).sloggerHandler)
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		l, err := NewE(
+			WithSlowThreshold(-time.Second),
+			WithIgnoredQueries(nil),
+			SetLogLevel("unknown", slog.LevelDebug),
+		)
+
+		require.Error(t, err)
+		assert.Nil(t, l)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.Contains(t, err.Error(), "negative slow threshold -1s")
+		assert.Contains(t, err.Error(), "nil ignored query pattern")
+		assert.Contains(t, err.Error(), `unknown log type "unknown"`)
+	})
+
+	t.Run("Invalid options ignored by New", func(t *testing.T) {
+		l := New(WithSlowThreshold(-time.Second), SetLogLevel("unknown", slog.LevelDebug)).(*logger)
+
+		assert.Zero(t, l.slowThreshold)
+		assert.NotContains(t, l.logLevel, LogType("unknown"))
+	})
+}
+
+func Test_logger_AttributeOrder(t *testing.T) {
+	var options []Option
+	ctx := context.Background()
+	var contextKeys []string
+	for i := 0; i < 16; i++ {
+		key := fmt.Sprintf("ctx_%02d", i)
+		options = append(options, WithContextValue(key, ctxKey(100+i)))
+		ctx = context.WithValue(ctx, ctxKey(100+i), i)
+		contextKeys = append(contextKeys, key)
+	}
+	receiver, gormLogger := getReceiverAndLogger(append(options, WithTraceAll()))
+
+	for i := 0; i < 10; i++ {
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", 1 }, errors.New("failed"))
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	}
+
+	// The attributes of the query, then the source, then the context attributes in the order of registration
+	errorKeys := append([]string{ErrorField, QueryField, DurationField, RowsField, SourceField}, contextKeys...)
+	queryKeys := append([]string{QueryField, DurationField, RowsField, SourceField}, contextKeys...)
+	require.Equal(t, 20, receiver.Len())
+	for _, r := range receiver.Records {
+		var keys []string
+		r.Attrs(func(attr slog.Attr) bool {
+			keys = append(keys, attr.Key)
+			return true
+		})
+		if r.Level == slog.LevelError {
+			assert.Equal(t, errorKeys, keys)
+		} else {
+			assert.Equal(t, queryKeys, keys)
+		}
+	}
+}
+
+func Test_logger_With(t *testing.T) {
+	t.Run("Additional options", func(t *testing.T) {
+		receiver, base := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Second),
+			WithContextValue("request_id", "request_id"),
+		})
+
+		derived := base.with(
+			WithTraceAll(),
+			SetLogLevel(DefaultLogType, slog.LevelDebug),
+			WithContextValue("tenant", "tenant"),
+			WithIgnoredTables("sessions"),
+		)
+
+		assert.Equal(t, receiver, derived.sloggerHandler)
+		assert.Equal(t, time.Second, derived.slowThreshold)
+		assert.True(t, derived.traceAll)
+		assert.Equal(t, slog.LevelDebug, derived.logLevel[DefaultLogType])
+		assert.Len(t, derived.contextAttrs, 2)
+		assert.Len(t, derived.filters, 1)
+		assert.Same(t, base.sourceCache, derived.sourceCache)
+
+		// The base logger is unchanged
+		assert.False(t, base.traceAll)
+		assert.Equal(t, slog.LevelInfo, base.logLevel[DefaultLogType])
+		assert.Len(t, base.contextAttrs, 1)
+		assert.Empty(t, base.filters)
+	})
+
+	t.Run("Logger interface", func(t *testing.T) {
+		var base Logger = New(WithSlowThreshold(time.Second))
+
+		derived := base.With(WithTraceAll())
+
+		require.IsType(t, &logger{}, derived)
+		assert.True(t, derived.(*logger).traceAll)
+		assert.Equal(t, time.Second, derived.Config().SlowThreshold)
+	})
+
+	t.Run("Replaced context value", func(t *testing.T) {
+		_, base := getReceiverAndLogger([]Option{WithContextValue("id", "request_id")})
+
+		derived := base.with(WithContextValue("id", "trace_id"))
+
+		assert.Equal(t, "request_id", base.contextAttrs[0].key)
+		assert.Equal(t, "trace_id", derived.contextAttrs[0].key)
+	})
+
+	t.Run("Shared asynchronous mode", func(t *testing.T) {
+		_, base := getReceiverAndLogger([]Option{WithAsync(8)})
+
+		derived := base.with(WithTraceAll())
+		assert.Same(t, base.async, derived.async)
+
+		other := base.with(WithHandler(NewDummyHandler()))
+		require.NotNil(t, other.async)
+		assert.NotSame(t, base.async, other.async)
+
+		require.NoError(t, other.Close())
+		require.NoError(t, base.Close())
+	})
+
+	t.Run("Source cache", func(t *testing.T) {
+		_, base := getReceiverAndLogger(nil)
+
+		assert.NotSame(t, base.sourceCache, base.with(WithSourceCacheSize(16)).sourceCache)
+		assert.Nil(t, base.with(WithoutSourceField()).sourceCache)
+	})
+}
+
 func Test_logger_Enabled(t *testing.T) {
 	buffer := bytes.NewBuffer(nil)
 	leveler := &slog.LevelVar{}
-	l := New(WithHandler(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: leveler})))
+	l := New(WithHandler(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: leveler}))).(*logger)
 	leveler.Set(slog.LevelWarn)
 
 	l.Info(nil, "an info message")
@@ -58,6 +199,88 @@ func Test_logger_Enabled(t *testing.T) {
 	assert.Greater(t, buffer.Len(), 0)
 }
 
+func Test_logger_log_Format(t *testing.T) {
+	receiver, l := getReceiverAndLogger(nil)
+
+	l.Info(context.Background(), "a static message")
+	assert.Equal(t, "a static message", receiver.Record.Message)
+
+	l.Info(context.Background(), "formatted %d%%", 100)
+	assert.Equal(t, "formatted 100%", receiver.Record.Message)
+}
+
+func Test_logger_log_Levels(t *testing.T) {
+	receiver, l := getReceiverAndLogger([]Option{
+		SetLogLevel(GormInfoLogType, slog.LevelDebug+1),
+		SetLogLevel(GormWarnLogType, slog.LevelDebug),
+		SetLogLevel(GormErrorLogType, slog.LevelWarn),
+	})
+
+	l.Info(context.Background(), "an info message")
+	assert.Equal(t, slog.LevelDebug+1, receiver.Record.Level)
+
+	l.Warn(context.Background(), "a warn message")
+	assert.Equal(t, slog.LevelDebug, receiver.Record.Level)
+
+	l.Error(context.Background(), "an error message")
+	assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+}
+
+func Test_logger_MinLevel(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	minLevel := &slog.LevelVar{}
+	minLevel.Set(slog.LevelWarn)
+	receiver, gormLogger := getReceiverAndLogger([]Option{
+		WithTraceAll(),
+		WithMinLevel(minLevel),
+	})
+
+	// The handler is enabled for all levels, but not the logger
+	gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+	gormLogger.Info(context.Background(), "an info message")
+	assert.Equal(t, 0, receiver.Len())
+
+	gormLogger.Trace(context.Background(), time.Now(), fc, errors.New("awesome error"))
+	gormLogger.Warn(context.Background(), "a warn message")
+	assert.Equal(t, 2, receiver.Len())
+
+	minLevel.Set(slog.LevelInfo)
+	gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+	assert.Equal(t, 3, receiver.Len())
+}
+
+func Test_logger_TenantLevels(t *testing.T) {
+	type tenantKey struct{}
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+	receiver, gormLogger := getReceiverAndLogger([]Option{
+		WithMinLevel(slog.LevelWarn),
+		WithTenantLevels(func(ctx context.Context) (slog.Level, bool) {
+			if ctx.Value(tenantKey{}) == "acme" {
+				return slog.LevelDebug, true
+			}
+			return 0, false
+		}),
+	})
+	debugged := context.WithValue(context.Background(), tenantKey{}, "acme")
+	other := context.WithValue(context.Background(), tenantKey{}, "globex")
+
+	// The queries of the other tenants are neither traced nor logged below the minimum level
+	gormLogger.Trace(other, time.Now(), fc, nil)
+	gormLogger.Info(other, "an info message")
+	assert.Equal(t, 0, receiver.Len())
+
+	// All the queries of the tenant are traced, at its level
+	gormLogger.Trace(debugged, time.Now(), fc, nil)
+	gormLogger.Info(debugged, "an info message")
+	require.Equal(t, 2, receiver.Len())
+	assert.Regexp(t, `^SQL query executed \[`, receiver.Records[0].Message)
+}
+
 func Test_logger_Trace_Enabled(t *testing.T) {
 	buffer := bytes.NewBuffer(nil)
 	leveler := &slog.LevelVar{}
@@ -65,7 +288,7 @@ func Test_logger_Trace_Enabled(t *testing.T) {
 		WithHandler(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: leveler})),
 		WithSlowThreshold(10*time.Second),
 		WithTraceAll(),
-	)
+	).(*logger)
 
 	fc := func() (string, int64) {
 		return "SELECT * FROM user", 1
@@ -84,6 +307,171 @@ func Test_logger_Trace_Enabled(t *testing.T) {
 	assert.Equal(t, 0, buffer.Len())
 }
 
+func Test_logger_Trace_Disabled_ZeroAllocation(t *testing.T) {
+	handler := slog.NewTextHandler(bytes.NewBuffer(nil), &slog.HandlerOptions{Level: slog.Level(42)})
+	l := New(
+		WithHandler(handler),
+		WithSlowThreshold(10*time.Second),
+		WithTraceAll(),
+	).(*logger)
+
+	ctx := context.Background()
+	err := fmt.Errorf("awesome error")
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Trace(ctx, time.Now().Add(-1*time.Second), fc, nil)
+		l.Trace(ctx, time.Now().Add(-1*time.Minute), fc, nil)
+		l.Trace(ctx, time.Now().Add(-1*time.Minute), fc, err)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func Test_logger_Trace_LazyQuery(t *testing.T) {
+	calls := 0
+	fc := func() (string, int64) {
+		calls++
+		return "SELECT * FROM user", 1
+	}
+
+	t.Run("Record dropped by the handler", func(t *testing.T) {
+		calls = 0
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("Record formatted by the handler", func(t *testing.T) {
+		calls = 0
+		buffer := bytes.NewBuffer(nil)
+		gormLogger := New(
+			WithHandler(slog.NewTextHandler(buffer, nil)),
+			WithTraceAll(),
+		).(*logger)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		assert.Contains(t, buffer.String(), `query="SELECT * FROM user"`)
+		assert.Contains(t, buffer.String(), "rows=1")
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func Test_logger_QueryBytes(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Attribute", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithQueryBytes(), WithNoValues()})
+
+		trace(gormLogger, "SELECT * FROM users WHERE name = 'john'")
+
+		require.Equal(t, 1, receiver.Len())
+		r := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &r, slog.Int(QueryBytesField, 39))
+		assertHasAttr(t, &r, slog.String(QueryField, "SELECT * FROM users WHERE name = ?"))
+	})
+
+	t.Run("Large query escalated", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithMaxQueryBytesWarn(20)})
+
+		trace(gormLogger, "SELECT * FROM users WHERE id IN (1, 2, 3)")
+		trace(gormLogger, "SELECT 1")
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assert.Regexp(t, `^large sql query \[.+\]$`, receiver.Record.Message)
+		assertHasAttr(t, receiver.Record, slog.Int(QueryBytesField, 41))
+	})
+
+	t.Run("Error kept", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithMaxQueryBytesWarn(1)})
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 1", 0 },
+			errors.New("an error"))
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelError, receiver.Record.Level)
+		assertHasAttr(t, receiver.Record, slog.Int(QueryBytesField, 8))
+	})
+}
+
+func Test_logger_LargeResult(t *testing.T) {
+	trace := func(l *logger, rows int64) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT * FROM users", rows }, nil)
+	}
+
+	t.Run("Large result set", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithLargeResultThreshold(1000)})
+
+		trace(gormLogger, 5000)
+		trace(gormLogger, 1000)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assert.Equal(t, "large result set [5000 rows]", receiver.Record.Message)
+		r := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &r, slog.Int64(RowsField, 5000))
+	})
+
+	t.Run("Slow query kept", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithLargeResultThreshold(1000),
+			WithSlowThreshold(time.Millisecond),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second),
+			func() (string, int64) { return "SELECT * FROM users", 5000 }, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Regexp(t, `^slow sql query`, receiver.Record.Message)
+	})
+
+	t.Run("Message template", func(t *testing.T) {
+		format, err := compileMessageTemplate(LargeResultMessage)
+		require.NoError(t, err)
+		assert.Equal(t, "large result set [5000 rows]", format(context.Background(), messageEvent{
+			logType: LargeResultLogType,
+			query:   newLazyQuery(func() (string, int64) { return "SELECT * FROM users", 5000 }),
+		}))
+	})
+}
+
+func Test_logger_Tables(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users JOIN orders ON orders.user_id = users.id", 1
+	}
+
+	t.Run("Attribute", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithTables()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		values := startupValues(resolveRecord(*receiver.Record))
+		assert.Equal(t, []string{"users", "orders"}, values[TablesField].Any())
+	})
+
+	t.Run("Query filter", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithQueryFilter(func(_ context.Context, query QueryInfo) bool {
+				return !slices.Contains(query.Tables, "orders")
+			}),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		assert.Equal(t, 0, receiver.Len())
+	})
+}
+
 func Test_logger_LogMode(t *testing.T) {
 	l := logger{gormLevel: gormlogger.Info}
 	actual := l.LogMode(gormlogger.Info)
@@ -91,6 +479,31 @@ func Test_logger_LogMode(t *testing.T) {
 	assert.Equal(t, l, actual)
 }
 
+func Test_logger_ParamsFilter(t *testing.T) {
+	t.Run("With the parameters", func(t *testing.T) {
+		// The asynchronous mode resolves the query before gorm resets its statement
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithAsync(8)})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE id = ?", 42).Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(QueryField, "DELETE FROM users WHERE id = 42"))
+	})
+
+	t.Run("Parameterized queries", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithAsync(8), WithParameterizedQueries()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE id = ?", 42).Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(QueryField, "DELETE FROM users WHERE id = ?"))
+	})
+}
+
 func Test_logger(t *testing.T) {
 	receiver, gormLogger := getReceiverAndLogger([]Option{
 		WithContextValue("attrKeyViaValue", "ctxKey"),
@@ -243,6 +656,7 @@ func Test_logger_Trace(t *testing.T) {
 		ctx     context.Context
 
 		wantNoRecord       bool
+		wantMessage        string
 		wantContainMessage string
 		wantAttributes     map[string]slog.Attr
 		wantLevel          slog.Level
@@ -366,6 +780,30 @@ func Test_logger_Trace(t *testing.T) {
 			ctx:          context.Background(),
 			wantNoRecord: true,
 		},
+		{
+			name: "With trace all mode and static messages",
+			options: []Option{
+				WithTraceAll(),
+				WithStaticMessages(),
+			},
+			args:               selectQueryArgs,
+			ctx:                context.Background(),
+			wantMessage:        "SQL query executed",
+			wantContainMessage: "SQL query executed",
+			wantLevel:          slog.LevelInfo,
+		},
+		{
+			name: "Slow query and static messages",
+			options: []Option{
+				WithSlowThreshold(1 * time.Second),
+				WithStaticMessages(),
+			},
+			args:               selectQueryArgs,
+			ctx:                context.Background(),
+			wantMessage:        "slow sql query",
+			wantContainMessage: "slow sql query",
+			wantLevel:          slog.LevelWarn,
+		},
 		{
 			name: "With context value",
 			options: []Option{
@@ -404,6 +842,9 @@ func Test_logger_Trace(t *testing.T) {
 				require.NotNil(t, receiver.Record)
 				assert.Equal(t, tt.wantLevel, receiver.Record.Level)
 				assert.Contains(t, receiver.Record.Message, tt.wantContainMessage)
+				if tt.wantMessage != "" {
+					assert.Equal(t, tt.wantMessage, receiver.Record.Message)
+				}
 				if tt.wantAttributes != nil {
 					for k, v := range tt.wantAttributes {
 						found := false
@@ -429,7 +870,7 @@ func getReceiverAndLogger(options []Option) (*DummyHandler, *logger) {
 	receiver := NewDummyHandler()
 	options = append(options, WithLogger(slog.New(receiver)))
 
-	return receiver, New(options...)
+	return receiver, New(options...).(*logger)
 }
 
 type ctxKey int
@@ -449,13 +890,16 @@ func NewDummyHandler() *DummyHandler {
 }
 
 type DummyHandler struct {
+	mu              sync.Mutex
 	EnabledResponse map[slog.Level]bool
 	Attrs           []slog.Attr
 	Record          *slog.Record
+	Records         []slog.Record
 }
 
 func (h *DummyHandler) Reset() {
 	h.Record = nil
+	h.Records = nil
 	h.Attrs = []slog.Attr{}
 }
 
@@ -473,6 +917,17 @@ func (h *DummyHandler) WithGroup(_ string) slog.Handler {
 }
 
 func (h *DummyHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.Record = &r
+	h.Records = append(h.Records, r)
 	return nil
 }
+
+func (h *DummyHandler) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.Records)
+}
@@ -0,0 +1,41 @@
+package slogGorm
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// maintenanceWindow is the end of the maintenance window declared with SuppressSlowUntil, shared with the
+// copies of the logger
+type maintenanceWindow struct {
+	// until is the end of the maintenance window, in nanoseconds since the Unix epoch, 0 if none is declared
+	until atomic.Int64
+}
+
+// SuppressSlowUntil declares a maintenance window ending at the given time (e.g. of a planned migration or
+// backfill), during which the slow queries are logged at the MaintenanceLogType level (slog.LevelInfo by default)
+// instead of the SlowQueryLogType level, so that they do not page anyone. They are not logged at all if
+// MaintenanceLogType is silenced, see WithSilencedLogTypes. The window applies to the logger and all its copies,
+// a zero time or a time in the past ending it. See WithMaintenanceSchedule for the recurring windows.
+//
+// Usage:
+//
+//	gormLogger.SuppressSlowUntil(time.Now().Add(2 * time.Hour))
+//	defer gormLogger.SuppressSlowUntil(time.Time{})
+func (l logger) SuppressSlowUntil(t time.Time) {
+	l = l.snapshot()
+	var until int64
+	if !t.IsZero() {
+		until = t.UnixNano()
+	}
+	l.maintenance.until.Store(until)
+}
+
+// inMaintenance reports whether the time is in a maintenance window, see SuppressSlowUntil and
+// WithMaintenanceSchedule
+func (l logger) inMaintenance(now time.Time) bool {
+	if until := l.maintenance.until.Load(); until != 0 && now.Before(time.Unix(0, until)) {
+		return true
+	}
+	return l.maintenanceSchedule != nil && l.maintenanceSchedule(now)
+}
@@ -0,0 +1,89 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_SuppressSlowUntil(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	fc := func() (string, int64) {
+		return "UPDATE users SET name = 'john'", 1
+	}
+	slow := func(l *logger) {
+		l.Trace(context.Background(), now.Add(-2*time.Second), fc, nil)
+	}
+
+	t.Run("Downgraded", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithSlowThreshold(time.Second),
+			WithMinLevel(slog.LevelDebug),
+		})
+
+		gormLogger.With().SuppressSlowUntil(now.Add(time.Hour))
+		slow(gormLogger)
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelInfo, receiver.Record.Level, "the window applies to all the copies")
+		assertHasAttr(t, receiver.Record, slog.Bool(MaintenanceField, true))
+
+		now = now.Add(time.Hour)
+		slow(gormLogger)
+		require.Equal(t, 2, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assertNoAttr(t, receiver.Record, MaintenanceField)
+	})
+
+	t.Run("Ended", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithSlowThreshold(time.Second),
+		})
+
+		gormLogger.SuppressSlowUntil(now.Add(time.Hour))
+		gormLogger.SuppressSlowUntil(time.Time{})
+		slow(gormLogger)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+	})
+
+	t.Run("Suppressed", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithSlowThreshold(time.Second),
+			WithSilencedLogTypes(MaintenanceLogType),
+		})
+
+		gormLogger.SuppressSlowUntil(now.Add(time.Hour))
+		slow(gormLogger)
+		assert.Equal(t, 0, receiver.Len())
+
+		gormLogger.SuppressSlowUntil(time.Time{})
+		slow(gormLogger)
+		assert.Equal(t, 1, receiver.Len())
+	})
+
+	t.Run("Schedule", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithSlowThreshold(time.Second),
+			WithMaintenanceSchedule(func(now time.Time) bool { return now.Hour() < 4 }),
+			SetLogLevel(MaintenanceLogType, slog.LevelWarn-1),
+		})
+
+		slow(gormLogger)
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+
+		now = time.Date(2024, 1, 3, 2, 0, 0, 0, time.UTC)
+		slow(gormLogger)
+		require.Equal(t, 2, receiver.Len())
+		assert.Equal(t, slog.LevelWarn-1, receiver.Record.Level)
+	})
+}
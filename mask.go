@@ -0,0 +1,312 @@
+package slogGorm
+
+import (
+	"slices"
+	"strings"
+)
+
+// maskedValue replaces the values of the masked columns in the SQL queries, see WithMaskedColumns
+const maskedValue = "'***'"
+
+// maskedColumn is a column whose values are masked in the SQL queries
+type maskedColumn struct {
+	// name is the name given to WithMaskedColumns
+	name string
+	// table is the unqualified name of the table in lowercase, empty for the columns of all tables
+	table  string
+	column string
+}
+
+// parseMaskedColumn parses a column given to WithMaskedColumns: "column", "table.column" or "schema.table.column"
+func parseMaskedColumn(name string) (maskedColumn, bool) {
+	parts := strings.Split(strings.ToLower(name), ".")
+	for _, part := range parts {
+		if part == "" {
+			return maskedColumn{}, false
+		}
+	}
+
+	column := maskedColumn{name: name, column: parts[len(parts)-1]}
+	if len(parts) > 1 {
+		column.table = parts[len(parts)-2]
+	}
+	return column, true
+}
+
+// maskAction is the action on a token of a SQL query whose values are masked
+type maskAction uint8
+
+const (
+	maskKeep    maskAction = iota
+	maskReplace            // the value is replaced by maskedValue
+	maskDrop               // the sign of a masked number is removed
+)
+
+// columnMasker masks the values of the masked columns in a SQL query
+type columnMasker struct {
+	columns []maskedColumn
+	tokens  []token
+	// significant are the indexes of the tokens which are neither whitespaces nor comments
+	significant []int
+	actions     []maskAction
+	// table is the unqualified main table of the query, for the unqualified columns
+	table  string
+	masked bool
+}
+
+// maskColumns returns the SQL query whose values compared or assigned to the given columns are masked,
+// e.g. "email = 'john@example.com'", "email IN ('a', 'b')", "SET email = 'a'" or the values of an INSERT
+// statement listing its columns, including the columns and the values wrapped in function calls, e.g.
+// "lower(email) = lower('John@example.com')". The columns qualified by an alias of their table are not
+// recognized.
+func maskColumns(sql string, columns []maskedColumn) string {
+	m := columnMasker{columns: columns, table: unqualified(parseTable(sql))}
+	tokenize(sql, func(t token) bool {
+		if t.kind != tokenSpace && t.kind != tokenComment {
+			m.significant = append(m.significant, len(m.tokens))
+		}
+		m.tokens = append(m.tokens, t)
+		return true
+	})
+	m.actions = make([]maskAction, len(m.tokens))
+
+	m.mask()
+	if !m.masked {
+		return sql
+	}
+
+	var b strings.Builder
+	b.Grow(len(sql))
+	for i, t := range m.tokens {
+		switch m.actions[i] {
+		case maskReplace:
+			b.WriteString(maskedValue)
+		case maskDrop:
+		default:
+			b.WriteString(t.text)
+		}
+	}
+	return b.String()
+}
+
+// unqualified returns the name without its qualifiers, in lowercase
+func unqualified(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(name)
+}
+
+// at returns the significant token at position p, or an empty token
+func (m *columnMasker) at(p int) token {
+	if p < 0 || p >= len(m.significant) {
+		return token{kind: tokenSpace}
+	}
+	return m.tokens[m.significant[p]]
+}
+
+// isMasked reports whether the values of the column of the given table (possibly empty) are masked
+func (m *columnMasker) isMasked(table, column string) bool {
+	if table == "" {
+		table = m.table
+	}
+	table, column = strings.ToLower(table), strings.ToLower(column)
+	for _, c := range m.columns {
+		if c.column == column && (c.table == "" || c.table == table) {
+			return true
+		}
+	}
+	return false
+}
+
+// name returns the qualifier and the name of the (possibly qualified) name starting at position p,
+// and the position following it
+func (m *columnMasker) name(p int) (qualifier, name string, next int) {
+	name = m.at(p).value()
+	for p+2 < len(m.significant) && m.at(p+1).text == "." && isName(m.at(p+2)) {
+		qualifier, name = name, m.at(p+2).value()
+		p += 2
+	}
+	return qualifier, name, p + 1
+}
+
+// mask masks the values of the masked columns in the query
+func (m *columnMasker) mask() {
+	var insertTable string
+	for p := 0; p < len(m.significant); {
+		t := m.at(p)
+		switch {
+		case t.isKeyword("INTO") && isName(m.at(p+1)):
+			_, insertTable, p = m.name(p + 1)
+			if m.at(p).text == "(" {
+				p = m.maskInsert(p, insertTable)
+			}
+		case isName(t):
+			var qualifier, column string
+			qualifier, column, p = m.name(p)
+			if m.isMasked(qualifier, column) {
+				p = m.maskComparison(m.unwrapCalls(p))
+			}
+		default:
+			p++
+		}
+	}
+}
+
+// unwrapCalls returns the position following the function calls wrapping the column whose name ends at
+// position p, e.g. "lower(email)" or "coalesce(trim(email), 'none')", or p if the column is not wrapped
+func (m *columnMasker) unwrapCalls(p int) int {
+	for t := m.at(p); t.text == ")" || t.text == ","; t = m.at(p) {
+		depth, end := 0, p
+		for ; end < len(m.significant) && depth >= 0; end++ {
+			switch m.at(end).text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		if depth >= 0 {
+			// The column is not wrapped in a call, e.g. a column of a SELECT list
+			return p
+		}
+		p = end
+	}
+	return p
+}
+
+// maskComparison masks the values compared to a masked column, the operator starting at position p.
+// It returns the position following the values.
+func (m *columnMasker) maskComparison(p int) int {
+	if m.at(p).isKeyword("NOT") {
+		p++
+	}
+
+	switch t := m.at(p); {
+	case t.isKeyword("IN"):
+		return m.maskOperand(p + 1)
+	case t.isKeyword("LIKE") || t.isKeyword("ILIKE"):
+		return m.maskOperand(p + 1)
+	case t.isKeyword("BETWEEN"):
+		p = m.maskOperand(p + 1)
+		if m.at(p).isKeyword("AND") {
+			p = m.maskOperand(p + 1)
+		}
+		return p
+	}
+
+	operator := p
+	for t := m.at(p); t.kind == tokenPunctuation && strings.Contains("=<>!", t.text); t = m.at(p) {
+		p++
+	}
+	if p == operator {
+		return p
+	}
+	return m.maskOperand(p)
+}
+
+// maskOperand masks the value at position p, or all the values of the parenthesized list
+// starting at p. It returns the position following the operand.
+func (m *columnMasker) maskOperand(p int) int {
+	if isName(m.at(p)) && m.at(p+1).text == "(" {
+		// The values of a function call, e.g. lower('John@example.com')
+		p++
+	}
+	if m.at(p).text == "(" {
+		depth := 0
+		for ; p < len(m.significant); p++ {
+			switch m.at(p).text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+			m.maskValue(p)
+			if depth == 0 {
+				return p + 1
+			}
+		}
+		return p
+	}
+
+	if t := m.at(p); t.kind == tokenPunctuation && (t.text == "-" || t.text == "+") && m.isValue(p+1) {
+		m.actions[m.significant[p]] = maskDrop
+		p++
+	}
+	m.maskValue(p)
+	return p + 1
+}
+
+// isValue reports whether the significant token at position p is a value. The double-quoted
+// tokens are values, except the qualifiers of the names.
+func (m *columnMasker) isValue(p int) bool {
+	switch m.at(p).kind {
+	case tokenString, tokenNumber:
+		return true
+	case tokenDoubleQuoted:
+		return m.at(p+1).text != "."
+	}
+	return false
+}
+
+// maskValue masks the significant token at position p if it is a value
+func (m *columnMasker) maskValue(p int) {
+	if m.isValue(p) {
+		m.actions[m.significant[p]] = maskReplace
+		m.masked = true
+	}
+}
+
+// maskInsert masks the values of the masked columns of an INSERT statement, whose columns are listed at
+// position p: INSERT INTO table (columns) VALUES (values), (values). It returns the position following
+// the values.
+func (m *columnMasker) maskInsert(p int, table string) int {
+	var columns []bool
+	for p++; p < len(m.significant) && m.at(p).text != ")"; p++ {
+		if isName(m.at(p)) {
+			var column string
+			_, column, p = m.name(p)
+			columns = append(columns, m.isMasked(table, column))
+			p--
+		}
+	}
+	p++
+	if !m.at(p).isKeyword("VALUES") || !slices.Contains(columns, true) {
+		return p
+	}
+
+	for p++; m.at(p).text == "("; p++ {
+		var column, depth int
+		for ; p < len(m.significant); p++ {
+			switch m.at(p).text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			case ",":
+				if depth == 1 {
+					column++
+				}
+			}
+			if depth == 0 {
+				p++
+				break
+			}
+			if column < len(columns) && columns[column] {
+				m.maskValue(p)
+			}
+		}
+		if m.at(p).text != "," {
+			return p
+		}
+	}
+	return p
+}
+
+// maskSQL masks the values of the masked columns in the SQL query, if any
+func (l logger) maskSQL(sql string) string {
+	if len(l.maskedColumns) == 0 {
+		return sql
+	}
+	return maskColumns(sql, l.maskedColumns)
+}
@@ -0,0 +1,113 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_maskColumns(t *testing.T) {
+	columns := []maskedColumn{
+		{table: "users", column: "email"},
+		{table: "payments", column: "card_number"},
+		{column: "token"},
+	}
+
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "SELECT * FROM users WHERE email = 'john@example.com' AND name = 'john'",
+			want: "SELECT * FROM users WHERE email = '***' AND name = 'john'",
+		},
+		{
+			sql:  `SELECT * FROM "users" WHERE "users"."email" IN ('a@example.com','b@example.com') LIMIT 1`,
+			want: `SELECT * FROM "users" WHERE "users"."email" IN ('***','***') LIMIT 1`,
+		},
+		{
+			sql:  "UPDATE `users` SET `email`=\"john@example.com\",`age`=42 WHERE `id` = 1",
+			want: "UPDATE `users` SET `email`='***',`age`=42 WHERE `id` = 1",
+		},
+		{
+			sql:  `INSERT INTO "payments" ("amount","card_number") VALUES (10,'4242424242424242'),(-20,'5555555555554444') RETURNING "id"`,
+			want: `INSERT INTO "payments" ("amount","card_number") VALUES (10,'***'),(-20,'***') RETURNING "id"`,
+		},
+		{
+			sql:  "SELECT * FROM sessions WHERE token <> 'secret' AND user_id BETWEEN 1 AND 2",
+			want: "SELECT * FROM sessions WHERE token <> '***' AND user_id BETWEEN 1 AND 2",
+		},
+		{
+			sql:  "SELECT * FROM sessions WHERE token NOT LIKE 'abc%' OR token BETWEEN -1 AND 2",
+			want: "SELECT * FROM sessions WHERE token NOT LIKE '***' OR token BETWEEN '***' AND '***'",
+		},
+		{
+			sql:  "SELECT * FROM orders JOIN users ON users.id = orders.user_id WHERE users.email = 'a' AND orders.email = 'b'",
+			want: "SELECT * FROM orders JOIN users ON users.id = orders.user_id WHERE users.email = '***' AND orders.email = 'b'",
+		},
+		{
+			sql:  `UPDATE "users" SET "email"="excluded"."email" WHERE "id" = 1`,
+			want: `UPDATE "users" SET "email"="excluded"."email" WHERE "id" = 1`,
+		},
+		{
+			sql:  "SELECT * FROM accounts WHERE email = 'john@example.com'",
+			want: "SELECT * FROM accounts WHERE email = 'john@example.com'",
+		},
+		{
+			sql:  "SELECT * FROM users WHERE lower(email) = 'john@example.com' AND LOWER(TRIM(users.email)) = LOWER('John')",
+			want: "SELECT * FROM users WHERE lower(email) = '***' AND LOWER(TRIM(users.email)) = LOWER('***')",
+		},
+		{
+			sql:  "SELECT * FROM sessions WHERE coalesce(token, '') IN ('a', 'b')",
+			want: "SELECT * FROM sessions WHERE coalesce(token, '') IN ('***', '***')",
+		},
+		{
+			sql:  "SELECT token, count(token) FROM sessions WHERE token = 'secret' GROUP BY token",
+			want: "SELECT token, count(token) FROM sessions WHERE token = '***' GROUP BY token",
+		},
+		{
+			sql:  "SELECT * FROM users WHERE email = ?",
+			want: "SELECT * FROM users WHERE email = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, maskColumns(tt.sql, columns))
+		})
+	}
+}
+
+func Test_logger_MaskedColumns(t *testing.T) {
+	t.Run("SQL query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithMaskedColumns("users.email")})
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM users WHERE email = 'john@example.com' AND id = 1", 1
+		}, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT * FROM users WHERE email = '***' AND id = 1"))
+	})
+
+	t.Run("Full SQL query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithDebugFullQueries(),
+			WithMaskedColumns("users.email"),
+			WithAsync(8),
+		})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE email = ? AND id = ?", "john@example.com", 1).Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.Equal(t, 2, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(QueryField, "DELETE FROM users WHERE email = '***' AND id = 1"))
+	})
+}
@@ -0,0 +1,350 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The templates of the messages logged by default for each LogType, see WithMessage. They are kept
+// stable across the releases, so that the alerting rules can match the messages.
+const (
+	ErrorMessage           = "{error}"
+	SlowQueryMessage       = "slow sql query [{elapsed} >= {threshold}]"
+	DefaultMessage         = "SQL query executed [{elapsed}]"
+	CommitMessage          = "transaction committed"
+	RollbackMessage        = "transaction rolled back"
+	SavepointMessage       = "{operation} executed [{savepoint}]"
+	LongTransactionMessage = "transaction open for too long [{elapsed} >= {threshold}]"
+	AsyncDropMessage       = "records dropped by the asynchronous mode, the buffer was full"
+	FullQueryMessage       = "full SQL query"
+	AuditMessage           = "{operation} on {table}"
+	SecurityMessage        = "suspicious SQL query"
+	DangerousWriteMessage  = "unscoped {operation} on {table}"
+	DDLMessage             = "DDL statement executed [{elapsed}]"
+	LargeQueryMessage      = "large sql query [{elapsed}]"
+	LargeResultMessage     = "large result set [{rows} rows]"
+	StatsMessage           = "records suppressed or failed since the last summary"
+	StartupMessage         = "slog-gorm configured"
+	DecisionMessage        = "SQL query not logged"
+	ExplainMessage         = "query plan of the slow query"
+	PlanChangeMessage      = "query plan changed"
+)
+
+// The templates of the messages logged by default with MetadataMessageOnly, the rows being logged in the message
+const (
+	SlowQueryRowsMessage   = "slow sql query [{elapsed} >= {threshold}, {rows} rows]"
+	DefaultRowsMessage     = "SQL query executed [{elapsed}, {rows} rows]"
+	LargeQueryRowsMessage  = "large sql query [{elapsed}, {rows} rows]"
+	LargeResultRowsMessage = "large result set [{elapsed}, {rows} rows]"
+)
+
+// The templates of the static messages logged by default with MetadataAttrsOnly or WithStaticMessages
+const (
+	SlowQueryStaticMessage      = "slow sql query"
+	DefaultStaticMessage        = "SQL query executed"
+	SavepointStaticMessage      = "{operation} executed"
+	DangerousWriteStaticMessage = "unscoped write"
+	DDLStaticMessage            = "DDL statement executed"
+	LargeQueryStaticMessage     = "large sql query"
+	LargeResultStaticMessage    = "large result set"
+)
+
+// MetadataMode defines where the duration and the number of rows of the SQL messages and of the slow
+// queries are logged, see WithMetadataMode
+type MetadataMode int
+
+const (
+	// MetadataBoth logs the duration in the message, and the duration and the rows as attributes (default)
+	MetadataBoth MetadataMode = iota
+	// MetadataAttrsOnly logs static messages, the duration and the rows as attributes only
+	MetadataAttrsOnly
+	// MetadataMessageOnly logs the duration and the rows in the message only, e.g. for the console
+	MetadataMessageOnly
+)
+
+// metadataModeNames are the names of the metadata modes in the configuration files
+var metadataModeNames = map[MetadataMode]string{
+	MetadataBoth:        "both",
+	MetadataAttrsOnly:   "attrs",
+	MetadataMessageOnly: "message",
+}
+
+// String returns the name of the metadata mode
+func (m MetadataMode) String() string {
+	if name, ok := metadataModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("MetadataMode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (m MetadataMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (m *MetadataMode) UnmarshalText(text []byte) error {
+	for mode, name := range metadataModeNames {
+		if strings.EqualFold(string(text), name) {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown metadata mode %q", text)
+}
+
+// MessageCatalog maps the log types to the templates of their messages, e.g. to localize them
+// or to pin them across the upgrades of the library, see WithMessageCatalog
+type MessageCatalog map[LogType]string
+
+// DefaultMessages returns the catalog of the messages logged by default, except the messages of
+// the asynchronous mode, of the statistics summary and of gorm itself which cannot be customized (the escalated
+// SQL errors keep the message of ErrorLogType, the slow queries of the maintenance windows the one of
+// SlowQueryLogType)
+func DefaultMessages() MessageCatalog {
+	return DefaultMessagesOf(MetadataBoth)
+}
+
+// DefaultMessagesOf returns the catalog of the messages logged by default with the given metadata mode, see
+// DefaultMessages. The static messages of WithStaticMessages are the ones of MetadataAttrsOnly.
+func DefaultMessagesOf(mode MetadataMode) MessageCatalog {
+	catalog := MessageCatalog{
+		ErrorLogType:           ErrorMessage,
+		SlowQueryLogType:       SlowQueryMessage,
+		DefaultLogType:         DefaultMessage,
+		CommitLogType:          CommitMessage,
+		RollbackLogType:        RollbackMessage,
+		SavepointLogType:       SavepointMessage,
+		LongTransactionLogType: LongTransactionMessage,
+		FullQueryLogType:       FullQueryMessage,
+		AuditLogType:           AuditMessage,
+		SecurityLogType:        SecurityMessage,
+		DangerousWriteLogType:  DangerousWriteMessage,
+		DDLLogType:             DDLMessage,
+		LargeQueryLogType:      LargeQueryMessage,
+		LargeResultLogType:     LargeResultMessage,
+	}
+
+	switch mode {
+	case MetadataMessageOnly:
+		catalog[SlowQueryLogType] = SlowQueryRowsMessage
+		catalog[DefaultLogType] = DefaultRowsMessage
+		catalog[LargeQueryLogType] = LargeQueryRowsMessage
+		catalog[LargeResultLogType] = LargeResultRowsMessage
+	case MetadataAttrsOnly:
+		catalog[SlowQueryLogType] = SlowQueryStaticMessage
+		catalog[DefaultLogType] = DefaultStaticMessage
+		catalog[SavepointLogType] = SavepointStaticMessage
+		catalog[DangerousWriteLogType] = DangerousWriteStaticMessage
+		catalog[DDLLogType] = DDLStaticMessage
+		catalog[LargeQueryLogType] = LargeQueryStaticMessage
+		catalog[LargeResultLogType] = LargeResultStaticMessage
+	}
+	return catalog
+}
+
+// defaultMessageFormats are the compiled templates of the default messages of each metadata mode
+var defaultMessageFormats = compileDefaultMessages()
+
+// compileDefaultMessages compiles the templates of the default messages of each metadata mode
+func compileDefaultMessages() map[MetadataMode]map[LogType]func(ctx context.Context, event messageEvent) string {
+	formats := make(map[MetadataMode]map[LogType]func(ctx context.Context, event messageEvent) string)
+	for mode := range metadataModeNames {
+		formats[mode] = make(map[LogType]func(ctx context.Context, event messageEvent) string)
+		for logType, template := range DefaultMessagesOf(mode) {
+			format, err := compileMessageTemplate(template)
+			if err != nil {
+				panic(err)
+			}
+			formats[mode][logType] = format
+		}
+	}
+	return formats
+}
+
+// MessageInfo describes a record whose message is formatted by a message function, see WithMessageFunc
+type MessageInfo struct {
+	Type LogType
+
+	// SQL, Operation, Table and Rows describe the SQL query, empty for the transactions
+	SQL       string
+	Operation string
+	Table     string
+	Rows      int64
+
+	// Elapsed is the duration of the SQL query or of the transaction
+	Elapsed time.Duration
+	// Threshold is the slow threshold of the SQL queries, or the threshold of the transaction watchdog
+	Threshold time.Duration
+	// Statements is the number of statements executed by the transaction
+	Statements int64
+	// Savepoint is the name of the savepoint
+	Savepoint string
+	Err       error
+}
+
+// messageEvent describes a record whose message is formatted, the SQL query being resolved if needed only
+type messageEvent struct {
+	logType    LogType
+	query      *lazyQuery
+	elapsed    time.Duration
+	threshold  time.Duration
+	statements int64
+	savepoint  string
+	err        error
+
+	// operation overrides the operation of the SQL query, e.g. for the savepoints
+	operation string
+}
+
+// queryOperation returns the operation of the record, if any
+func (e messageEvent) queryOperation() string {
+	if e.operation != "" || e.query == nil {
+		return e.operation
+	}
+	return e.query.Operation()
+}
+
+// info returns the description of the record given to the message functions
+func (e messageEvent) info() MessageInfo {
+	info := MessageInfo{
+		Type:       e.logType,
+		Elapsed:    e.elapsed,
+		Threshold:  e.threshold,
+		Statements: e.statements,
+		Savepoint:  e.savepoint,
+		Err:        e.err,
+	}
+	if e.query != nil {
+		info.SQL = e.query.SQL()
+		info.Operation = e.queryOperation()
+		info.Table = e.query.Table()
+		info.Rows = e.query.Rows()
+	}
+	return info
+}
+
+// customMessage formats the messages of a LogType
+type customMessage struct {
+	// template is the template of the messages, empty for a message function
+	template string
+	format   func(ctx context.Context, event messageEvent) string
+}
+
+// messagePlaceholders are the placeholders of the message templates, formatting a value of the record
+var messagePlaceholders = map[string]func(b *strings.Builder, event messageEvent){
+	"elapsed": func(b *strings.Builder, event messageEvent) {
+		b.WriteString(event.elapsed.String())
+	},
+	"threshold": func(b *strings.Builder, event messageEvent) {
+		b.WriteString(event.threshold.String())
+	},
+	"rows": func(b *strings.Builder, event messageEvent) {
+		if event.query != nil {
+			b.WriteString(strconv.FormatInt(event.query.Rows(), 10))
+		}
+	},
+	"table": func(b *strings.Builder, event messageEvent) {
+		if event.query != nil {
+			b.WriteString(event.query.Table())
+		}
+	},
+	"operation": func(b *strings.Builder, event messageEvent) {
+		b.WriteString(event.queryOperation())
+	},
+	"query": func(b *strings.Builder, event messageEvent) {
+		if event.query != nil {
+			b.WriteString(event.query.SQL())
+		}
+	},
+	"statements": func(b *strings.Builder, event messageEvent) {
+		b.WriteString(strconv.FormatInt(event.statements, 10))
+	},
+	"savepoint": func(b *strings.Builder, event messageEvent) {
+		b.WriteString(event.savepoint)
+	},
+	"error": func(b *strings.Builder, event messageEvent) {
+		if event.err != nil {
+			b.WriteString(event.err.Error())
+		}
+	},
+}
+
+// compileMessageTemplate compiles a message template, whose placeholders like {elapsed} are
+// replaced by the values of the record
+func compileMessageTemplate(template string) (func(ctx context.Context, event messageEvent) string, error) {
+	var parts []func(b *strings.Builder, event messageEvent)
+	for rest := template; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			parts = append(parts, literalMessagePart(rest))
+			break
+		}
+		if start > 0 {
+			parts = append(parts, literalMessagePart(rest[:start]))
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder in message template %q", template)
+		}
+		name := rest[start+1 : start+end]
+		placeholder, ok := messagePlaceholders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown placeholder {%s} in message template %q", name, template)
+		}
+		parts = append(parts, placeholder)
+		rest = rest[start+end+1:]
+	}
+
+	return func(_ context.Context, event messageEvent) string {
+		var b strings.Builder
+		for _, part := range parts {
+			part(&b, event)
+		}
+		return b.String()
+	}, nil
+}
+
+// literalMessagePart returns a part of a message template writing the given text
+func literalMessagePart(text string) func(b *strings.Builder, event messageEvent) {
+	return func(b *strings.Builder, _ messageEvent) {
+		b.WriteString(text)
+	}
+}
+
+// setMessage registers the custom message of the LogType, if it is logged by the logger
+func (l *logger) setMessage(logType LogType, message customMessage) {
+	if _, ok := l.logLevel[logType]; !ok || logType == AsyncDropLogType || logType == StatsLogType || logType == StartupLogType ||
+		logType == DecisionLogType || logType == ExplainLogType || logType == PlanChangeLogType ||
+		logType == GormInfoLogType || logType == GormWarnLogType || logType == GormErrorLogType ||
+		logType == EscalatedErrorLogType || logType == MaintenanceLogType {
+		l.invalidOption("unsupported log type %q for a custom message", logType)
+		return
+	}
+	if l.messages == nil {
+		l.messages = make(map[LogType]customMessage)
+	}
+	l.messages[logType] = message
+}
+
+// defaultMessage returns the default message of the record, see DefaultMessagesOf
+func (l logger) defaultMessage(ctx context.Context, event messageEvent) string {
+	mode := l.metadataMode
+	if l.staticMessages {
+		mode = MetadataAttrsOnly
+	}
+	return defaultMessageFormats[mode][event.logType](ctx, event)
+}
+
+// formatMessage returns the custom message of the record, if any
+func (l logger) formatMessage(ctx context.Context, event messageEvent) (string, bool) {
+	message, ok := l.messages[event.logType]
+	if !ok {
+		return "", false
+	}
+	return message.format(ctx, event), true
+}
@@ -0,0 +1,298 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_compileMessageTemplate(t *testing.T) {
+	event := messageEvent{
+		logType: SlowQueryLogType,
+		query: newLazyQuery(func() (string, int64) {
+			return "SELECT * FROM users", 3
+		}),
+		elapsed:    1500 * time.Millisecond,
+		threshold:  time.Second,
+		statements: 2,
+		savepoint:  "sp1",
+		err:        errors.New("an error"),
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "slow query", expected: "slow query"},
+		{template: "slow {operation} on {table} [{elapsed} >= {threshold}]", expected: "slow SELECT on users [1.5s >= 1s]"},
+		{template: "{rows} rows: {query}", expected: "3 rows: SELECT * FROM users"},
+		{template: "{statements} statements, {savepoint}: {error}", expected: "2 statements, sp1: an error"},
+		{template: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			format, err := compileMessageTemplate(tt.template)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format(context.Background(), event))
+		})
+	}
+
+	t.Run("Invalid templates", func(t *testing.T) {
+		_, err := compileMessageTemplate("slow query on {tables}")
+		assert.Error(t, err)
+
+		_, err = compileMessageTemplate("slow query on {table")
+		assert.Error(t, err)
+	})
+}
+
+func Test_logger_Message(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("Slow query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Nanosecond),
+			WithMessage(SlowQueryLogType, "slow query on {table} over {threshold}"),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "slow query on users over 1ns", receiver.Record.Message)
+	})
+
+	t.Run("Message function", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithMessageFunc(DefaultLogType, func(ctx context.Context, info MessageInfo) string {
+				return info.Operation + " " + info.Table
+			}),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "SELECT users", receiver.Record.Message)
+	})
+
+	t.Run("Other log types", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithMessage(ErrorLogType, "query failed"),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Contains(t, receiver.Record.Message, "SQL query executed")
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithMessage(RollbackLogType, "rollback after {statements} statements"),
+		})
+		db := openTestDB(t, gormLogger)
+
+		tx := db.Begin()
+		require.NoError(t, tx.Exec("DELETE FROM users").Error)
+		require.NoError(t, tx.Rollback().Error)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "rollback after 1 statements", receiver.Record.Message)
+	})
+}
+
+func TestDefaultMessages(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+	catalog := DefaultMessages()
+
+	tests := []struct {
+		name    string
+		logType LogType
+		options []Option
+		err     error
+	}{
+		{name: "Default", logType: DefaultLogType, options: []Option{WithTraceAll()}},
+		{name: "Slow query", logType: SlowQueryLogType, options: []Option{WithSlowThreshold(time.Nanosecond)}},
+		{name: "Error", logType: ErrorLogType, err: errors.New("an error")},
+		{name: "Large query", logType: LargeQueryLogType, options: []Option{WithMaxQueryBytesWarn(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver, gormLogger := getReceiverAndLogger(tt.options)
+
+			gormLogger.Trace(context.Background(), time.Now(), fc, tt.err)
+
+			// The default messages are formatted like the templates of the catalog
+			require.NotNil(t, receiver.Record)
+			var elapsed time.Duration
+			receiver.Record.Attrs(func(attr slog.Attr) bool {
+				if attr.Key == DurationField {
+					elapsed = attr.Value.Duration()
+				}
+				return true
+			})
+			format, err := compileMessageTemplate(catalog[tt.logType])
+			require.NoError(t, err)
+			assert.Equal(t, receiver.Record.Message, format(context.Background(), messageEvent{
+				logType:   tt.logType,
+				elapsed:   elapsed,
+				threshold: gormLogger.slowThreshold,
+				err:       tt.err,
+			}))
+		})
+	}
+
+	t.Run("Metadata modes", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		elapsed := 5 * time.Millisecond
+		cases := []struct {
+			name    string
+			logType LogType
+			sql     string
+			options []Option
+			err     error
+		}{
+			{name: "Default", logType: DefaultLogType, options: []Option{WithTraceAll()}},
+			{name: "Slow query", logType: SlowQueryLogType, options: []Option{WithSlowThreshold(time.Millisecond)}},
+			{name: "Error", logType: ErrorLogType, err: errors.New("an error")},
+			{name: "Large query", logType: LargeQueryLogType, options: []Option{WithMaxQueryBytesWarn(1)}},
+			{name: "Large result", logType: LargeResultLogType, options: []Option{WithLargeResultThreshold(1)}},
+			{name: "Dangerous write", logType: DangerousWriteLogType, sql: "DELETE FROM users", options: []Option{WithDangerousWriteDetection()}},
+		}
+		modes := map[string][]Option{
+			"both":            {WithMetadataMode(MetadataBoth)},
+			"attrs":           {WithMetadataMode(MetadataAttrsOnly)},
+			"message":         {WithMetadataMode(MetadataMessageOnly)},
+			"static messages": {WithStaticMessages()},
+		}
+
+		for modeName, modeOptions := range modes {
+			for _, tt := range cases {
+				t.Run(modeName+"/"+tt.name, func(t *testing.T) {
+					sql := tt.sql
+					if sql == "" {
+						sql = "SELECT * FROM users"
+					}
+					fc := func() (string, int64) { return sql, 2 }
+					options := append([]Option{WithClock(fixedClock{now: now})}, modeOptions...)
+					receiver, gormLogger := getReceiverAndLogger(append(options, tt.options...))
+
+					gormLogger.Trace(context.Background(), now.Add(-elapsed), fc, tt.err)
+
+					// The message emitted is the template of the catalog of the metadata mode
+					mode := gormLogger.metadataMode
+					if gormLogger.staticMessages {
+						mode = MetadataAttrsOnly
+					}
+					require.NotNil(t, receiver.Record)
+					format, err := compileMessageTemplate(DefaultMessagesOf(mode)[tt.logType])
+					require.NoError(t, err)
+					assert.Equal(t, format(context.Background(), messageEvent{
+						logType:   tt.logType,
+						query:     newLazyQuery(fc),
+						elapsed:   elapsed,
+						threshold: gormLogger.slowThreshold,
+						err:       tt.err,
+					}), receiver.Record.Message)
+				})
+			}
+		}
+	})
+
+	t.Run("Savepoint", func(t *testing.T) {
+		format, err := compileMessageTemplate(catalog[SavepointLogType])
+		require.NoError(t, err)
+		assert.Equal(t, "RELEASE SAVEPOINT executed [sp1]", format(context.Background(), messageEvent{
+			logType:   SavepointLogType,
+			savepoint: "sp1",
+			operation: "RELEASE SAVEPOINT",
+		}))
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		catalog := DefaultMessages()
+		catalog[SlowQueryLogType] = "requête lente sur {table}"
+
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Nanosecond),
+			WithMessageCatalog(catalog),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "requête lente sur users", receiver.Record.Message)
+		assert.Len(t, gormLogger.messages, len(catalog))
+	})
+}
+
+func Test_logger_MetadataMode(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 3
+	}
+
+	t.Run("Message only", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithMetadataMode(MetadataMessageOnly)})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Regexp(t, `^SQL query executed \[.+, 3 rows\]$`, receiver.Record.Message)
+		assertNoAttr(t, receiver.Record, DurationField)
+		assertNoAttr(t, receiver.Record, RowsField)
+	})
+
+	t.Run("Slow query in the message only", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Nanosecond),
+			WithMetadataMode(MetadataMessageOnly),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Regexp(t, `^slow sql query \[.+ >= 1ns, 3 rows\]$`, receiver.Record.Message)
+		assertNoAttr(t, receiver.Record, DurationField)
+	})
+
+	t.Run("Attributes only", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithMetadataMode(MetadataAttrsOnly)})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "SQL query executed", receiver.Record.Message)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.Int64(RowsField, 3))
+		assert.Equal(t, MetadataAttrsOnly, gormLogger.Config().MetadataMode)
+		assert.True(t, gormLogger.Config().StaticMessages)
+	})
+}
+
+func TestMetadataMode_Text(t *testing.T) {
+	for _, mode := range []MetadataMode{MetadataBoth, MetadataAttrsOnly, MetadataMessageOnly} {
+		text, err := mode.MarshalText()
+		require.NoError(t, err)
+
+		var actual MetadataMode
+		require.NoError(t, actual.UnmarshalText(text))
+		assert.Equal(t, mode, actual)
+	}
+
+	assert.Equal(t, "message", MetadataMessageOnly.String())
+	assert.Equal(t, "MetadataMode(42)", MetadataMode(42).String())
+	assert.Error(t, new(MetadataMode).UnmarshalText([]byte("unknown")))
+}
@@ -0,0 +1,93 @@
+package slogGorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// staticErrors are the errors of gorm whose messages don't contain values, logged as is by WithNoValues
+var staticErrors = []error{
+	gorm.ErrRecordNotFound,
+	gorm.ErrInvalidTransaction,
+	gorm.ErrNotImplemented,
+	gorm.ErrMissingWhereClause,
+	gorm.ErrUnsupportedRelation,
+	gorm.ErrPrimaryKeyRequired,
+	gorm.ErrModelValueRequired,
+	gorm.ErrModelAccessibleFieldsRequired,
+	gorm.ErrSubQueryRequired,
+	gorm.ErrInvalidData,
+	gorm.ErrUnsupportedDriver,
+	gorm.ErrRegistered,
+	gorm.ErrInvalidField,
+	gorm.ErrEmptySlice,
+	gorm.ErrDryRunModeUnsupported,
+	gorm.ErrInvalidDB,
+	gorm.ErrInvalidValue,
+	gorm.ErrInvalidValueOfLength,
+	gorm.ErrPreloadNotAllowed,
+	gorm.ErrDuplicatedKey,
+	gorm.ErrForeignKeyViolated,
+}
+
+// noValuesError is an error whose message was replaced, as it may contain values
+type noValuesError struct {
+	msg string
+	// typ is the type of the original error
+	typ string
+}
+
+// Error implements error
+func (e noValuesError) Error() string {
+	return e.msg
+}
+
+// withoutValues returns an error describing the given error without the values its message may contain:
+// the message of the static errors of gorm, or the type of the error otherwise (e.g. "*pgconn.PgError").
+func withoutValues(err error) error {
+	if err == nil {
+		return nil
+	}
+	typ := errorType(err)
+	for _, static := range staticErrors {
+		if errors.Is(err, static) {
+			return noValuesError{msg: static.Error(), typ: typ}
+		}
+	}
+	return noValuesError{msg: typ, typ: typ}
+}
+
+// errorType returns the type of the error, or the type of the original error if its message was replaced
+func errorType(err error) string {
+	if e, ok := err.(noValuesError); ok {
+		return e.typ
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// allowedContextAttr reports whether the context attribute can be logged
+func (l logger) allowedContextAttr(name string) bool {
+	if !l.noValues {
+		return true
+	}
+	return slices.Contains(l.allowedContextAttrs, name)
+}
+
+// sanitizeSQL returns the SQL query without its values (see WithNoValues) or with its masked columns
+// (see WithMaskedColumns), with its RETURNING clauses redacted (see WithReturningRedaction) and without
+// its comments (see WithoutComments)
+func (l logger) sanitizeSQL(sql string) string {
+	if l.noValues {
+		// The fingerprint removes the comments
+		return l.redactReturning(fingerprint(sql))
+	}
+	return l.redactReturning(l.maskSQL(l.stripComments(sql)))
+}
+
+// sanitizesSQL reports whether the SQL queries logged are sanitized, see sanitizeSQL
+func (l logger) sanitizesSQL() bool {
+	return l.noValues || len(l.maskedColumns) > 0 || l.returningRedaction != ReturningKept || l.withoutComments
+}
@@ -0,0 +1,117 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// valuesError is an error of a driver whose message contains values
+type valuesError struct{}
+
+func (valuesError) Error() string {
+	return `duplicate key value "john@example.com"`
+}
+
+func Test_logger_NoValues(t *testing.T) {
+	ctx := context.WithValue(context.WithValue(context.Background(), "request_id", "42"), "user", "john@example.com")
+	options := []Option{
+		WithTraceAll(),
+		WithNoValues("request_id"),
+		WithContextValue("request_id", "request_id"),
+		WithContextValue("user", "user"),
+	}
+
+	t.Run("SQL query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "SELECT * FROM users WHERE email = 'john@example.com'", 1
+		}, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT * FROM users WHERE email = ?"))
+		assertHasAttr(t, &resolved, slog.Int64(RowsField, 1))
+		assertHasAttr(t, &resolved, slog.String("request_id", "42"))
+		assertNoAttr(t, &resolved, "user")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "INSERT INTO users (email) VALUES ('john@example.com')", 0
+		}, fmt.Errorf("insert: %w", valuesError{}))
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "*fmt.wrapError", receiver.Record.Message)
+		assertHasAttr(t, receiver.Record, slog.Any(ErrorField, noValuesError{msg: "*fmt.wrapError", typ: "*fmt.wrapError"}))
+	})
+
+	t.Run("Static error", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(append(options, WithRecordNotFoundError()))
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "SELECT * FROM users WHERE id = 1", 0
+		}, gorm.ErrRecordNotFound)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, gorm.ErrRecordNotFound.Error(), receiver.Record.Message)
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Warn(ctx, "failed to parse %v", "john@example.com")
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "failed to parse %v", receiver.Record.Message)
+	})
+
+	t.Run("Plugin mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithNoValues(), WithDebugFullQueries(), WithAsync(8)})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE email = ?", "john@example.com").Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(QueryField, "DELETE FROM users WHERE email = ?"))
+	})
+}
+
+func Test_logger_sanitizeSQL_Lazy(t *testing.T) {
+	receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithNoValues()})
+	var calls int
+
+	gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+		calls++
+		return "SELECT * FROM users WHERE email = 'john@example.com'", 1
+	}, nil)
+
+	require.NotNil(t, receiver.Record)
+	assert.Zero(t, calls, "the SQL query is only sanitized when the handler formats the record")
+	resolved := resolveRecord(*receiver.Record)
+	assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT * FROM users WHERE email = ?"))
+	assert.Equal(t, 1, calls)
+}
+
+func Test_withoutValues(t *testing.T) {
+	assert.Nil(t, withoutValues(nil))
+	assert.EqualError(t, withoutValues(fmt.Errorf("query: %w", gorm.ErrDuplicatedKey)), gorm.ErrDuplicatedKey.Error())
+	assert.EqualError(t, withoutValues(valuesError{}), "slogGorm.valuesError")
+	assert.EqualError(t, withoutValues(errors.New("value")), "*errors.errorString")
+}
+
+func Test_errorType(t *testing.T) {
+	assert.Equal(t, "slogGorm.valuesError", errorType(valuesError{}))
+	assert.Equal(t, "*fmt.wrapError", errorType(withoutValues(fmt.Errorf("query: %w", gorm.ErrDuplicatedKey))))
+}
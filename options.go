@@ -2,12 +2,28 @@ package slogGorm
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Option func(l *logger)
 
+// ErrInvalidOption is the error wrapped by the errors of the invalid options reported by NewE
+var ErrInvalidOption = errors.New("slog-gorm: invalid option")
+
+// invalidOption reports an invalid option, which is not applied
+func (l *logger) invalidOption(format string, args ...any) {
+	l.errs = append(l.errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...))
+}
+
 // WithLogger defines a custom logger to use
 //
 // Deprecated: Use WithHandler instead
@@ -26,6 +42,60 @@ func WithHandler(handler slog.Handler) Option {
 	}
 }
 
+// WithFallbackHandler writes the records the handler fails to write (e.g. a network syslog being down) with
+// the given handler, so that they are not silently lost. The failures of the handler are counted by Stats,
+// with or without fallback handler. The records of the audit handler (see WithAudit) have no fallback.
+func WithFallbackHandler(handler slog.Handler) Option {
+	return func(l *logger) {
+		if handler == nil {
+			l.invalidOption("nil fallback handler")
+			return
+		}
+		l.fallbackHandler = handler
+	}
+}
+
+// WithStatsSummary logs periodically, at the StatsLogType level (slog.LevelInfo by default), the number of
+// records suppressed by the filters, discarded by the sampling or by the asynchronous mode, and failed at the
+// handler since the last summary, so that the losses of the logger are visible in the logs. The summary is
+// logged by the first query traced after each interval, and only if a counter was incremented in the meantime.
+// The counters are reported by Stats whatever the summary.
+func WithStatsSummary(interval time.Duration) Option {
+	return func(l *logger) {
+		if interval <= 0 {
+			l.invalidOption("non-positive stats summary interval %s", interval)
+			return
+		}
+		l.statsSummary = &statsSummary{interval: interval}
+	}
+}
+
+// WithAsync enables the asynchronous mode: the records are queued in a buffer of the given size
+// and written by a background worker, so that the I/O of the handler does not slow down the queries.
+// Logging blocks while the buffer is full. Use Flush and Close to write the queued records on shutdown.
+func WithAsync(bufferSize int) Option {
+	return func(l *logger) {
+		if bufferSize < 0 {
+			l.invalidOption("negative asynchronous buffer size %d", bufferSize)
+			return
+		}
+		l.asyncBufferSize = bufferSize
+	}
+}
+
+// WithAsyncDropPolicy defines the behavior of the asynchronous mode when its buffer is full.
+// The records dropped are counted (see Stats) and reported by a record logged with the
+// AsyncDropLogType level.
+func WithAsyncDropPolicy(policy DropPolicy) Option {
+	return func(l *logger) {
+		if _, ok := dropPolicyNames[policy]; !ok {
+			l.invalidOption("unknown asynchronous drop policy %s", policy)
+			return
+		}
+		l.asyncDropPolicy = policy
+	}
+}
+
 // WithSourceField defines the field to set the file name and line number of the current file
 func WithSourceField(field string) Option {
 	return func(l *logger) {
@@ -33,6 +103,151 @@ func WithSourceField(field string) Option {
 	}
 }
 
+// WithoutSourceField disables the field of the file name and line number of the current file,
+// so that the stack is not walked for each query.
+func WithoutSourceField() Option {
+	return func(l *logger) {
+		l.sourceField = ""
+	}
+}
+
+// WithCallerFunction adds the package and the function calling gorm, i.e. the first frame outside of gorm,
+// to the records (PackageField and FunctionField), so that the logs can be filtered by the layer of the
+// application, e.g. package "github.com/app/repository" and function "(*Users).Find".
+func WithCallerFunction() Option {
+	return func(l *logger) {
+		l.callerFunction = true
+	}
+}
+
+// WithSourceCacheSize defines the number of call sites whose file name and line number are
+// cached, so that the queries executed from the same call site don't resolve the stack again
+// (1024 by default). The cache is reset once full, and disabled when size is zero or less.
+func WithSourceCacheSize(size int) Option {
+	return func(l *logger) {
+		l.sourceCacheSize = size
+	}
+}
+
+// WithOtelSemconvFields names the attributes after the semantic conventions of OpenTelemetry, so that the
+// records correlate with the spans in the OpenTelemetry pipelines: the SQL query is logged as db.statement,
+// with its operation (db.operation.name) and main table (db.collection.name). In plugin mode (see Initialize),
+// the database is also logged as db.system.
+func WithOtelSemconvFields() Option {
+	return func(l *logger) {
+		l.preset = FieldPresetFunc(otelSemconvPreset)
+		l.presetName = "otel"
+	}
+}
+
+// WithECSFields names the attributes after the Elastic Common Schema, so that the records are indexed
+// by Elasticsearch without ReplaceAttr shims: the SQL query is logged as db.statement, the duration as
+// event.duration (in nanoseconds), the error as error.message and error.type, and the source as
+// log.origin.file.name and log.origin.file.line.
+func WithECSFields() Option {
+	return func(l *logger) {
+		l.preset = FieldPresetFunc(ecsPreset)
+		l.presetName = "ecs"
+	}
+}
+
+// WithGoogleCloudFields names the attributes after the structured logs of Google Cloud Logging, as
+// parsed from the standard output by its agent: the level is logged as severity, the source as
+// logging.googleapis.com/sourceLocation and the durations in seconds like "1.5s".
+func WithGoogleCloudFields() Option {
+	return func(l *logger) {
+		l.preset = FieldPresetFunc(googleCloudPreset)
+		l.presetName = "google_cloud"
+	}
+}
+
+// WithDatadogFields names the attributes after the standard attributes of Datadog, so that the records
+// are linked to the APM traces: dd.trace_id and dd.span_id, the duration in nanoseconds, the SQL query as
+// db.statement, its operation as db.operation and resource.name as its operation and main table.
+//
+// The identifiers are converted from the hexadecimal identifiers of the OpenTelemetry span returned by
+// spanContext, which can be nil. For example, with go.opentelemetry.io/otel/trace:
+//
+//	slogGorm.WithDatadogFields(func(ctx context.Context) (string, string) {
+//		spanContext := trace.SpanContextFromContext(ctx)
+//		return spanContext.TraceID().String(), spanContext.SpanID().String()
+//	})
+func WithDatadogFields(spanContext func(ctx context.Context) (traceID, spanID string)) Option {
+	return func(l *logger) {
+		l.preset = newDatadogPreset(spanContext)
+		l.presetName = ""
+		if spanContext == nil {
+			l.presetName = "datadog"
+		}
+	}
+}
+
+// WithSpanContext stamps the SQL records with the identifiers of the span of their query, as trace_id and span_id,
+// so that the records are linked to the spans of a tracing plugin, e.g. the OpenTelemetry plugin of gorm
+// (github.com/uptrace/opentelemetry-go-extra/otelgorm) which starts a span before each query. The hexadecimal
+// identifiers are returned by spanContext, the records without a valid span (empty or made of zeros) being
+// logged as before. For example, with go.opentelemetry.io/otel/trace:
+//
+//	slogGorm.WithSpanContext(func(ctx context.Context) (string, string) {
+//		spanContext := trace.SpanContextFromContext(ctx)
+//		return spanContext.TraceID().String(), spanContext.SpanID().String()
+//	})
+func WithSpanContext(spanContext func(ctx context.Context) (traceID, spanID string)) Option {
+	return func(l *logger) {
+		if spanContext == nil {
+			l.invalidOption("nil span context")
+			return
+		}
+		l.spanContext = spanContext
+	}
+}
+
+// WithoutSpanStatement does not log the SQL query on the records linked to a span (see WithSpanContext), the span
+// of the tracing plugin carrying it as db.statement, so that the logs and the spans complement each other instead
+// of doubling the volume of the SQL queries. The companion records of the full SQL queries keep it.
+func WithoutSpanStatement() Option {
+	return func(l *logger) {
+		l.withoutSpanStatement = true
+	}
+}
+
+// WithPreset names the attributes after the FieldPreset registered under the given name with RegisterPreset,
+// including the built-in presets: "otel" (WithOtelSemconvFields), "ecs" (WithECSFields), "google_cloud"
+// (WithGoogleCloudFields) and "datadog" (WithDatadogFields without the identifiers of the spans).
+func WithPreset(name string) Option {
+	return func(l *logger) {
+		preset, ok := lookupPreset(name)
+		if !ok {
+			l.invalidOption("unknown field preset %q", name)
+			return
+		}
+		l.preset = preset
+		l.presetName = name
+	}
+}
+
+// WithFieldPreset names the attributes with the given FieldPreset, to map the records to a custom logging schema
+func WithFieldPreset(preset FieldPreset) Option {
+	return func(l *logger) {
+		l.preset = preset
+		l.presetName = ""
+	}
+}
+
+// WithKeyCase converts the keys of the attributes logged (e.g. "slow_threshold" to "slowThreshold"
+// with CamelCase), including the keys of the context attributes, so that the records follow the
+// conventions of the other logs. The segments of the namespaced keys like "db.statement" are converted
+// separately. The keys of the field presets are kept, as defined by their schema.
+func WithKeyCase(keyCase KeyCase) Option {
+	return func(l *logger) {
+		if _, ok := keyCaseNames[keyCase]; !ok {
+			l.invalidOption("unknown key case %s", keyCase)
+			return
+		}
+		l.keyCase = keyCase
+	}
+}
+
 // WithErrorField defines the field to set the error
 func WithErrorField(field string) Option {
 	return func(l *logger) {
@@ -43,10 +258,110 @@ func WithErrorField(field string) Option {
 // WithSlowThreshold defines the threshold above which a sql query is considered slow
 func WithSlowThreshold(threshold time.Duration) Option {
 	return func(l *logger) {
+		if threshold < 0 {
+			l.invalidOption("negative slow threshold %s", threshold)
+			return
+		}
 		l.slowThreshold = threshold
 	}
 }
 
+// WithSlowThresholdFor defines the slow threshold of the SQL queries whose context attribute of the given key has
+// the given value (e.g. a tenant running heavy analytical queries), the other queries keeping the slow threshold
+// of the logger. The key is the name of a context attribute (see WithContextValue and WithContextFunc) or
+// TenantField (see WithTenant), the value being compared with the string of its slog.Value. The threshold of
+// the first override registered matching the context applies.
+//
+// Usage:
+//
+//	slogGorm.New(
+//		slogGorm.WithSlowThreshold(200*time.Millisecond),
+//		slogGorm.WithTenant(tenantFromContext),
+//		slogGorm.WithSlowThresholdFor(slogGorm.TenantField, "acme", 5*time.Second),
+//	)
+func WithSlowThresholdFor(key, value string, threshold time.Duration) Option {
+	return func(l *logger) {
+		if threshold < 0 {
+			l.invalidOption("negative slow threshold %s for %s=%s", threshold, key, value)
+			return
+		}
+		l.addSlowThreshold(slowThresholdOverride{key: key, value: value, threshold: threshold})
+	}
+}
+
+// WithClock defines the clock timing the SQL queries and the transactions, and dating the records (the clock of
+// the system by default), so that the tests and the simulations control the time, e.g. to trigger the slow queries
+// deterministically. The watchdog of the transactions (see WithTransactionWatchdog) uses the timers of the system.
+func WithClock(clock Clock) Option {
+	return func(l *logger) {
+		if clock == nil {
+			l.invalidOption("nil clock")
+			return
+		}
+		l.clock = clock
+	}
+}
+
+// WithTransactionWatchdog warns when a transaction stays open longer than the given threshold
+// without being committed or rolled back. The logger must be registered as a gorm plugin.
+func WithTransactionWatchdog(threshold time.Duration) Option {
+	return func(l *logger) {
+		if threshold < 0 {
+			l.invalidOption("negative transaction watchdog threshold %s", threshold)
+			return
+		}
+		l.txWatchdogThreshold = threshold
+	}
+}
+
+// WithBurstCapture traces all the SQL queries for the given window after each SQL error, as with WithTraceAll,
+// so that the workload following the error, otherwise invisible, is captured. The burst capture is shared with
+// the loggers derived from. See ContextWithBurstCapture to trace the remaining SQL queries of a context instead.
+func WithBurstCapture(window time.Duration) Option {
+	return func(l *logger) {
+		if window <= 0 {
+			l.invalidOption("non-positive burst capture window %s", window)
+			return
+		}
+		l.burstWindow = window
+	}
+}
+
+// WithErrorEscalation escalates the SQL errors whose fingerprint (the fingerprints of the SQL query and of the
+// error message) occurs more than threshold times within the window: the following errors of the window are
+// logged at the EscalatedErrorLogType level (slog.LevelError+4 by default) with the escalated and error_count
+// attributes, so that the chronic failures stand out from the one-offs. Up to 1024 fingerprints are counted,
+// shared with the loggers derived from.
+func WithErrorEscalation(threshold int, window time.Duration) Option {
+	return func(l *logger) {
+		if threshold <= 0 || window <= 0 {
+			l.invalidOption("invalid error escalation of %d errors within %s", threshold, window)
+			return
+		}
+		l.escalationThreshold = threshold
+		l.escalationWindow = window
+	}
+}
+
+// WithMaintenanceSchedule declares the recurring maintenance windows, for which the schedule returns true (e.g.
+// every night from 2am to 4am), during which the slow queries are logged at the MaintenanceLogType level
+// instead of the SlowQueryLogType level, like with SuppressSlowUntil.
+//
+// Usage:
+//
+//	slogGorm.WithMaintenanceSchedule(func(now time.Time) bool {
+//		return now.UTC().Hour() >= 2 && now.UTC().Hour() < 4
+//	})
+func WithMaintenanceSchedule(schedule func(now time.Time) bool) Option {
+	return func(l *logger) {
+		if schedule == nil {
+			l.invalidOption("nil maintenance schedule")
+			return
+		}
+		l.maintenanceSchedule = schedule
+	}
+}
+
 // WithTraceAll enables mode which logs all SQL messages.
 func WithTraceAll() Option {
 	return func(l *logger) {
@@ -54,13 +369,718 @@ func WithTraceAll() Option {
 	}
 }
 
+// WithStaticMessages logs the slow queries and the SQL messages with static messages, without
+// the elapsed time and threshold which are already logged as attributes. It avoids formatting
+// the messages and eases matching them.
+func WithStaticMessages() Option {
+	return func(l *logger) {
+		l.staticMessages = true
+		l.metadataMode = MetadataAttrsOnly
+	}
+}
+
+// WithMetadataMode defines where the duration and the number of rows of the SQL messages and of the slow
+// queries are logged: in the message and as attributes (MetadataBoth by default), as attributes only with
+// static messages (MetadataAttrsOnly, like WithStaticMessages) for the JSON pipelines, or in the message only
+// (MetadataMessageOnly) for the console, e.g. "SQL query executed [1.2ms, 3 rows]".
+func WithMetadataMode(mode MetadataMode) Option {
+	return func(l *logger) {
+		if _, ok := metadataModeNames[mode]; !ok {
+			l.invalidOption("unknown metadata mode %s", mode)
+			return
+		}
+		l.metadataMode = mode
+		l.staticMessages = mode == MetadataAttrsOnly
+	}
+}
+
+// WithParameterizedQueries logs the SQL queries without their parameters (e.g. "SELECT * FROM users WHERE id = ?"),
+// like the ParameterizedQueries option of the gorm logger.
+func WithParameterizedQueries() Option {
+	return func(l *logger) {
+		l.parameterizedQueries = true
+	}
+}
+
+// WithDebugFullQueries logs the SQL queries without their parameters, like WithParameterizedQueries, and
+// a companion record with the full SQL query at the FullQueryLogType level (slog.LevelDebug by default)
+// when the handler enables it. The production logs stay compliant, while the full SQL queries can be
+// enabled locally. The logger must be registered as a gorm plugin (see Initialize).
+func WithDebugFullQueries() Option {
+	return func(l *logger) {
+		l.debugFullQueries = true
+	}
+}
+
+// WithMessage defines the template of the messages of the given LogType (e.g. "slow query on {table}"), so that
+// the messages match the alerting rules. The placeholders are replaced by the values of the record: {elapsed},
+// {threshold}, {rows}, {table}, {operation}, {query}, {statements}, {savepoint} and {error}.
+func WithMessage(logType LogType, template string) Option {
+	return func(l *logger) {
+		format, err := compileMessageTemplate(template)
+		if err != nil {
+			l.invalidOption("%w", err)
+			return
+		}
+		l.setMessage(logType, customMessage{template: template, format: format})
+	}
+}
+
+// WithMessageCatalog defines the templates of the messages of the log types of the catalog, see WithMessage
+// and DefaultMessages
+func WithMessageCatalog(catalog MessageCatalog) Option {
+	return func(l *logger) {
+		for logType, template := range catalog {
+			WithMessage(logType, template)(l)
+		}
+	}
+}
+
+// WithMessageFunc formats the messages of the given LogType with the given function
+func WithMessageFunc(logType LogType, fn func(ctx context.Context, info MessageInfo) string) Option {
+	return func(l *logger) {
+		if fn == nil {
+			l.invalidOption("nil message function for log type %q", logType)
+			return
+		}
+		l.setMessage(logType, customMessage{format: func(ctx context.Context, event messageEvent) string {
+			return fn(ctx, event.info())
+		}})
+	}
+}
+
+// WithScrubber removes the personal data from the SQL queries and the attributes (including the context
+// attributes) with the given Scrubber before the records are logged, e.g. to plug a centralized scrubbing.
+// The SQL queries of the logged records are then resolved while tracing them.
+func WithScrubber(scrubber Scrubber) Option {
+	return func(l *logger) {
+		if scrubber == nil {
+			l.invalidOption("nil scrubber")
+			return
+		}
+		l.scrubber = scrubber
+	}
+}
+
+// WithNoValues guarantees that no value is logged, e.g. for the services which must prove that their logs
+// contain no personal data: the SQL queries are logged without their parameters and replaced by their
+// fingerprint (e.g. "SELECT * FROM users WHERE email = ?"), the errors by their type unless they are static errors
+// of gorm, and the messages logged by gorm are not formatted. The durations, the rows and the tables are kept.
+// Only the context attributes with the given names are logged, and the full SQL queries are never logged
+// (see WithDebugFullQueries).
+func WithNoValues(allowedContextAttrs ...string) Option {
+	return func(l *logger) {
+		l.noValues = true
+		l.allowedContextAttrs = allowedContextAttrs
+	}
+}
+
+// WithMaskedColumns masks the values of the given columns in the SQL queries logged, keeping the other values
+// for the debugging: the columns are named "column" for the columns of all tables, or "table.column" (e.g.
+// "users.email"). The values compared or assigned to the columns (e.g. "email = 'john@example.com'" or
+// "SET email = ...") and the values inserted in the columns are replaced by '***'.
+func WithMaskedColumns(columns ...string) Option {
+	return func(l *logger) {
+		for _, name := range columns {
+			column, ok := parseMaskedColumn(name)
+			if !ok {
+				l.invalidOption("invalid masked column %q", name)
+				continue
+			}
+			l.maskedColumns = append(l.maskedColumns, column)
+		}
+	}
+}
+
+// WithRedactionVerification verifies that the SQL queries of the records about to be logged, once redacted
+// by WithNoValues, WithMaskedColumns or WithScrubber, don't contain any string or number literal, the values
+// masked by WithMaskedColumns aside. Each violation is reported to the given callback, or panics if it is nil,
+// to prove in the tests that the redaction is complete. The companion records of WithDebugFullQueries are
+// verified as well, but not the messages nor the other attributes. The SQL queries are resolved for each
+// record logged, so it is meant for the tests rather than the production.
+func WithRedactionVerification(report func(ctx context.Context, violation RedactionViolation)) Option {
+	return func(l *logger) {
+		l.redactionCheck = report
+		if report == nil {
+			l.redactionCheck = panicRedactionViolation
+		}
+	}
+}
+
+// WithAudit logs the statements modifying the data (INSERT, UPDATE and DELETE) synchronously with the given
+// handler, at the AuditLogType level (slog.LevelInfo by default), with the actor returned by the given function,
+// which can be nil. For example:
+//
+//	slogGorm.WithAudit(auditHandler, func(ctx context.Context) (slog.Value, bool) {
+//		user, ok := auth.UserFromContext(ctx)
+//		return slog.StringValue(user.ID), ok
+//	})
+func WithAudit(handler slog.Handler, actor func(ctx context.Context) (slog.Value, bool)) Option {
+	return func(l *logger) {
+		if handler == nil {
+			l.invalidOption("nil audit handler")
+			return
+		}
+		l.auditHandler = handler
+		l.auditActor = actor
+	}
+}
+
+// WithAuditHashChain chains the audit records (see WithAudit) with a rolling hash, so that the removed or altered
+// records are detected afterwards: each record has a sequence number (audit_seq) and the hash of its content
+// chained to the hash of the previous record (audit_hash), see AuditHash. The chain starts when the option is
+// applied, and is shared by the loggers derived with With.
+func WithAuditHashChain() Option {
+	return func(l *logger) {
+		l.auditChain = &auditChain{}
+	}
+}
+
+// WithSlowLog writes the slow queries (see WithSlowThreshold) to w in the format of the slow query log of
+// MySQL, so that they can be analyzed by its tools, like pt-query-digest. They are redacted like the records.
+func WithSlowLog(w io.Writer) Option {
+	return func(l *logger) {
+		if w == nil {
+			l.invalidOption("nil slow log writer")
+			return
+		}
+		l.slowLog = &slowLog{w: w}
+	}
+}
+
+// WithQueryLogFile writes all the queries to the query log file as JSON Lines, redacted like the records.
+// The capture can be enabled temporarily with Apply, then disabled with WithoutQueryLogFile.
+func WithQueryLogFile(file *QueryLogFile) Option {
+	return func(l *logger) {
+		if file == nil {
+			l.invalidOption("nil query log file")
+			return
+		}
+		l.queryLogFile = file
+	}
+}
+
+// WithoutQueryLogFile stops writing the queries to the query log file, see WithQueryLogFile. The file is not closed.
+func WithoutQueryLogFile() Option {
+	return func(l *logger) {
+		l.queryLogFile = nil
+	}
+}
+
+// WithSlowQueryLogFile writes the slow queries only (see WithSlowThreshold) to their own query log file as
+// JSON Lines, so that they are retained separately from the application logs. They are redacted like the records.
+func WithSlowQueryLogFile(file *QueryLogFile) Option {
+	return func(l *logger) {
+		if file == nil {
+			l.invalidOption("nil slow query log file")
+			return
+		}
+		l.slowQueryLogFile = file
+	}
+}
+
+// WithQueryStats aggregates the queries by fingerprint (see WithNoValues), reported by Stats and exported by
+// Stats().WriteCSV. Up to 1024 fingerprints are aggregated, the aggregates being shared by the loggers derived with With.
+func WithQueryStats() Option {
+	return func(l *logger) {
+		l.queryStats = &queryStats{}
+	}
+}
+
+// WithRouteStats aggregates the queries by route, the route being the value of the context attribute of the given
+// name (see WithContextValue, e.g. "http.route" with the slogormhttp package), logged on the records: their count,
+// their number of errors, their total and average durations and the 95th percentile of their last 1024 durations,
+// reported by Stats to find the endpoints loading the database the most. Up to 1024 routes are aggregated, the
+// queries without the attribute being ignored.
+func WithRouteStats(attr string) Option {
+	return func(l *logger) {
+		if attr == "" {
+			l.invalidOption("empty route attribute")
+			return
+		}
+		l.routeStats = &attrStats{attr: attr}
+	}
+}
+
+// WithJobStats aggregates the queries by background job, the job being the value of the context attribute of the
+// given name (see WithContextValue, e.g. "job" with the slogormjob package), logged on the records: like
+// WithRouteStats, their count, their number of errors, their total and average durations and the 95th percentile of
+// their last 1024 durations are reported by Stats, to find the cron jobs and the queue workers loading the database
+// the most. Up to 1024 jobs are aggregated, the queries without the attribute being ignored.
+func WithJobStats(attr string) Option {
+	return func(l *logger) {
+		if attr == "" {
+			l.invalidOption("empty job attribute")
+			return
+		}
+		l.jobStats = &attrStats{attr: attr}
+	}
+}
+
+// WithSink hands the queries to the given sinks as typed events redacted like the records, so that the metrics,
+// the audit stores or the custom exporters don't parse the records.
+func WithSink(sinks ...Sink) Option {
+	return func(l *logger) {
+		for _, sink := range sinks {
+			if sink == nil {
+				l.invalidOption("nil sink")
+				continue
+			}
+			l.sinks = append(l.sinks, sink)
+		}
+	}
+}
+
+// WithTracer records a span for each SQL query with the given tracer, with the attributes of the semantic
+// conventions of OpenTelemetry (see QuerySpan). Do not use it with a tracing plugin of gorm starting its own
+// spans, e.g. otelgorm, see WithSpanContext instead.
+//
+// As the modules of OpenTelemetry are not dependencies of slog-gorm, the tracer is written with them, e.g. with
+// the tracer of a TracerProvider:
+//
+//	tracer := otel.GetTracerProvider().Tracer("gorm")
+//	slogGorm.WithTracer(slogGorm.TracerFunc(func(ctx context.Context, s slogGorm.QuerySpan) {
+//		_, span := tracer.Start(ctx, s.Name, trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(s.Start))
+//		for _, attr := range s.Attrs {
+//			if attr.Value.Kind() == slog.KindInt64 {
+//				span.SetAttributes(attribute.Int64(attr.Key, attr.Value.Int64()))
+//			} else {
+//				span.SetAttributes(attribute.String(attr.Key, attr.Value.String()))
+//			}
+//		}
+//		if s.Err != nil {
+//			span.RecordError(s.Err)
+//			span.SetStatus(codes.Error, s.Err.Error())
+//		}
+//		span.End(trace.WithTimestamp(s.End))
+//	}))
+func WithTracer(tracer Tracer) Option {
+	return func(l *logger) {
+		if tracer == nil {
+			l.invalidOption("nil tracer")
+			return
+		}
+		l.tracer = tracer
+	}
+}
+
+// WithPprofLabels sets the pprof labels sql_fingerprint and sql_table (see FingerprintLabel and TableLabel)
+// while the SQL queries are executed, so that the CPU profiles taken during the incidents show which queries
+// the goroutines were serving. It requires the plugin mode, see Initialize.
+func WithPprofLabels() Option {
+	return func(l *logger) {
+		l.pprofLabels = true
+	}
+}
+
+// WithSecurityDetection logs the SQL queries suspicious of a SQL injection (e.g. "OR 1=1" or stacked
+// statements) at the SecurityLogType level (slog.LevelWarn by default), with the anomalies attribute.
+// The literals are only inspected in the SQL queries logged with their values, see WithParameterizedQueries.
+func WithSecurityDetection() Option {
+	return func(l *logger) {
+		l.securityDetection = true
+	}
+}
+
+// WithDangerousWriteDetection logs the UPDATE and DELETE statements without WHERE clause, including the
+// statements blocked by gorm (gorm.ErrMissingWhereClause), at the DangerousWriteLogType level (slog.LevelError
+// by default) with the dangerous_write attribute.
+func WithDangerousWriteDetection() Option {
+	return func(l *logger) {
+		l.dangerousWriteDetection = true
+	}
+}
+
+// WithName names the logger, e.g. after its database: the name is stamped on every record as the logger_name
+// attribute (see NameField), and the logger is registered under it, see Get. A logger created again with the
+// same name replaces it in the registry.
+func WithName(name string) Option {
+	return func(l *logger) {
+		if name == "" {
+			l.invalidOption("empty logger name")
+			return
+		}
+		l.name = name
+	}
+}
+
+// WithBuildInfo stamps every record with the slog_gorm attribute (see BuildInfoField), a group of the version
+// of slog-gorm built in the binary (see Version) and of the names of the features enabled (e.g. "async",
+// "no_values"), to explain the differences of behavior between the deployments. See WithStartupBuildInfo to
+// only stamp the startup summary.
+func WithBuildInfo() Option {
+	return func(l *logger) {
+		l.buildInfo = buildInfoAllRecords
+	}
+}
+
+// WithStartupBuildInfo stamps only the startup summary with the build information, see WithBuildInfo
+// and LogStartupSummary.
+func WithStartupBuildInfo() Option {
+	return func(l *logger) {
+		l.buildInfo = buildInfoStartup
+	}
+}
+
+// WithResource stamps the SQL records with the service, its version and its environment as the service.name,
+// service.version and deployment.environment attributes (see ServiceNameField), added to the handler with
+// slog.Handler.WithAttrs, so that the logs shipped from many services are self-describing. The empty values
+// are not stamped. See WithResourceFromEnv to read them from the environment of the OpenTelemetry SDKs.
+func WithResource(service, version, environment string) Option {
+	return func(l *logger) {
+		for _, attr := range []slog.Attr{
+			slog.String(ServiceNameField, service),
+			slog.String(ServiceVersionField, version),
+			slog.String(DeploymentEnvironmentField, environment),
+		} {
+			if attr.Value.String() != "" {
+				l.setResource(attr)
+			}
+		}
+	}
+}
+
+// WithResourceFromEnv stamps the SQL records with the resource attributes of the OTEL_RESOURCE_ATTRIBUTES
+// environment variable (e.g. "service.name=orders,deployment.environment=prod"), and with the service.name
+// of OTEL_SERVICE_NAME which takes precedence, like WithResource. The entries which cannot be parsed are
+// reported as invalid options.
+func WithResourceFromEnv() Option {
+	return func(l *logger) {
+		l.resourceFromEnv()
+	}
+}
+
+// WithKubernetesMetadata stamps the SQL records with the pod, its namespace and its node as the k8s.pod.name,
+// k8s.namespace.name and k8s.node.name attributes (see K8sPodNameField), read from the POD_NAME, POD_NAMESPACE
+// and NODE_NAME environment variables set with the downward API of Kubernetes, and added to the handler like
+// the resource attributes, see WithResource. The missing variables are skipped.
+func WithKubernetesMetadata() Option {
+	return func(l *logger) {
+		l.kubernetesFromEnv()
+	}
+}
+
+// WithContextAttrConflict defines how the context attributes (see WithContextValue and WithTenant) whose key
+// is also an attribute of the handler are logged: with duplicate keys (ConflictKept, by default), skipped,
+// overriding the attribute of the handler or renamed with the ConflictSuffix. The attributes of the handler
+// are the resource attributes (see WithResource) and those of the given keys, added to the handler before it
+// is given to the logger (e.g. with slog.Logger.With), which cannot be overridden: the context attributes
+// are kept with them.
+func WithContextAttrConflict(conflict ContextAttrConflict, handlerKeys ...string) Option {
+	return func(l *logger) {
+		if _, ok := contextAttrConflictNames[conflict]; !ok {
+			l.invalidOption("unknown context attribute conflict %s", conflict)
+			return
+		}
+		l.attrConflict = conflict
+		l.handlerKeys = slices.Clone(handlerKeys)
+	}
+}
+
+// WithHostInfo stamps the SQL records with the host name and the process ID as the host.name and process.pid
+// attributes (see HostNameField), computed once and added to the handler like the resource attributes, see
+// WithResource. The host name is omitted if it cannot be read.
+func WithHostInfo() Option {
+	return func(l *logger) {
+		for _, attr := range hostInfo() {
+			l.setResource(attr)
+		}
+	}
+}
+
+// WithDecisionDebug logs why each SQL query traced is not logged, at the DecisionLogType level (slog.LevelDebug
+// by default), to debug the filters and the sampling: the decision attribute is "filtered" (with the filter
+// attribute naming the filter, e.g. "ignored query ^SELECT 1$"), "sampled_out", "level_disabled",
+// "below_slow_threshold" or "not_traced" (neither slow nor traced, see WithTraceAll).
+func WithDecisionDebug() Option {
+	return func(l *logger) {
+		l.decisionDebug = true
+	}
+}
+
+// WithExplainOnSlow runs EXPLAIN with the given database for the slow queries, and logs their plan as the
+// plan attribute of the slow queries (or in companion records, see WithExplainRecord). To keep it safe, only
+// the single SELECT statements logged with their values (see WithParameterizedQueries) outside of a
+// transaction are explained, one per interval (see WithExplainInterval), and the plans are not captured with
+// WithNoValues or WithMaskedColumns as they may contain the values. The EXPLAIN statements are run
+// synchronously by the slow queries, and are not logged.
+func WithExplainOnSlow(db *gorm.DB, options ...ExplainOption) Option {
+	return func(l *logger) {
+		if db == nil {
+			l.invalidOption("nil database to explain the slow queries")
+			return
+		}
+		l.explainer = newExplainer(db, options)
+	}
+}
+
+// WithBindParamsCount adds the number of parameters bound to each statement as the bind_params attribute,
+// to spot the gigantic IN lists and the batched statements exceeding the limits of the planners and the
+// drivers. The parameters are counted by the gorm plugin, see Initialize: the attribute is missing from the
+// records of the queries executed without it.
+func WithBindParamsCount() Option {
+	return func(l *logger) {
+		l.bindParamsCount = true
+	}
+}
+
+// WithGoroutineID adds the ID of the goroutine executing each SQL query as the goroutine_id attribute, to
+// debug the concurrency issues (e.g. a connection reused across goroutines, interleaved transactions). It is
+// meant for debugging only: the IDs are reused by the runtime, and reading them walks the stack of each query.
+func WithGoroutineID() Option {
+	return func(l *logger) {
+		l.goroutineID = true
+	}
+}
+
+// WithQueryBytes adds the byte length of the SQL query logged as the query_bytes attribute, measured with
+// its values before they are removed or masked (see WithNoValues and WithMaskedColumns).
+func WithQueryBytes() Option {
+	return func(l *logger) {
+		l.queryBytes = true
+	}
+}
+
+// WithMaxQueryBytesWarn logs the SQL queries longer than the given number of bytes at the LargeQueryLogType
+// level (slog.LevelWarn by default) with the query_bytes attribute. The errors, the slow queries, the DDL
+// statements and the savepoints keep their own level.
+func WithMaxQueryBytesWarn(n int) Option {
+	return func(l *logger) {
+		if n <= 0 {
+			l.invalidOption("non-positive maximum query size %d", n)
+			return
+		}
+		l.maxQueryBytes = n
+	}
+}
+
+// WithLargeResultThreshold logs the SQL queries returning or affecting more rows than the threshold at the
+// LargeResultLogType level (slog.LevelWarn by default), to catch the accidental full-table reads. The errors,
+// the slow queries and the large queries (see WithMaxQueryBytesWarn) keep their own level.
+func WithLargeResultThreshold(rows int64) Option {
+	return func(l *logger) {
+		if rows <= 0 {
+			l.invalidOption("non-positive large result threshold %d", rows)
+			return
+		}
+		l.largeResultThreshold = rows
+	}
+}
+
+// WithBatchMetrics adds the number of rows inserted by the multi-row INSERT ... VALUES statements (e.g. by
+// gorm's CreateInBatches) as the batch_size attribute, and their duration per row as the row_latency attribute,
+// to monitor the performance of the bulk loads.
+func WithBatchMetrics() Option {
+	return func(l *logger) {
+		l.batchMetrics = true
+	}
+}
+
+// WithReturningRedaction strips (ReturningStripped) or masks (ReturningMasked) the RETURNING clauses of the
+// SQL queries logged, as they may disclose the values written, e.g. the columns generated from the personal
+// data, even with WithParameterizedQueries or WithNoValues.
+func WithReturningRedaction(redaction ReturningRedaction) Option {
+	return func(l *logger) {
+		if _, ok := returningRedactionNames[redaction]; !ok {
+			l.invalidOption("unknown RETURNING redaction %s", redaction)
+			return
+		}
+		l.returningRedaction = redaction
+	}
+}
+
+// WithoutComments removes the comments from the SQL queries logged, to reduce their size and not to leak the
+// internal annotations (e.g. the sqlcommenter tags) to the external log processors.
+func WithoutComments() Option {
+	return func(l *logger) {
+		l.withoutComments = true
+	}
+}
+
+// WithTables adds all the tables referenced by the SQL queries as the tables attribute, including their joins
+// and subqueries (e.g. ["orders", "users"]), to filter and aggregate the records per table. The tables are
+// also given to the query filters (see WithQueryFilter) and to the sinks (see WithSink).
+func WithTables() Option {
+	return func(l *logger) {
+		l.tables = true
+	}
+}
+
+// WithShardedTables adds the logical table and the physical shard table of the queries on the given tables sharded
+// by the sharding plugin of gorm (gorm.io/sharding), which logs the queries rewritten on the shards, as the
+// logical_table and shard_table attributes, so that the records can be analyzed per logical entity and per shard.
+// The shards are the tables named after a logical table followed by an underscore and a suffix of digits and
+// underscores, e.g. orders_01 or orders_2024_01; the queries on the logical table itself only have the
+// logical_table attribute.
+func WithShardedTables(tables ...string) Option {
+	return func(l *logger) {
+		for _, table := range tables {
+			if table == "" {
+				l.invalidOption("empty sharded table")
+				continue
+			}
+			l.shardedTables = append(l.shardedTables, table)
+		}
+	}
+}
+
+// WithSoftDeleteFlag flags the statements of the models with soft deletes (see gorm.DeletedAt) with the soft_delete
+// attribute: "delete" for the UPDATE statements generated by the soft deletes, which often explain why an UPDATE is
+// executed, and "filter" for the statements whose condition excluding the soft deleted rows (e.g. deleted_at IS
+// NULL) was added by gorm. The unscoped statements are not flagged. It requires the plugin mode, see Initialize.
+func WithSoftDeleteFlag() Option {
+	return func(l *logger) {
+		l.softDeleteFlag = true
+	}
+}
+
+// WithMinLevel defines the minimum level of the records logged, checked before the handler, so that the
+// verbosity of the logger is controlled independently of the handler shared with the application.
+// Use a *slog.LevelVar to change it at runtime.
+func WithMinLevel(level slog.Leveler) Option {
+	return func(l *logger) {
+		l.minLevel = level
+	}
+}
+
+// WithTenantLevels defines the minimum level of the records of the tenants for which fn returns a level,
+// e.g. to enable the verbose logging of the SQL queries of a tenant during its onboarding while the others
+// stay at the minimum level of the logger (see WithMinLevel). All the SQL queries of a tenant whose level
+// enables the DefaultLogType level are traced, like with WithTraceAll. The handler must accept the records
+// at the levels of the tenants, and fn is called for each record, so it should be fast.
+//
+// Usage:
+//
+//	slogGorm.WithTenantLevels(func(ctx context.Context) (slog.Level, bool) {
+//		if tenant, ok := ctx.Value(tenantKey).(string); ok && debuggedTenants[tenant] {
+//			return slog.LevelDebug, true
+//		}
+//		return 0, false
+//	})
+func WithTenantLevels(fn func(ctx context.Context) (slog.Level, bool)) Option {
+	return func(l *logger) {
+		if fn == nil {
+			l.invalidOption("nil tenant levels function")
+			return
+		}
+		l.tenantLevel = fn
+	}
+}
+
+// WithTenant stamps the tenant returned by fn for the context of each record as the tenant_id attribute
+// (before the context attributes), even with WithNoValues, and enables the sampling rates per tenant, see
+// WithTenantSamplingRates.
+func WithTenant(fn func(ctx context.Context) (string, bool)) Option {
+	return func(l *logger) {
+		if fn == nil {
+			l.invalidOption("nil tenant function")
+			return
+		}
+		l.tenant = fn
+	}
+}
+
+// WithTenantSamplingRates overrides the sampling rate of the SQL messages traced for the given tenants
+// (see WithSamplingRate and WithTenant), so that a noisy tenant cannot drown out the logs of the others.
+// The other tenants keep the sampling rate of the logger.
+func WithTenantSamplingRates(rates map[string]float64) Option {
+	return func(l *logger) {
+		for tenant, rate := range rates {
+			if rate < 0 || rate > 1 {
+				l.invalidOption("sampling rate %v of tenant %q out of [0, 1]", rate, tenant)
+				return
+			}
+		}
+		l.tenantSamplingRates = maps.Clone(rates)
+	}
+}
+
 // SetLogLevel sets a new slog.Level for a LogType.
 func SetLogLevel(key LogType, level slog.Level) Option {
 	return func(l *logger) {
+		if _, ok := l.logLevel[key]; !ok {
+			l.invalidOption("unknown log type %q", key)
+			return
+		}
 		l.logLevel[key] = level
 	}
 }
 
+// WithSilencedLogTypes turns off the records of the given log types entirely, whatever their level, e.g. to keep
+// the errors but silence the slow queries during a known maintenance backfill, without setting an absurd level.
+// The sinks, the statistics and the audit of the SQL queries are not affected, except AuditLogType which silences
+// the audit records. AsyncDropLogType and StartupLogType cannot be silenced. The log types replace those silenced
+// before, so that applying the option without log type logs them again.
+//
+// Usage:
+//
+//	gormLogger.Apply(slogGorm.WithSilencedLogTypes(slogGorm.SlowQueryLogType, slogGorm.LargeResultLogType))
+//	// ... once the backfill is done
+//	gormLogger.Apply(slogGorm.WithSilencedLogTypes())
+func WithSilencedLogTypes(logTypes ...LogType) Option {
+	return func(l *logger) {
+		var silenced []LogType
+		for _, logType := range logTypes {
+			if _, ok := l.logLevel[logType]; !ok {
+				l.invalidOption("unknown log type %q", logType)
+				continue
+			}
+			if slices.Contains(unsilenceableLogTypes, logType) {
+				l.invalidOption("log type %q cannot be silenced", logType)
+				continue
+			}
+			if !slices.Contains(silenced, logType) {
+				silenced = append(silenced, logType)
+			}
+		}
+		l.silencedLogTypes = silenced
+	}
+}
+
+// WithTablePolicy defines how the SQL queries on the given table are logged: the levels of their records, their
+// tracing or their suppression (except the errors and the dangerous writes), optionally for some operations only.
+// The policy of the first table policy registered matching the table and the operation of a query applies.
+//
+// Usage:
+//
+//	slogGorm.WithTablePolicy("payments", slogGorm.ModelPolicy{Operations: []string{"INSERT", "UPDATE"}, Trace: true})
+func WithTablePolicy(table string, policy ModelPolicy) Option {
+	return func(l *logger) {
+		if table == "" {
+			l.invalidOption("empty table of model policy")
+			return
+		}
+		for logType := range policy.Levels {
+			if _, ok := l.logLevel[logType]; !ok {
+				l.invalidOption("unknown log type %q of model policy %s", logType, table)
+				return
+			}
+		}
+		policy.Operations = slices.Clone(policy.Operations)
+		policy.Levels = maps.Clone(policy.Levels)
+		l.policies = append(l.policies, tablePolicy{table: table, policy: policy})
+	}
+}
+
+// WithModelPolicy defines how the SQL queries on the table of the given model are logged, see WithTablePolicy.
+// The table is named with the default naming strategy of gorm (or the TableName method of the model): use
+// WithTablePolicy with a custom naming strategy.
+//
+// Usage:
+//
+//	slogGorm.WithModelPolicy(&Payment{}, slogGorm.ModelPolicy{Operations: []string{"INSERT", "UPDATE"}, Trace: true}),
+//	slogGorm.WithModelPolicy(&AuditEvent{}, slogGorm.ModelPolicy{Operations: []string{"SELECT"}, Suppress: true}),
+func WithModelPolicy(model any, policy ModelPolicy) Option {
+	return func(l *logger) {
+		table, err := modelTable(model)
+		if err != nil {
+			l.invalidOption("model policy of %T: %v", model, err)
+			return
+		}
+		WithTablePolicy(table, policy)(l)
+	}
+}
+
 // WithRecordNotFoundError allows the slogger to log gorm.ErrRecordNotFound errors
 func WithRecordNotFoundError() Option {
 	return func(l *logger) {
@@ -75,13 +1095,11 @@ func WithIgnoreTrace() Option {
 	}
 }
 
-// WithContextValue adds a context value to the log
+// WithContextValue adds a context value to the log. The context attributes are logged in the order in
+// which they are registered, a context attribute registered again keeping its position.
 func WithContextValue(slogAttrName string, contextKey any) Option {
 	return func(l *logger) {
-		if l.contextKeys == nil {
-			l.contextKeys = make(map[string]any, 0)
-		}
-		l.contextKeys[slogAttrName] = contextKey
+		l.addContextAttr(contextAttr{name: slogAttrName, key: contextKey})
 	}
 }
 
@@ -91,9 +1109,103 @@ func WithContextValue(slogAttrName string, contextKey any) Option {
 // functions.
 func WithContextFunc(slogAttrName string, slogValueFunc func(ctx context.Context) (slog.Value, bool)) Option {
 	return func(l *logger) {
-		if l.contextFuncs == nil {
-			l.contextFuncs = make(map[string]func(context.Context) (slog.Value, bool))
+		l.addContextAttr(contextAttr{name: slogAttrName, fn: slogValueFunc})
+	}
+}
+
+// WithIgnoredOperations ignores the queries with the given operations (e.g. "SELECT"), except the errors
+func WithIgnoredOperations(operations ...string) Option {
+	return func(l *logger) {
+		l.filters = append(l.filters, newOperationFilter(operations))
+	}
+}
+
+// WithIgnoredTables ignores the queries on the given tables, except the errors
+func WithIgnoredTables(tables ...string) Option {
+	return func(l *logger) {
+		l.filters = append(l.filters, newTableFilter(tables))
+	}
+}
+
+// WithIgnoredQueries ignores the queries matching one of the given patterns, except the errors
+func WithIgnoredQueries(patterns ...*regexp.Regexp) Option {
+	return func(l *logger) {
+		for _, pattern := range patterns {
+			if pattern == nil {
+				l.invalidOption("nil ignored query pattern")
+				continue
+			}
+			l.filters = append(l.filters, newRegexpFilter(pattern))
+		}
+	}
+}
+
+// WithSamplingRate logs only the given rate of the SQL messages traced (e.g. 0.1 for 10%), chosen randomly.
+// The errors, the slow queries and the transactions are not sampled, unless set by WithSamplingRates.
+func WithSamplingRate(rate float64) Option {
+	return func(l *logger) {
+		if rate < 0 || rate > 1 {
+			l.invalidOption("sampling rate %v out of [0, 1]", rate)
+			return
+		}
+		l.samplingRate = rate
+	}
+}
+
+// WithSamplingRates sets the sampling rate of each log type of the SQL queries (e.g. 1 for the errors, 0.5 for
+// the slow queries and 0.01 for the SQL messages traced), the other log types keeping their sampling rate: the one
+// of WithSamplingRate for DefaultLogType, none for the others. The sampling rates of WithTenantSamplingRates take
+// precedence for DefaultLogType. The records kept with a rate below 1 have the sampling_rate attribute, so that
+// the statistics computed downstream can be weighted. The rates replace those set before.
+//
+// Usage:
+//
+//	slogGorm.WithSamplingRates(map[slogGorm.LogType]float64{
+//		slogGorm.ErrorLogType:     1,
+//		slogGorm.SlowQueryLogType: 0.5,
+//		slogGorm.DefaultLogType:   0.01,
+//	})
+func WithSamplingRates(rates map[LogType]float64) Option {
+	return func(l *logger) {
+		for logType, rate := range rates {
+			if !isSampledLogType(logType) {
+				l.invalidOption("log type %q cannot be sampled", logType)
+				return
+			}
+			if rate < 0 || rate > 1 {
+				l.invalidOption("sampling rate %v of log type %q out of [0, 1]", rate, logType)
+				return
+			}
+		}
+		l.logTypeSamplingRates = maps.Clone(rates)
+	}
+}
+
+// WithVolumeBudget adapts the sampling to log at most the given number of SQL records per minute, e.g. to protect
+// the bill of the logs during the traffic spikes. The errors, the dangerous writes and the DDL statements are never
+// dropped, the budget left being spent on the slow and large queries first, then on the SQL messages traced and the
+// savepoints: the sampling rate of each priority is computed from the volume of the previous minute, and the records
+// are dropped once the budget of the minute is spent. The sampling rate is logged with the sampling_rate attribute,
+// combined with the one of WithSamplingRate and WithSamplingRates, and the records dropped are counted in
+// Stats().SampledOut. The budget is shared with the loggers derived from.
+func WithVolumeBudget(maxRecordsPerMinute int) Option {
+	return func(l *logger) {
+		if maxRecordsPerMinute <= 0 {
+			l.invalidOption("non-positive volume budget %d", maxRecordsPerMinute)
+			return
+		}
+		l.volumeBudget = maxRecordsPerMinute
+	}
+}
+
+// WithQueryFilter ignores the queries for which the given function returns false, except the errors.
+// The functions are evaluated after the other filters.
+func WithQueryFilter(fn func(ctx context.Context, query QueryInfo) bool) Option {
+	return func(l *logger) {
+		if fn == nil {
+			l.invalidOption("nil query filter")
+			return
 		}
-		l.contextFuncs[slogAttrName] = slogValueFunc
+		l.filters = append(l.filters, newFuncFilter(fn))
 	}
 }
@@ -1,11 +1,17 @@
 package slogGorm
 
 import (
+	"context"
+	"io"
 	"log/slog"
+	"os"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestWithTraceAll(t *testing.T) {
@@ -16,6 +22,14 @@ func TestWithTraceAll(t *testing.T) {
 	assert.True(t, actual.traceAll)
 }
 
+func TestWithStaticMessages(t *testing.T) {
+	actual := &logger{}
+
+	WithStaticMessages()(actual)
+
+	assert.True(t, actual.staticMessages)
+}
+
 func TestWithErrorField(t *testing.T) {
 	actual := &logger{}
 	expected := "error"
@@ -51,6 +65,59 @@ func TestWithHandler(t *testing.T) {
 	assert.Equal(t, handler, actual.sloggerHandler)
 }
 
+func TestWithSilencedLogTypes(t *testing.T) {
+	actual := newLogger(nil)
+
+	WithSilencedLogTypes(SlowQueryLogType, SlowQueryLogType, CommitLogType, AsyncDropLogType, "unknown")(actual)
+
+	assert.Equal(t, []LogType{SlowQueryLogType, CommitLogType}, actual.silencedLogTypes)
+	require.Len(t, actual.errs, 2)
+	for _, err := range actual.errs {
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	}
+
+	WithSilencedLogTypes()(actual)
+
+	assert.Empty(t, actual.silencedLogTypes)
+}
+
+func TestWithBurstCapture(t *testing.T) {
+	actual := &logger{}
+
+	WithBurstCapture(time.Minute)(actual)
+	WithBurstCapture(0)(actual)
+
+	assert.Equal(t, time.Minute, actual.burstWindow)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithErrorEscalation(t *testing.T) {
+	actual := &logger{}
+
+	WithErrorEscalation(10, time.Minute)(actual)
+	WithErrorEscalation(0, time.Minute)(actual)
+	WithErrorEscalation(10, 0)(actual)
+
+	assert.Equal(t, 10, actual.escalationThreshold)
+	assert.Equal(t, time.Minute, actual.escalationWindow)
+	require.Len(t, actual.errs, 2)
+	for _, err := range actual.errs {
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	}
+}
+
+func TestWithMaintenanceSchedule(t *testing.T) {
+	actual := &logger{}
+
+	WithMaintenanceSchedule(func(time.Time) bool { return true })(actual)
+	WithMaintenanceSchedule(nil)(actual)
+
+	require.NotNil(t, actual.maintenanceSchedule)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
 func TestSetLogLevel(t *testing.T) {
 	tests := []struct {
 		lType LogType
@@ -74,6 +141,16 @@ func TestSetLogLevel(t *testing.T) {
 			assert.Equal(t, tt.level, actual.logLevel[tt.lType])
 		})
 	}
+
+	t.Run("Unknown LogType", func(t *testing.T) {
+		actual := &logger{logLevel: map[LogType]slog.Level{}}
+
+		SetLogLevel("unknown", slog.LevelInfo)(actual)
+
+		assert.Empty(t, actual.logLevel)
+		require.Len(t, actual.errs, 1)
+		assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+	})
 }
 
 func TestWithRecordNotFoundError(t *testing.T) {
@@ -91,8 +168,91 @@ func TestWithSlowThreshold(t *testing.T) {
 	expected := 1 * time.Second
 
 	WithSlowThreshold(expected)(actual)
+	WithSlowThreshold(-expected)(actual)
 
 	assert.Equal(t, expected, actual.slowThreshold)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSlowThresholdFor(t *testing.T) {
+	actual := &logger{}
+
+	WithSlowThresholdFor(TenantField, "acme", time.Second)(actual)
+	WithSlowThresholdFor("plan", "enterprise", time.Minute)(actual)
+	WithSlowThresholdFor(TenantField, "acme", 5*time.Second)(actual)
+	WithSlowThresholdFor(TenantField, "globex", -time.Second)(actual)
+
+	assert.Equal(t, []slowThresholdOverride{
+		{key: TenantField, value: "acme", threshold: 5 * time.Second},
+		{key: "plan", value: "enterprise", threshold: time.Minute},
+	}, actual.slowThresholds)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithQueryBytes(t *testing.T) {
+	actual := &logger{}
+
+	WithQueryBytes()(actual)
+
+	assert.True(t, actual.queryBytes)
+}
+
+func TestWithMaxQueryBytesWarn(t *testing.T) {
+	actual := &logger{}
+
+	WithMaxQueryBytesWarn(1 << 20)(actual)
+	WithMaxQueryBytesWarn(0)(actual)
+
+	assert.Equal(t, 1<<20, actual.maxQueryBytes)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithLargeResultThreshold(t *testing.T) {
+	actual := &logger{}
+
+	WithLargeResultThreshold(10000)(actual)
+	WithLargeResultThreshold(-1)(actual)
+
+	assert.Equal(t, int64(10000), actual.largeResultThreshold)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithTransactionWatchdog(t *testing.T) {
+	actual := &logger{}
+	expected := 1 * time.Minute
+
+	WithTransactionWatchdog(expected)(actual)
+	WithTransactionWatchdog(-expected)(actual)
+
+	assert.Equal(t, expected, actual.txWatchdogThreshold)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithAsync(t *testing.T) {
+	actual := &logger{}
+
+	WithAsync(42)(actual)
+	WithAsync(-1)(actual)
+
+	assert.Equal(t, 42, actual.asyncBufferSize)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithAsyncDropPolicy(t *testing.T) {
+	actual := &logger{}
+
+	WithAsyncDropPolicy(DropOldest)(actual)
+	WithAsyncDropPolicy(DropPolicy(42))(actual)
+
+	assert.Equal(t, DropOldest, actual.asyncDropPolicy)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
 }
 
 func TestWithSourceField(t *testing.T) {
@@ -104,6 +264,335 @@ func TestWithSourceField(t *testing.T) {
 	assert.Equal(t, expected, actual.sourceField)
 }
 
+func TestWithoutSourceField(t *testing.T) {
+	actual := &logger{sourceField: SourceField}
+
+	WithoutSourceField()(actual)
+
+	assert.Equal(t, "", actual.sourceField)
+}
+
+func TestWithClock(t *testing.T) {
+	actual := &logger{}
+	clock := fixedClock{now: time.Now()}
+
+	WithClock(clock)(actual)
+	assert.Equal(t, clock, actual.clock)
+	assert.Empty(t, actual.errs)
+
+	WithClock(nil)(actual)
+	assert.Equal(t, clock, actual.clock)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithPprofLabels(t *testing.T) {
+	actual := &logger{}
+
+	WithPprofLabels()(actual)
+
+	assert.True(t, actual.pprofLabels)
+}
+
+func TestWithName(t *testing.T) {
+	actual := &logger{}
+
+	WithName("orders-db")(actual)
+	WithName("")(actual)
+
+	assert.Equal(t, "orders-db", actual.name)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithResource(t *testing.T) {
+	actual := &logger{}
+
+	WithResource("orders", "v1.2.0", "")(actual)
+	WithResource("", "v1.3.0", "prod")(actual)
+
+	assert.Equal(t, []slog.Attr{
+		slog.String(ServiceNameField, "orders"),
+		slog.String(ServiceVersionField, "v1.3.0"),
+		slog.String(DeploymentEnvironmentField, "prod"),
+	}, actual.resource)
+}
+
+func TestWithResourceFromEnv(t *testing.T) {
+	t.Run("Resource attributes", func(t *testing.T) {
+		t.Setenv(envResourceAttributes, "service.name=orders, deployment.environment=prod,team=checkout%20squad")
+		t.Setenv(envServiceName, "")
+		actual := &logger{}
+
+		WithResourceFromEnv()(actual)
+
+		assert.Empty(t, actual.errs)
+		assert.Equal(t, []slog.Attr{
+			slog.String(ServiceNameField, "orders"),
+			slog.String(DeploymentEnvironmentField, "prod"),
+			slog.String("team", "checkout squad"),
+		}, actual.resource)
+	})
+
+	t.Run("Service name", func(t *testing.T) {
+		t.Setenv(envResourceAttributes, "service.name=orders")
+		t.Setenv(envServiceName, "orders-api")
+		actual := &logger{}
+
+		WithResourceFromEnv()(actual)
+
+		assert.Equal(t, []slog.Attr{slog.String(ServiceNameField, "orders-api")}, actual.resource)
+	})
+
+	t.Run("Invalid entries", func(t *testing.T) {
+		t.Setenv(envResourceAttributes, "service.name=orders,team,region=%zz")
+		t.Setenv(envServiceName, "")
+		actual := &logger{}
+
+		WithResourceFromEnv()(actual)
+
+		assert.Equal(t, []slog.Attr{slog.String(ServiceNameField, "orders")}, actual.resource)
+		require.Len(t, actual.errs, 2)
+		assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+	})
+}
+
+func TestWithKubernetesMetadata(t *testing.T) {
+	t.Setenv("POD_NAME", "orders-7d9f-x2k4")
+	t.Setenv("POD_NAMESPACE", "checkout")
+	t.Setenv("NODE_NAME", "")
+	actual := &logger{}
+
+	WithKubernetesMetadata()(actual)
+
+	assert.Empty(t, actual.errs)
+	assert.Equal(t, []slog.Attr{
+		slog.String(K8sPodNameField, "orders-7d9f-x2k4"),
+		slog.String(K8sNamespaceNameField, "checkout"),
+	}, actual.resource)
+}
+
+func TestWithContextAttrConflict(t *testing.T) {
+	actual := &logger{}
+	keys := []string{"team"}
+
+	WithContextAttrConflict(ConflictRenamed, keys...)(actual)
+	WithContextAttrConflict(ContextAttrConflict(42))(actual)
+	keys[0] = "region"
+
+	assert.Equal(t, ConflictRenamed, actual.attrConflict)
+	assert.Equal(t, []string{"team"}, actual.handlerKeys)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithHostInfo(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	actual := &logger{}
+
+	WithResource("orders", "", "")(actual)
+	WithHostInfo()(actual)
+	WithHostInfo()(actual)
+
+	assert.Equal(t, []slog.Attr{
+		slog.String(ServiceNameField, "orders"),
+		slog.String(HostNameField, hostname),
+		slog.Int(ProcessPIDField, os.Getpid()),
+	}, actual.resource)
+}
+
+func TestWithGoroutineID(t *testing.T) {
+	actual := &logger{}
+
+	WithGoroutineID()(actual)
+
+	assert.True(t, actual.goroutineID)
+}
+
+func TestWithTablePolicy(t *testing.T) {
+	actual := newLogger(nil)
+	policy := ModelPolicy{Operations: []string{"INSERT"}, Levels: map[LogType]slog.Level{DefaultLogType: slog.LevelWarn}}
+
+	WithTablePolicy("payments", policy)(actual)
+	WithTablePolicy("", ModelPolicy{})(actual)
+	WithTablePolicy("sessions", ModelPolicy{Levels: map[LogType]slog.Level{"unknown": slog.LevelWarn}})(actual)
+	policy.Operations[0] = "DELETE"
+	policy.Levels[DefaultLogType] = slog.LevelError
+
+	require.Len(t, actual.policies, 1)
+	assert.Equal(t, tablePolicy{
+		table:  "payments",
+		policy: ModelPolicy{Operations: []string{"INSERT"}, Levels: map[LogType]slog.Level{DefaultLogType: slog.LevelWarn}},
+	}, actual.policies[0])
+	require.Len(t, actual.errs, 2)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithModelPolicy(t *testing.T) {
+	actual := newLogger(nil)
+
+	WithModelPolicy(&Payment{}, ModelPolicy{Trace: true})(actual)
+	WithModelPolicy("payments", ModelPolicy{})(actual)
+
+	require.Len(t, actual.policies, 1)
+	assert.Equal(t, "payments", actual.policies[0].table)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithRouteStats(t *testing.T) {
+	actual := &logger{}
+
+	WithRouteStats("http.route")(actual)
+	WithRouteStats("")(actual)
+
+	require.NotNil(t, actual.routeStats)
+	assert.Equal(t, "http.route", actual.routeStats.attr)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithJobStats(t *testing.T) {
+	actual := &logger{}
+
+	WithJobStats("job")(actual)
+	WithJobStats("")(actual)
+
+	require.NotNil(t, actual.jobStats)
+	assert.Equal(t, "job", actual.jobStats.attr)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	actual := &logger{}
+
+	WithBuildInfo()(actual)
+	assert.Equal(t, buildInfoAllRecords, actual.buildInfo)
+
+	WithStartupBuildInfo()(actual)
+	assert.Equal(t, buildInfoStartup, actual.buildInfo)
+}
+
+func TestWithExplainOnSlow(t *testing.T) {
+	actual := &logger{}
+
+	WithExplainOnSlow(&gorm.DB{}, WithExplainInterval(time.Second), WithExplainRecord())(actual)
+
+	require.NotNil(t, actual.explainer)
+	assert.Equal(t, "EXPLAIN ", actual.explainer.prefix)
+	assert.Equal(t, time.Second, actual.explainer.interval)
+	assert.True(t, actual.explainer.record)
+}
+
+func TestWithBindParamsCount(t *testing.T) {
+	actual := &logger{}
+
+	WithBindParamsCount()(actual)
+
+	assert.True(t, actual.bindParamsCount)
+}
+
+func TestWithBatchMetrics(t *testing.T) {
+	actual := &logger{}
+
+	WithBatchMetrics()(actual)
+
+	assert.True(t, actual.batchMetrics)
+}
+
+func TestWithReturningRedaction(t *testing.T) {
+	actual := &logger{}
+
+	WithReturningRedaction(ReturningMasked)(actual)
+	WithReturningRedaction(ReturningRedaction(42))(actual)
+
+	assert.Equal(t, ReturningMasked, actual.returningRedaction)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithoutComments(t *testing.T) {
+	actual := &logger{}
+
+	WithoutComments()(actual)
+
+	assert.True(t, actual.withoutComments)
+}
+
+func TestWithTracer(t *testing.T) {
+	actual := &logger{}
+
+	WithTracer(TracerFunc(func(ctx context.Context, span QuerySpan) {}))(actual)
+	WithTracer(nil)(actual)
+
+	assert.NotNil(t, actual.tracer)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSpanContext(t *testing.T) {
+	actual := &logger{}
+
+	WithSpanContext(func(ctx context.Context) (string, string) { return "", "" })(actual)
+	WithSpanContext(nil)(actual)
+
+	assert.NotNil(t, actual.spanContext)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithoutSpanStatement(t *testing.T) {
+	actual := &logger{}
+
+	WithoutSpanStatement()(actual)
+
+	assert.True(t, actual.withoutSpanStatement)
+}
+
+func TestWithShardedTables(t *testing.T) {
+	actual := &logger{}
+
+	WithShardedTables("orders", "", "invoices")(actual)
+
+	assert.Equal(t, []string{"orders", "invoices"}, actual.shardedTables)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSoftDeleteFlag(t *testing.T) {
+	actual := &logger{}
+
+	WithSoftDeleteFlag()(actual)
+
+	assert.True(t, actual.softDeleteFlag)
+}
+
+func TestWithTables(t *testing.T) {
+	actual := &logger{}
+
+	WithTables()(actual)
+
+	assert.True(t, actual.tables)
+}
+
+func TestWithDecisionDebug(t *testing.T) {
+	actual := &logger{}
+
+	WithDecisionDebug()(actual)
+
+	assert.True(t, actual.decisionDebug)
+}
+
+func TestWithCallerFunction(t *testing.T) {
+	actual := &logger{}
+
+	WithCallerFunction()(actual)
+
+	assert.True(t, actual.callerFunction)
+}
+
 func TestWithContextValue(t *testing.T) {
 	actual := &logger{}
 	attrName := "attrName"
@@ -111,5 +600,438 @@ func TestWithContextValue(t *testing.T) {
 
 	WithContextValue(attrName, expected)(actual)
 
-	assert.Equal(t, map[string]any{attrName: expected}, actual.contextKeys)
+	assert.Equal(t, []contextAttr{{name: attrName, key: expected}}, actual.contextAttrs)
+
+	// Registering the same attribute name replaces the previous one
+	WithContextValue(attrName, "otherContextKey")(actual)
+
+	assert.Equal(t, []contextAttr{{name: attrName, key: "otherContextKey"}}, actual.contextAttrs)
+}
+
+func TestWithContextFunc(t *testing.T) {
+	actual := &logger{}
+
+	WithContextValue("attrName1", "contextKey")(actual)
+	WithContextFunc("attrName2", func(ctx context.Context) (slog.Value, bool) {
+		return slog.StringValue("value"), true
+	})(actual)
+
+	require.Len(t, actual.contextAttrs, 2)
+	assert.Equal(t, "attrName1", actual.contextAttrs[0].name)
+	assert.Equal(t, "attrName2", actual.contextAttrs[1].name)
+	assert.NotNil(t, actual.contextAttrs[1].fn)
+}
+
+func TestWithIgnoredOperations(t *testing.T) {
+	actual := &logger{}
+
+	WithIgnoredOperations("SELECT")(actual)
+
+	require.Len(t, actual.filters, 1)
+	assert.Equal(t, operationFilterCost, actual.filters[0].cost)
+}
+
+func TestWithIgnoredTables(t *testing.T) {
+	actual := &logger{}
+
+	WithIgnoredTables("users")(actual)
+
+	require.Len(t, actual.filters, 1)
+	assert.Equal(t, tableFilterCost, actual.filters[0].cost)
+}
+
+func TestWithIgnoredQueries(t *testing.T) {
+	actual := &logger{}
+
+	WithIgnoredQueries(regexp.MustCompile("SELECT 1"), nil)(actual)
+
+	require.Len(t, actual.filters, 1)
+	assert.Equal(t, regexpFilterCost, actual.filters[0].cost)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithQueryFilter(t *testing.T) {
+	actual := &logger{}
+
+	WithQueryFilter(nil)(actual)
+	WithQueryFilter(func(ctx context.Context, query QueryInfo) bool { return true })(actual)
+
+	require.Len(t, actual.filters, 1)
+	assert.Equal(t, funcFilterCost, actual.filters[0].cost)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSourceCacheSize(t *testing.T) {
+	actual := &logger{}
+
+	WithSourceCacheSize(16)(actual)
+
+	assert.Equal(t, 16, actual.sourceCacheSize)
+}
+
+func TestWithParameterizedQueries(t *testing.T) {
+	actual := &logger{}
+
+	WithParameterizedQueries()(actual)
+
+	assert.True(t, actual.parameterizedQueries)
+}
+
+func TestWithSamplingRate(t *testing.T) {
+	actual := &logger{}
+
+	WithSamplingRate(0.5)(actual)
+	WithSamplingRate(1.5)(actual)
+
+	assert.Equal(t, 0.5, actual.samplingRate)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithMinLevel(t *testing.T) {
+	actual := &logger{}
+
+	WithMinLevel(slog.LevelWarn)(actual)
+
+	assert.Equal(t, slog.LevelWarn, actual.minLevel)
+}
+
+func TestWithTenantLevels(t *testing.T) {
+	actual := &logger{}
+
+	WithTenantLevels(func(context.Context) (slog.Level, bool) { return slog.LevelDebug, true })(actual)
+	WithTenantLevels(nil)(actual)
+
+	assert.NotNil(t, actual.tenantLevel)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithTenant(t *testing.T) {
+	actual := &logger{}
+
+	WithTenant(func(context.Context) (string, bool) { return "acme", true })(actual)
+	WithTenant(nil)(actual)
+
+	assert.NotNil(t, actual.tenant)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSamplingRates(t *testing.T) {
+	actual := &logger{}
+	rates := map[LogType]float64{ErrorLogType: 1, DefaultLogType: 0.01}
+
+	WithSamplingRates(rates)(actual)
+	WithSamplingRates(map[LogType]float64{SlowQueryLogType: 2})(actual)
+	WithSamplingRates(map[LogType]float64{CommitLogType: 0.5})(actual)
+	rates[ErrorLogType] = 0
+
+	assert.Equal(t, map[LogType]float64{ErrorLogType: 1, DefaultLogType: 0.01}, actual.logTypeSamplingRates)
+	require.Len(t, actual.errs, 2)
+	for _, err := range actual.errs {
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	}
+}
+
+func TestWithVolumeBudget(t *testing.T) {
+	actual := &logger{}
+
+	WithVolumeBudget(1000)(actual)
+	WithVolumeBudget(0)(actual)
+
+	assert.Equal(t, 1000, actual.volumeBudget)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithTenantSamplingRates(t *testing.T) {
+	actual := &logger{}
+	rates := map[string]float64{"acme": 0.1}
+
+	WithTenantSamplingRates(rates)(actual)
+	WithTenantSamplingRates(map[string]float64{"globex": 2})(actual)
+	rates["acme"] = 1
+
+	assert.Equal(t, map[string]float64{"acme": 0.1}, actual.tenantSamplingRates)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithOtelSemconvFields(t *testing.T) {
+	actual := &logger{}
+
+	WithOtelSemconvFields()(actual)
+
+	assert.NotNil(t, actual.preset)
+}
+
+func TestWithECSFields(t *testing.T) {
+	actual := &logger{}
+
+	WithECSFields()(actual)
+
+	assert.NotNil(t, actual.preset)
+}
+
+func TestWithGoogleCloudFields(t *testing.T) {
+	actual := &logger{}
+
+	WithGoogleCloudFields()(actual)
+
+	assert.NotNil(t, actual.preset)
+}
+
+func TestWithDatadogFields(t *testing.T) {
+	actual := &logger{}
+
+	WithDatadogFields(nil)(actual)
+
+	assert.NotNil(t, actual.preset)
+}
+
+func TestWithPreset(t *testing.T) {
+	actual := &logger{}
+
+	WithPreset("ecs")(actual)
+	WithPreset("unknown")(actual)
+
+	assert.NotNil(t, actual.preset)
+	assert.Equal(t, "ecs", actual.presetName)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithFieldPreset(t *testing.T) {
+	actual := &logger{presetName: "ecs"}
+
+	WithFieldPreset(FieldPresetFunc(ecsPreset))(actual)
+
+	assert.NotNil(t, actual.preset)
+	assert.Equal(t, "", actual.presetName)
+}
+
+func TestWithKeyCase(t *testing.T) {
+	actual := &logger{}
+
+	WithKeyCase(KebabCase)(actual)
+	WithKeyCase(KeyCase(42))(actual)
+
+	assert.Equal(t, KebabCase, actual.keyCase)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithDebugFullQueries(t *testing.T) {
+	actual := &logger{}
+
+	WithDebugFullQueries()(actual)
+
+	assert.True(t, actual.debugFullQueries)
+}
+
+func TestWithMessage(t *testing.T) {
+	actual := &logger{logLevel: map[LogType]slog.Level{
+		SlowQueryLogType: slog.LevelWarn,
+		AsyncDropLogType: slog.LevelWarn,
+		GormWarnLogType:  slog.LevelWarn,
+	}}
+
+	WithMessage(SlowQueryLogType, "slow query on {table}")(actual)
+	WithMessage(SlowQueryLogType, "slow query on {unknown}")(actual)
+	WithMessage(AsyncDropLogType, "records dropped")(actual)
+	WithMessage(GormWarnLogType, "gorm warning")(actual)
+	WithMessage("unknown", "unknown")(actual)
+
+	require.Len(t, actual.messages, 1)
+	assert.Equal(t, "slow query on {table}", actual.messages[SlowQueryLogType].template)
+	require.Len(t, actual.errs, 4)
+	for _, err := range actual.errs {
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	}
+}
+
+func TestWithMessageFunc(t *testing.T) {
+	actual := &logger{logLevel: map[LogType]slog.Level{DefaultLogType: slog.LevelInfo}}
+
+	WithMessageFunc(DefaultLogType, func(ctx context.Context, info MessageInfo) string { return "query" })(actual)
+	WithMessageFunc(DefaultLogType, nil)(actual)
+
+	require.Len(t, actual.messages, 1)
+	assert.NotNil(t, actual.messages[DefaultLogType].format)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithMetadataMode(t *testing.T) {
+	actual := &logger{}
+
+	WithMetadataMode(MetadataAttrsOnly)(actual)
+	assert.True(t, actual.staticMessages)
+
+	WithMetadataMode(MetadataMessageOnly)(actual)
+	WithMetadataMode(MetadataMode(42))(actual)
+
+	assert.Equal(t, MetadataMessageOnly, actual.metadataMode)
+	assert.False(t, actual.staticMessages)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithScrubber(t *testing.T) {
+	actual := &logger{}
+
+	WithScrubber(ScrubberFunc(func(sql string, attrs []slog.Attr) (string, []slog.Attr) { return sql, attrs }))(actual)
+	WithScrubber(nil)(actual)
+
+	assert.NotNil(t, actual.scrubber)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithNoValues(t *testing.T) {
+	actual := &logger{}
+
+	WithNoValues("request_id")(actual)
+
+	assert.True(t, actual.noValues)
+	assert.Equal(t, []string{"request_id"}, actual.allowedContextAttrs)
+}
+
+func TestWithMaskedColumns(t *testing.T) {
+	actual := &logger{}
+
+	WithMaskedColumns("users.Email", "card_number", "public.users.phone", "users.")(actual)
+
+	assert.Equal(t, []maskedColumn{
+		{name: "users.Email", table: "users", column: "email"},
+		{name: "card_number", column: "card_number"},
+		{name: "public.users.phone", table: "users", column: "phone"},
+	}, actual.maskedColumns)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithRedactionVerification(t *testing.T) {
+	actual := &logger{}
+
+	var reported bool
+	WithRedactionVerification(func(context.Context, RedactionViolation) { reported = true })(actual)
+	require.NotNil(t, actual.redactionCheck)
+	actual.redactionCheck(context.Background(), RedactionViolation{})
+	assert.True(t, reported)
+
+	WithRedactionVerification(nil)(actual)
+	require.NotNil(t, actual.redactionCheck)
+	assert.Panics(t, func() { actual.redactionCheck(context.Background(), RedactionViolation{}) })
+}
+
+func TestWithAudit(t *testing.T) {
+	handler := NewDummyHandler()
+	actual := &logger{}
+
+	WithAudit(handler, nil)(actual)
+	assert.Equal(t, handler, actual.auditHandler)
+	assert.Empty(t, actual.errs)
+
+	WithAudit(nil, nil)(actual)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithFallbackHandler(t *testing.T) {
+	actual := &logger{}
+	handler := NewDummyHandler()
+
+	WithFallbackHandler(handler)(actual)
+	assert.Equal(t, handler, actual.fallbackHandler)
+	assert.Empty(t, actual.errs)
+
+	WithFallbackHandler(nil)(actual)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithStatsSummary(t *testing.T) {
+	actual := &logger{}
+
+	WithStatsSummary(time.Minute)(actual)
+	require.NotNil(t, actual.statsSummary)
+	assert.Equal(t, time.Minute, actual.statsSummary.interval)
+	assert.Empty(t, actual.errs)
+
+	WithStatsSummary(0)(actual)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSlowLog(t *testing.T) {
+	actual := &logger{}
+
+	WithSlowLog(io.Discard)(actual)
+	require.NotNil(t, actual.slowLog)
+	assert.Equal(t, io.Discard, actual.slowLog.w)
+	assert.Empty(t, actual.errs)
+
+	WithSlowLog(nil)(actual)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithQueryLogFile(t *testing.T) {
+	actual := &logger{}
+	file := &QueryLogFile{}
+
+	WithQueryLogFile(file)(actual)
+	assert.Equal(t, file, actual.queryLogFile)
+	assert.Empty(t, actual.errs)
+
+	WithoutQueryLogFile()(actual)
+	assert.Nil(t, actual.queryLogFile)
+
+	WithQueryLogFile(nil)(actual)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSlowQueryLogFile(t *testing.T) {
+	actual := &logger{}
+	file := &QueryLogFile{}
+
+	WithSlowQueryLogFile(file)(actual)
+	assert.Equal(t, file, actual.slowQueryLogFile)
+	assert.Empty(t, actual.errs)
+
+	WithSlowQueryLogFile(nil)(actual)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSink(t *testing.T) {
+	actual := &logger{}
+	sink := SinkFunc(func(context.Context, QueryEvent) {})
+
+	WithSink(sink, nil)(actual)
+
+	assert.Len(t, actual.sinks, 1)
+	require.Len(t, actual.errs, 1)
+	assert.ErrorIs(t, actual.errs[0], ErrInvalidOption)
+}
+
+func TestWithSecurityDetection(t *testing.T) {
+	actual := &logger{}
+
+	WithSecurityDetection()(actual)
+
+	assert.True(t, actual.securityDetection)
+}
+
+func TestWithDangerousWriteDetection(t *testing.T) {
+	actual := &logger{}
+
+	WithDangerousWriteDetection()(actual)
+
+	assert.True(t, actual.dangerousWriteDetection)
 }
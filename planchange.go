@@ -0,0 +1,93 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// maxPlanFingerprints is the number of fingerprints whose plans are cached by WithPlanChangeDetection, the
+// plans of the queries with other fingerprints not being compared
+const maxPlanFingerprints = 1024
+
+// planCache caches the last plan captured per fingerprint, see WithPlanChangeDetection
+type planCache struct {
+	mu    sync.Mutex
+	plans map[string]cachedPlan
+}
+
+// cachedPlan is the last plan captured for a fingerprint
+type cachedPlan struct {
+	plan  string
+	shape string
+}
+
+// planChange is a change of the plan shape of a fingerprint
+type planChange struct {
+	fingerprint string
+	previous    string
+}
+
+// WithPlanChangeDetection caches the plans captured per fingerprint, and logs a record at the
+// PlanChangeLogType level when the shape of the plan of a fingerprint changes (e.g. from an index scan
+// to a sequential scan), the shapes being compared without their costs, row estimates and values.
+// Up to 1024 fingerprints are cached, the plans of the queries with other fingerprints not being compared.
+func WithPlanChangeDetection() ExplainOption {
+	return func(e *explainer) {
+		e.plans = &planCache{plans: make(map[string]cachedPlan)}
+	}
+}
+
+// planChanged caches the plan of the SQL query, and returns the previous plan of its fingerprint if its
+// shape changed, nil otherwise
+func (c *planCache) planChanged(sql, plan string) *planChange {
+	key := fingerprint(sql)
+	shape := planShape(plan)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.plans[key]
+	if !ok && len(c.plans) >= maxPlanFingerprints {
+		return nil
+	}
+	c.plans[key] = cachedPlan{plan: plan, shape: shape}
+	if !ok || cached.shape == shape {
+		return nil
+	}
+	return &planChange{fingerprint: key, previous: cached.plan}
+}
+
+// planShape returns the shape of the plan: its lines without the numbers and the strings (the costs, the
+// row estimates, the values...), see fingerprint, their indentation being kept as it describes the tree
+func planShape(plan string) string {
+	lines := strings.Split(plan, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		lines[i] = line[:len(line)-len(trimmed)] + fingerprint(trimmed)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// logPlanChange logs the change of the plan of the query, at the PlanChangeLogType level
+func (l logger) logPlanChange(ctx context.Context, query *lazyQuery, source *sourceValuer, plan string, change *planChange) {
+	level := l.logLevel[PlanChangeLogType]
+	if l.silenced(PlanChangeLogType) || !l.enabled(ctx, level) {
+		return
+	}
+
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+
+	*attributes = append(*attributes,
+		slog.Any(QueryField, sqlValuer{query}),
+		slog.String(FingerprintField, change.fingerprint),
+		slog.String(PlanField, plan),
+		slog.String(PreviousPlanField, change.previous),
+	)
+	*attributes = l.appendSourceAttribute(*attributes, source)
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(query, *attributes)
+
+	l.logAttrs(ctx, level, PlanChangeMessage, *attributes...)
+}
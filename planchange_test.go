@@ -0,0 +1,95 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_PlanChangeDetection(t *testing.T) {
+	db := openTestDB(t, New(WithHandler(NewDummyHandler())).(*logger))
+	traceSlow := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Plan changed", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithExplainOnSlow(db, WithExplainInterval(0), WithPlanChangeDetection()),
+		})
+		previous := "Index Scan using users_pkey on users\n  Index Cond: (id = 3)"
+		gormLogger.explainer.plans.planChanged("SELECT * FROM users WHERE id = 3", previous)
+
+		traceSlow(gormLogger, "SELECT * FROM users WHERE id = 1")
+
+		require.Equal(t, 2, receiver.Len())
+		change := resolveRecord(receiver.Records[1])
+		assert.Equal(t, PlanChangeMessage, change.Message)
+		assert.Equal(t, slog.LevelWarn, change.Level)
+		assertHasAttr(t, &change, slog.String(QueryField, "SELECT * FROM users WHERE id = 1"))
+		assertHasAttr(t, &change, slog.String(FingerprintField, "SELECT * FROM users WHERE id = ?"))
+		assertHasAttr(t, &change, slog.String(PlanField, "Seq Scan on users\n  Filter: (id = 1)"))
+		assertHasAttr(t, &change, slog.String(PreviousPlanField, previous))
+	})
+
+	t.Run("Same shape", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithExplainOnSlow(db, WithExplainInterval(0), WithPlanChangeDetection()),
+		})
+
+		traceSlow(gormLogger, "SELECT * FROM users WHERE id = 1")
+		traceSlow(gormLogger, "SELECT * FROM users WHERE id = 2")
+
+		require.Equal(t, 2, receiver.Len())
+		for _, r := range receiver.Records {
+			assert.NotEqual(t, PlanChangeMessage, r.Message)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithExplainOnSlow(db, WithExplainInterval(0)),
+		})
+
+		traceSlow(gormLogger, "SELECT * FROM users WHERE id = 1")
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Nil(t, gormLogger.explainer.plans)
+	})
+}
+
+func Test_planCache_planChanged(t *testing.T) {
+	cache := &planCache{plans: make(map[string]cachedPlan)}
+
+	assert.Nil(t, cache.planChanged("SELECT * FROM users WHERE id = 1",
+		"Index Scan using users_pkey on users  (cost=0.15..8.17 rows=1 width=36)"))
+	assert.Nil(t, cache.planChanged("SELECT * FROM users WHERE id = 2",
+		"Index Scan using users_pkey on users  (cost=0.15..9.02 rows=3 width=36)"))
+
+	change := cache.planChanged("SELECT * FROM users WHERE id = 3", "Seq Scan on users  (cost=0.00..1.05 rows=5 width=36)")
+	require.NotNil(t, change)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", change.fingerprint)
+	assert.Equal(t, "Index Scan using users_pkey on users  (cost=0.15..9.02 rows=3 width=36)", change.previous)
+
+	t.Run("Limited fingerprints", func(t *testing.T) {
+		cache := &planCache{plans: make(map[string]cachedPlan)}
+		for i := 0; i < maxPlanFingerprints; i++ {
+			cache.plans[string(rune(i))] = cachedPlan{}
+		}
+
+		cache.planChanged("SELECT * FROM users", "Seq Scan on users")
+
+		assert.Len(t, cache.plans, maxPlanFingerprints)
+	})
+}
+
+func Test_planShape(t *testing.T) {
+	assert.Equal(t, "Hash Join (cost=? rows=? width=?)\n  Hash Cond: (a.id = b.id)\n  -> Seq Scan on a",
+		planShape("Hash Join  (cost=1.09..2.20 rows=5 width=72)\n  Hash Cond: (a.id = b.id)\n  ->  Seq Scan on a"))
+}
@@ -0,0 +1,69 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// ModelPolicy defines how the SQL queries on the table of a model are logged, see WithModelPolicy
+type ModelPolicy struct {
+	// Operations restricts the policy to the queries with the given operations (e.g. "INSERT", "UPDATE",
+	// "DELETE"), all the queries by default
+	Operations []string
+	// Levels overrides the slog.Level of the given log types, like SetLogLevel
+	Levels map[LogType]slog.Level
+	// Trace logs all the SQL queries, like WithTraceAll
+	Trace bool
+	// Suppress never logs the SQL queries, except the errors and the dangerous writes, like the filters
+	Suppress bool
+}
+
+// tablePolicy is the policy of the queries on a table, see WithTablePolicy
+type tablePolicy struct {
+	table  string
+	policy ModelPolicy
+}
+
+// matches reports whether the policy applies to the SQL query
+func (p *tablePolicy) matches(q *lazyQuery) bool {
+	if !strings.EqualFold(p.table, q.Table()) {
+		return false
+	}
+	return len(p.policy.Operations) == 0 || slices.ContainsFunc(p.policy.Operations, func(operation string) bool {
+		return strings.EqualFold(operation, q.Operation())
+	})
+}
+
+// policyOf returns the policy of the first table policy matching the SQL query, nil if none matches
+func (l logger) policyOf(q *lazyQuery) *ModelPolicy {
+	for i := range l.policies {
+		if l.policies[i].matches(q) {
+			return &l.policies[i].policy
+		}
+	}
+	return nil
+}
+
+// level returns the level of the log type overridden by the policy, false if the policy is nil or does
+// not override it
+func (p *ModelPolicy) level(logType LogType) (slog.Level, bool) {
+	if p == nil {
+		return 0, false
+	}
+	level, ok := p.Levels[logType]
+	return level, ok
+}
+
+// modelTable returns the table of the model with the default naming strategy of gorm, following its
+// TableName method if any
+func modelTable(model any) (string, error) {
+	s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		return "", err
+	}
+	return s.Table, nil
+}
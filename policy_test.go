@@ -0,0 +1,111 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Payment struct {
+	ID     uint
+	Amount int64
+}
+
+type AuditEvent struct {
+	ID uint
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_log"
+}
+
+func Test_logger_ModelPolicy(t *testing.T) {
+	options := []Option{
+		WithModelPolicy(&Payment{}, ModelPolicy{Operations: []string{"insert", "UPDATE"}, Trace: true}),
+		WithModelPolicy(AuditEvent{}, ModelPolicy{Operations: []string{"SELECT"}, Suppress: true}),
+		WithTablePolicy("sessions", ModelPolicy{Levels: map[LogType]slog.Level{SlowQueryLogType: slog.LevelInfo}}),
+		WithSlowThreshold(time.Millisecond),
+	}
+	query := func(sql string) func() (string, int64) {
+		return func() (string, int64) { return sql, 1 }
+	}
+
+	t.Run("Traced writes", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(context.Background(), time.Now(), query("INSERT INTO payments (amount) VALUES (10)"), nil)
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, slog.LevelInfo, receiver.Record.Level)
+
+		receiver.Record = nil
+		gormLogger.Trace(context.Background(), time.Now(), query("SELECT * FROM payments"), nil)
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Suppressed reads", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(append(options, WithDecisionDebug(), WithMinLevel(slog.LevelDebug)))
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), query("SELECT * FROM audit_log"), nil)
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(DecisionField, decisionFiltered))
+		assertHasAttr(t, receiver.Record, slog.String(FilterField, "model policy audit_log"))
+		assert.Equal(t, uint64(1), gormLogger.Stats().Filtered)
+	})
+
+	t.Run("Errors of the suppressed reads", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(context.Background(), time.Now(), query("SELECT * FROM audit_log"), errors.New("timeout"))
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, slog.LevelError, receiver.Record.Level)
+	})
+
+	t.Run("Writes not suppressed", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), query("INSERT INTO audit_log (id) VALUES (1)"), nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+	})
+
+	t.Run("Level override", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), query("SELECT * FROM sessions"), nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, slog.LevelInfo, receiver.Record.Level)
+	})
+
+	t.Run("First policy matching", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTablePolicy("payments", ModelPolicy{Trace: true}),
+			WithTablePolicy("PAYMENTS", ModelPolicy{Suppress: true}),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), query("SELECT * FROM payments"), nil)
+
+		assert.NotNil(t, receiver.Record)
+	})
+}
+
+func Test_modelTable(t *testing.T) {
+	table, err := modelTable(&Payment{})
+	require.NoError(t, err)
+	assert.Equal(t, "payments", table)
+
+	table, err = modelTable(AuditEvent{})
+	require.NoError(t, err)
+	assert.Equal(t, "audit_log", table)
+
+	_, err = modelTable(42)
+	assert.Error(t, err)
+}
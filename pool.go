@@ -0,0 +1,34 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// maxPooledAttrs is the capacity above which an attribute slice is not pooled,
+// so that a single large record does not retain memory forever.
+const maxPooledAttrs = 64
+
+// attrsPool pools the attribute slices built for each record
+var attrsPool = sync.Pool{
+	New: func() any {
+		attrs := make([]slog.Attr, 0, 16)
+		return &attrs
+	},
+}
+
+// getAttrs returns an empty attribute slice from the pool
+func getAttrs() *[]slog.Attr {
+	return attrsPool.Get().(*[]slog.Attr)
+}
+
+// putAttrs returns the attribute slice to the pool. The attributes are copied
+// by slog.Record.AddAttrs, so the slice can be reused once the record is built.
+func putAttrs(attrs *[]slog.Attr) {
+	if cap(*attrs) > maxPooledAttrs {
+		return
+	}
+	clear(*attrs)
+	*attrs = (*attrs)[:0]
+	attrsPool.Put(attrs)
+}
@@ -0,0 +1,30 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_putAttrs(t *testing.T) {
+	t.Run("Reset", func(t *testing.T) {
+		attrs := getAttrs()
+		*attrs = append(*attrs, slog.String("key", "value"))
+		backing := (*attrs)[:1]
+
+		putAttrs(attrs)
+
+		assert.Empty(t, *attrs)
+		assert.Equal(t, slog.Attr{}, backing[0])
+	})
+
+	t.Run("Too large to be pooled", func(t *testing.T) {
+		large := make([]slog.Attr, 1, maxPooledAttrs+1)
+		large[0] = slog.String("key", "value")
+
+		putAttrs(&large)
+
+		assert.Len(t, large, 1)
+	})
+}
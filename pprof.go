@@ -0,0 +1,79 @@
+package slogGorm
+
+import (
+	"context"
+	"database/sql"
+	"runtime/pprof"
+)
+
+// The pprof labels of the goroutines executing the SQL queries, see WithPprofLabels
+const (
+	FingerprintLabel = "sql_fingerprint"
+	TableLabel       = "sql_table"
+)
+
+// profile runs fn with the pprof labels of the SQL query if enabled, so that the CPU profiles show which
+// queries the goroutines were serving
+func (l logger) profile(ctx context.Context, query string, fn func(ctx context.Context)) {
+	if !l.snapshot().pprofLabels {
+		fn(ctx)
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	labels := []string{FingerprintLabel, fingerprint(query)}
+	if table := parseTable(query); table != "" {
+		labels = append(labels, TableLabel, table)
+	}
+	pprof.Do(ctx, pprof.Labels(labels...), fn)
+}
+
+// ExecContext implements gorm.ConnPool, with the pprof labels of the query
+func (p *txTrackingPool) ExecContext(ctx context.Context, query string, args ...any) (result sql.Result, err error) {
+	p.logger.profile(ctx, query, func(ctx context.Context) {
+		result, err = p.ConnPool.ExecContext(ctx, query, args...)
+	})
+	return result, err
+}
+
+// QueryContext implements gorm.ConnPool, with the pprof labels of the query
+func (p *txTrackingPool) QueryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, err error) {
+	p.logger.profile(ctx, query, func(ctx context.Context) {
+		rows, err = p.ConnPool.QueryContext(ctx, query, args...)
+	})
+	return rows, err
+}
+
+// QueryRowContext implements gorm.ConnPool, with the pprof labels of the query
+func (p *txTrackingPool) QueryRowContext(ctx context.Context, query string, args ...any) (row *sql.Row) {
+	p.logger.profile(ctx, query, func(ctx context.Context) {
+		row = p.ConnPool.QueryRowContext(ctx, query, args...)
+	})
+	return row
+}
+
+// ExecContext implements gorm.ConnPool, with the pprof labels of the query
+func (t *trackedTx) ExecContext(ctx context.Context, query string, args ...any) (result sql.Result, err error) {
+	t.pool.logger.profile(ctx, query, func(ctx context.Context) {
+		result, err = t.ConnPool.ExecContext(ctx, query, args...)
+	})
+	return result, err
+}
+
+// QueryContext implements gorm.ConnPool, with the pprof labels of the query
+func (t *trackedTx) QueryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, err error) {
+	t.pool.logger.profile(ctx, query, func(ctx context.Context) {
+		rows, err = t.ConnPool.QueryContext(ctx, query, args...)
+	})
+	return rows, err
+}
+
+// QueryRowContext implements gorm.ConnPool, with the pprof labels of the query
+func (t *trackedTx) QueryRowContext(ctx context.Context, query string, args ...any) (row *sql.Row) {
+	t.pool.logger.profile(ctx, query, func(ctx context.Context) {
+		row = t.ConnPool.QueryRowContext(ctx, query, args...)
+	})
+	return row
+}
@@ -0,0 +1,83 @@
+package slogGorm
+
+import (
+	"context"
+	"database/sql"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// labelPool records the pprof labels of the context of the last query executed
+type labelPool struct {
+	gorm.ConnPool
+	labels map[string]string
+}
+
+func (p *labelPool) record(ctx context.Context) {
+	p.labels = map[string]string{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		p.labels[key] = value
+		return true
+	})
+}
+
+func (p *labelPool) ExecContext(ctx context.Context, _ string, _ ...any) (sql.Result, error) {
+	p.record(ctx)
+	return nil, nil
+}
+
+func (p *labelPool) QueryContext(ctx context.Context, _ string, _ ...any) (*sql.Rows, error) {
+	p.record(ctx)
+	return nil, nil
+}
+
+func (p *labelPool) QueryRowContext(ctx context.Context, _ string, _ ...any) *sql.Row {
+	p.record(ctx)
+	return nil
+}
+
+func Test_logger_PprofLabels(t *testing.T) {
+	t.Run("Enabled", func(t *testing.T) {
+		conn := &labelPool{}
+		pool := &txTrackingPool{ConnPool: conn, logger: *New(WithPprofLabels()).(*logger)}
+		expected := map[string]string{FingerprintLabel: "SELECT * FROM users WHERE id = ?", TableLabel: "users"}
+
+		_, err := pool.QueryContext(context.Background(), "SELECT * FROM users WHERE id = 1")
+		require.NoError(t, err)
+		assert.Equal(t, expected, conn.labels)
+
+		pool.QueryRowContext(context.Background(), "SELECT * FROM users WHERE id = 2")
+		assert.Equal(t, expected, conn.labels)
+
+		tx := &trackedTx{ConnPool: conn, pool: pool}
+		_, err = tx.ExecContext(context.Background(), "UPDATE users SET name = 'john' WHERE id = 1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{FingerprintLabel: "UPDATE users SET name = ? WHERE id = ?", TableLabel: "users"}, conn.labels)
+
+		// The labels are removed once executed
+		count := 0
+		pprof.ForLabels(context.Background(), func(string, string) bool { count++; return true })
+		assert.Zero(t, count)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		conn := &labelPool{}
+		pool := &txTrackingPool{ConnPool: conn, logger: *New().(*logger)}
+
+		_, err := pool.ExecContext(context.Background(), "DELETE FROM users")
+		require.NoError(t, err)
+		assert.Empty(t, conn.labels)
+	})
+
+	t.Run("Plugin mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithPprofLabels()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("SELECT 1").Error)
+		assert.NotNil(t, receiver.Record)
+	})
+}
@@ -0,0 +1,286 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// dialectContextKey is the context key under which the name of the dialector of a query is stored
+type dialectContextKey struct{}
+
+// FieldPreset maps the attributes of the records to a logging schema (e.g. the semantic conventions of
+// OpenTelemetry), see WithFieldPreset and RegisterPreset.
+type FieldPreset interface {
+	// MapAttrs appends the attributes of the record, mapped to the schema, to dst and returns it.
+	// The values of the attributes (e.g. the SQL query) must not be resolved, so that they are
+	// only computed when the handler formats the record.
+	MapAttrs(ctx context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr
+}
+
+// FieldPresetFunc is a function implementing FieldPreset
+type FieldPresetFunc func(ctx context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr
+
+// MapAttrs implements FieldPreset
+func (f FieldPresetFunc) MapAttrs(ctx context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr {
+	return f(ctx, record, attrs, dst)
+}
+
+// PresetRecord describes the record whose attributes are mapped by a FieldPreset
+type PresetRecord struct {
+	Type  LogType
+	Level slog.Level
+	// DBSystem is the name of the gorm dialector (e.g. "postgres"), only known in plugin mode
+	DBSystem string
+
+	// query is the traced query, nil for the records of the transactions
+	query *lazyQuery
+}
+
+// HasQuery reports whether the record describes a SQL query, unlike the records of the transactions
+func (r PresetRecord) HasQuery() bool {
+	return r.query != nil
+}
+
+// Operation returns the lazy value of the operation of the SQL query (e.g. SELECT), if any
+func (r PresetRecord) Operation() slog.Value {
+	if r.query == nil {
+		return slog.StringValue("")
+	}
+	return slog.AnyValue(operationValuer{r.query})
+}
+
+// Table returns the lazy value of the main table of the SQL query, if any
+func (r PresetRecord) Table() slog.Value {
+	if r.query == nil {
+		return slog.StringValue("")
+	}
+	return slog.AnyValue(tableValuer{r.query})
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]FieldPreset{
+		"otel":         FieldPresetFunc(otelSemconvPreset),
+		"ecs":          FieldPresetFunc(ecsPreset),
+		"google_cloud": FieldPresetFunc(googleCloudPreset),
+		"datadog":      newDatadogPreset(nil),
+	}
+)
+
+// RegisterPreset registers a FieldPreset under the given name, to share schema mappings used
+// with WithPreset or in the configuration files. It replaces the preset registered under the
+// same name, including the built-in presets: "otel", "ecs", "google_cloud" and "datadog".
+func RegisterPreset(name string, preset FieldPreset) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	presets[name] = preset
+}
+
+// lookupPreset returns the FieldPreset registered under the given name
+func lookupPreset(name string) (FieldPreset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	preset, ok := presets[name]
+	return preset, ok
+}
+
+// applyPreset maps the attributes of the record with the preset, if any
+func (l logger) applyPreset(ctx context.Context, logType LogType, level slog.Level, query *lazyQuery, attrs []slog.Attr) []slog.Attr {
+	if l.preset == nil {
+		return attrs
+	}
+
+	dbSystem, _ := ctx.Value(dialectContextKey{}).(string)
+	record := PresetRecord{Type: logType, Level: level, DBSystem: dbSystem, query: query}
+	return l.preset.MapAttrs(ctx, record, attrs, make([]slog.Attr, 0, len(attrs)+4))
+}
+
+// detectDialect returns a callback storing the name of the dialector into the statement
+// context, when the preset of the logger needs it.
+func (l logger) detectDialect() func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if l.snapshot().preset == nil || db.Dialector == nil {
+			return
+		}
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		db.Statement.Context = context.WithValue(ctx, dialectContextKey{}, db.Dialector.Name())
+	}
+}
+
+// otelDBSystems maps the names of the gorm dialectors to the db.system values of OpenTelemetry
+var otelDBSystems = map[string]string{
+	"postgres":  "postgresql",
+	"sqlserver": "mssql",
+}
+
+// otelSemconvPreset maps the attributes to the semantic conventions of OpenTelemetry:
+// db.statement, db.operation.name, db.collection.name and db.system.
+func otelSemconvPreset(_ context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr {
+	for _, attr := range attrs {
+		if attr.Key == QueryField {
+			attr.Key = "db.statement"
+		}
+		dst = append(dst, attr)
+	}
+
+	if record.query != nil {
+		dst = append(dst,
+			slog.Any("db.operation.name", operationValuer{record.query}),
+			slog.Any("db.collection.name", tableValuer{record.query}),
+		)
+	}
+	if record.DBSystem != "" {
+		system, ok := otelDBSystems[record.DBSystem]
+		if !ok {
+			system = record.DBSystem
+		}
+		dst = append(dst, slog.String("db.system", system))
+	}
+	return dst
+}
+
+// ecsPreset maps the attributes to the Elastic Common Schema: the SQL query is logged as db.statement,
+// the duration as event.duration (in nanoseconds), the error as error.message and error.type, and the
+// source as log.origin.file.name and log.origin.file.line.
+func ecsPreset(_ context.Context, _ PresetRecord, attrs, dst []slog.Attr) []slog.Attr {
+	for _, attr := range attrs {
+		switch value := attr.Value.Any().(type) {
+		case error:
+			dst = append(dst,
+				slog.String("error.message", value.Error()),
+				slog.String("error.type", errorType(value)),
+			)
+			continue
+		case *sourceValuer:
+			dst = append(dst,
+				slog.Any("log.origin.file.name", fileValuer{value}),
+				slog.Any("log.origin.file.line", lineValuer{value}),
+			)
+			continue
+		}
+
+		switch {
+		case attr.Key == QueryField:
+			attr.Key = "db.statement"
+		case attr.Key == DurationField && attr.Value.Kind() == slog.KindDuration:
+			attr = slog.Int64("event.duration", attr.Value.Duration().Nanoseconds())
+		}
+		dst = append(dst, attr)
+	}
+	return dst
+}
+
+// googleCloudSourceLocationKey is the key of the source location in the structured logs of Cloud Logging
+const googleCloudSourceLocationKey = "logging.googleapis.com/sourceLocation"
+
+// googleCloudPreset maps the attributes to the structured logs of Google Cloud Logging: the level
+// is logged as severity, the source as logging.googleapis.com/sourceLocation and the duration
+// in seconds like "1.5s".
+func googleCloudPreset(_ context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr {
+	dst = append(dst, slog.String("severity", googleCloudSeverity(record.Level)))
+	for _, attr := range attrs {
+		if source, ok := attr.Value.Any().(*sourceValuer); ok {
+			dst = append(dst, slog.Any(googleCloudSourceLocationKey, sourceLocationValuer{source}))
+			continue
+		}
+		if attr.Value.Kind() == slog.KindDuration {
+			attr.Value = slog.StringValue(strconv.FormatFloat(attr.Value.Duration().Seconds(), 'f', -1, 64) + "s")
+		}
+		dst = append(dst, attr)
+	}
+	return dst
+}
+
+// googleCloudSeverity returns the severity of Cloud Logging of the given level
+func googleCloudSeverity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// sourceLocationValuer is a slog.LogValuer resolving the source as a group of its file and line number
+type sourceLocationValuer struct{ *sourceValuer }
+
+// LogValue implements slog.LogValuer
+func (v sourceLocationValuer) LogValue() slog.Value {
+	file, line := v.fileLine()
+	return slog.GroupValue(
+		slog.String("file", file),
+		slog.String("line", strconv.Itoa(line)),
+	)
+}
+
+// newDatadogPreset returns a preset mapping the attributes to the standard attributes of Datadog:
+// dd.trace_id and dd.span_id converted from the hexadecimal identifiers of the OpenTelemetry span
+// returned by spanContext (if not nil), the duration in nanoseconds, the SQL query as db.statement
+// with its operation as db.operation, and resource.name as the operation and the main table.
+func newDatadogPreset(spanContext func(ctx context.Context) (traceID, spanID string)) FieldPreset {
+	return FieldPresetFunc(func(ctx context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr {
+		if spanContext != nil {
+			traceID, spanID := spanContext(ctx)
+			if id, ok := datadogID(traceID); ok {
+				dst = append(dst, slog.String("dd.trace_id", id))
+			}
+			if id, ok := datadogID(spanID); ok {
+				dst = append(dst, slog.String("dd.span_id", id))
+			}
+		}
+
+		for _, attr := range attrs {
+			switch {
+			case attr.Key == QueryField:
+				attr.Key = "db.statement"
+			case attr.Key == DurationField && attr.Value.Kind() == slog.KindDuration:
+				attr = slog.Int64(DurationField, attr.Value.Duration().Nanoseconds())
+			}
+			dst = append(dst, attr)
+		}
+
+		if record.query != nil {
+			dst = append(dst,
+				slog.Any("db.operation", operationValuer{record.query}),
+				slog.Any("resource.name", resourceValuer{record.query}),
+			)
+		}
+		return dst
+	})
+}
+
+// datadogID converts the hexadecimal identifier of OpenTelemetry to the decimal identifier of Datadog,
+// keeping the lower 64 bits of the 128-bit trace identifiers
+func datadogID(id string) (string, bool) {
+	if len(id) > 16 {
+		id = id[len(id)-16:]
+	}
+	value, err := strconv.ParseUint(id, 16, 64)
+	if err != nil || value == 0 {
+		return "", false
+	}
+	return strconv.FormatUint(value, 10), true
+}
+
+// resourceValuer is a slog.LogValuer resolving the operation and the main table of the query
+type resourceValuer struct{ *lazyQuery }
+
+// LogValue implements slog.LogValuer
+func (v resourceValuer) LogValue() slog.Value {
+	if table := v.Table(); table != "" {
+		return slog.StringValue(v.Operation() + " " + table)
+	}
+	return slog.StringValue(v.Operation())
+}
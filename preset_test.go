@@ -0,0 +1,264 @@
+package slogGorm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Trace_OtelSemconvFields(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users WHERE id = 1", 1
+	}
+
+	t.Run("Trace", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithOtelSemconvFields()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String("db.statement", "SELECT * FROM users WHERE id = 1"))
+		assertHasAttr(t, &resolved, slog.String("db.operation.name", "SELECT"))
+		assertHasAttr(t, &resolved, slog.String("db.collection.name", "users"))
+		assertNoAttr(t, &resolved, QueryField)
+		assertNoAttr(t, &resolved, "db.system")
+	})
+
+	t.Run("Plugin mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithOtelSemconvFields(), WithAsync(8)})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("db.statement", "DELETE FROM users"))
+		assertHasAttr(t, receiver.Record, slog.String("db.system", "dummy"))
+	})
+
+	t.Run("Without preset", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+
+		require.NotNil(t, receiver.Record)
+		assertNoAttr(t, receiver.Record, "db.statement")
+	})
+}
+
+func Test_otelSemconvPreset(t *testing.T) {
+	query := newLazyQuery(func() (string, int64) {
+		return "INSERT INTO orders (id) VALUES (1)", 1
+	})
+
+	attrs := otelSemconvPreset(context.Background(), PresetRecord{
+		Type:     DefaultLogType,
+		DBSystem: "postgres",
+		query:    query,
+	}, []slog.Attr{slog.Any(QueryField, sqlValuer{query}), slog.Int64(RowsField, 1)}, nil)
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "", 0)
+	r.AddAttrs(attrs...)
+	r = resolveRecord(r)
+	assertHasAttr(t, &r, slog.String("db.statement", "INSERT INTO orders (id) VALUES (1)"))
+	assertHasAttr(t, &r, slog.String("db.operation.name", "INSERT"))
+	assertHasAttr(t, &r, slog.String("db.collection.name", "orders"))
+	assertHasAttr(t, &r, slog.String("db.system", "postgresql"))
+	assertHasAttr(t, &r, slog.Int64(RowsField, 1))
+}
+
+func Test_logger_Trace_ECSFields(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("SQL message", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithECSFields()})
+
+		begin := time.Now()
+		_, file, line, _ := runtime.Caller(0)
+		gormLogger.Trace(context.Background(), begin, fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String("db.statement", "SELECT * FROM users"))
+		assertHasAttr(t, &resolved, slog.String("log.origin.file.name", file))
+		assertHasAttr(t, &resolved, slog.Int("log.origin.file.line", line+1))
+		assertNoAttr(t, &resolved, DurationField)
+		assertNoAttr(t, &resolved, SourceField)
+
+		var duration slog.Value
+		resolved.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == "event.duration" {
+				duration = attr.Value
+			}
+			return true
+		})
+		require.Equal(t, slog.KindInt64, duration.Kind())
+		assert.Greater(t, duration.Int64(), int64(0))
+	})
+
+	t.Run("SQL error", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithECSFields()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, errors.New("awesome error"))
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("error.message", "awesome error"))
+		assertHasAttr(t, receiver.Record, slog.String("error.type", "*errors.errorString"))
+		assertNoAttr(t, receiver.Record, ErrorField)
+	})
+}
+
+func Test_logger_Trace_GoogleCloudFields(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	gormLogger := New(
+		WithHandler(slog.NewJSONHandler(buffer, nil)),
+		WithSlowThreshold(time.Millisecond),
+		WithGoogleCloudFields(),
+	).(*logger)
+
+	_, file, line, _ := runtime.Caller(0)
+	gormLogger.Trace(context.Background(), time.Now().Add(-1500*time.Millisecond), fc, nil)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	assert.Equal(t, "WARNING", entry["severity"])
+	assert.Equal(t, map[string]any{"file": file, "line": strconv.Itoa(line + 1)}, entry[googleCloudSourceLocationKey])
+	assert.Regexp(t, `^1\.5[0-9]*s$`, entry[DurationField])
+	assert.Equal(t, "SELECT * FROM users", entry[QueryField])
+	assert.NotContains(t, entry, SourceField)
+}
+
+func Test_googleCloudSeverity(t *testing.T) {
+	assert.Equal(t, "DEBUG", googleCloudSeverity(slog.LevelDebug))
+	assert.Equal(t, "INFO", googleCloudSeverity(slog.LevelInfo))
+	assert.Equal(t, "INFO", googleCloudSeverity(slog.LevelInfo+2))
+	assert.Equal(t, "WARNING", googleCloudSeverity(slog.LevelWarn))
+	assert.Equal(t, "ERROR", googleCloudSeverity(slog.LevelError))
+	assert.Equal(t, "ERROR", googleCloudSeverity(slog.LevelError+4))
+}
+
+func Test_logger_Trace_DatadogFields(t *testing.T) {
+	fc := func() (string, int64) {
+		return "UPDATE users SET name = 'john'", 1
+	}
+
+	t.Run("With a span", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithDatadogFields(func(ctx context.Context) (string, string) {
+				return "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+			}),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String("dd.trace_id", "11803532876627986230"))
+		assertHasAttr(t, &resolved, slog.String("dd.span_id", "67667974448284343"))
+		assertHasAttr(t, &resolved, slog.String("db.statement", "UPDATE users SET name = 'john'"))
+		assertHasAttr(t, &resolved, slog.String("db.operation", "UPDATE"))
+		assertHasAttr(t, &resolved, slog.String("resource.name", "UPDATE users"))
+
+		resolved.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == DurationField {
+				assert.Equal(t, slog.KindInt64, attr.Value.Kind())
+			}
+			return true
+		})
+	})
+
+	t.Run("Without a span", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithDatadogFields(func(ctx context.Context) (string, string) {
+				return "00000000000000000000000000000000", "0000000000000000"
+			}),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertNoAttr(t, receiver.Record, "dd.trace_id")
+		assertNoAttr(t, receiver.Record, "dd.span_id")
+	})
+}
+
+func Test_datadogID(t *testing.T) {
+	id, ok := datadogID("4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.True(t, ok)
+	assert.Equal(t, "11803532876627986230", id)
+
+	id, ok = datadogID("00f067aa0ba902b7")
+	assert.True(t, ok)
+	assert.Equal(t, "67667974448284343", id)
+
+	_, ok = datadogID("")
+	assert.False(t, ok)
+	_, ok = datadogID("not hexadecimal")
+	assert.False(t, ok)
+}
+
+func TestRegisterPreset(t *testing.T) {
+	RegisterPreset("test_prefix", FieldPresetFunc(func(ctx context.Context, record PresetRecord, attrs, dst []slog.Attr) []slog.Attr {
+		for _, attr := range attrs {
+			attr.Key = "test." + attr.Key
+			dst = append(dst, attr)
+		}
+		if record.HasQuery() {
+			dst = append(dst, slog.Any("test.table", record.Table()))
+		}
+		return dst
+	}))
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		delete(presets, "test_prefix")
+		presetsMu.Unlock()
+	})
+
+	receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithPreset("test_prefix")})
+
+	gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}, nil)
+
+	require.NotNil(t, receiver.Record)
+	resolved := resolveRecord(*receiver.Record)
+	assertHasAttr(t, &resolved, slog.String("test."+QueryField, "SELECT * FROM users"))
+	assertHasAttr(t, &resolved, slog.String("test.table", "users"))
+	assertNoAttr(t, &resolved, QueryField)
+	assert.Equal(t, "test_prefix", gormLogger.Config().Preset)
+}
+
+func TestPresetRecord(t *testing.T) {
+	query := newLazyQuery(func() (string, int64) {
+		return "UPDATE orders SET paid = 1", 1
+	})
+
+	record := PresetRecord{query: query}
+	assert.True(t, record.HasQuery())
+	assert.Equal(t, "UPDATE", record.Operation().Resolve().String())
+	assert.Equal(t, "orders", record.Table().Resolve().String())
+
+	record = PresetRecord{Type: CommitLogType}
+	assert.False(t, record.HasQuery())
+	assert.Equal(t, "", record.Operation().Resolve().String())
+	assert.Equal(t, "", record.Table().Resolve().String())
+}
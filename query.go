@@ -0,0 +1,136 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// lazyQuery explains the SQL query and the number of rows affected by calling
+// the function given by gorm to Trace, the first time one of them is needed.
+//
+// The handlers retaining the records beyond Handle must resolve the values of
+// the attributes (see slog.Value.Resolve) before Handle returns, as gorm reuses
+// its statement once Trace returns.
+type lazyQuery struct {
+	fc   func() (sql string, rowsAffected int64)
+	once sync.Once
+	sql  string
+	rows int64
+
+	// replace replaces the SQL query the first time it is needed once set, see replaceSQL
+	replace     func(sql string) string
+	replaceOnce sync.Once
+
+	// parsed from the SQL query when needed
+	parsedOperation, parsedTable, parsedTables bool
+	operation, table                           string
+	tables                                     []string
+}
+
+func newLazyQuery(fc func() (string, int64)) *lazyQuery {
+	return &lazyQuery{fc: fc}
+}
+
+// orNewLazyQuery returns the query, or a new lazy query evaluating fc if it is nil
+func orNewLazyQuery(query *lazyQuery, fc func() (string, int64)) *lazyQuery {
+	if query != nil {
+		return query
+	}
+	return newLazyQuery(fc)
+}
+
+func (q *lazyQuery) resolve() {
+	q.once.Do(func() {
+		q.sql, q.rows = q.fc()
+	})
+}
+
+// replaceSQL replaces the SQL query by the result of fn (e.g. by its fingerprint), called the first time the
+// SQL query is needed, so that the records dropped by the handler never pay for it
+func (q *lazyQuery) replaceSQL(fn func(sql string) string) {
+	q.replace = fn
+}
+
+// SQL returns the SQL query, replaced if set by replaceSQL
+func (q *lazyQuery) SQL() string {
+	q.resolve()
+	if q.replace != nil {
+		q.replaceOnce.Do(func() {
+			q.sql = q.replace(q.sql)
+		})
+	}
+	return q.sql
+}
+
+// Rows returns the number of rows affected
+func (q *lazyQuery) Rows() int64 {
+	q.resolve()
+	return q.rows
+}
+
+// Operation returns the operation of the SQL query (SELECT, INSERT, ...)
+func (q *lazyQuery) Operation() string {
+	if !q.parsedOperation {
+		q.operation = parseOperation(q.SQL())
+		q.parsedOperation = true
+	}
+	return q.operation
+}
+
+// Table returns the main table of the SQL query
+func (q *lazyQuery) Table() string {
+	if !q.parsedTable {
+		q.table = parseTable(q.SQL())
+		q.parsedTable = true
+	}
+	return q.table
+}
+
+// Tables returns the tables referenced by the SQL query, see parseTables
+func (q *lazyQuery) Tables() []string {
+	if !q.parsedTables {
+		q.tables = parseTables(q.SQL())
+		q.parsedTables = true
+	}
+	return q.tables
+}
+
+// sqlValuer is a slog.LogValuer resolving the SQL query when the handler formats the record
+type sqlValuer struct{ *lazyQuery }
+
+// LogValue implements slog.LogValuer
+func (v sqlValuer) LogValue() slog.Value {
+	return slog.StringValue(v.SQL())
+}
+
+// rowsValuer is a slog.LogValuer resolving the number of rows affected when the handler formats the record
+type rowsValuer struct{ *lazyQuery }
+
+// LogValue implements slog.LogValuer
+func (v rowsValuer) LogValue() slog.Value {
+	return slog.Int64Value(v.Rows())
+}
+
+// operationValuer is a slog.LogValuer resolving the operation of the SQL query when the handler formats the record
+type operationValuer struct{ *lazyQuery }
+
+// LogValue implements slog.LogValuer
+func (v operationValuer) LogValue() slog.Value {
+	return slog.StringValue(v.Operation())
+}
+
+// tableValuer is a slog.LogValuer resolving the main table of the SQL query when the handler formats the record
+type tableValuer struct{ *lazyQuery }
+
+// LogValue implements slog.LogValuer
+func (v tableValuer) LogValue() slog.Value {
+	return slog.StringValue(v.Table())
+}
+
+// tablesValuer is a slog.LogValuer resolving the tables referenced by the SQL query when the handler formats the record
+type tablesValuer struct{ *lazyQuery }
+
+// LogValue implements slog.LogValuer
+func (v tablesValuer) LogValue() slog.Value {
+	return slog.AnyValue(v.Tables())
+}
@@ -0,0 +1,225 @@
+package slogGorm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// queryLogEvent is the line of a query written by QueryLogFile
+type queryLogEvent struct {
+	Time        time.Time      `json:"time"`
+	Query       string         `json:"query"`
+	Operation   string         `json:"operation,omitempty"`
+	Table       string         `json:"table,omitempty"`
+	Rows        int64          `json:"rows"`
+	Duration    time.Duration  `json:"duration"`
+	Error       string         `json:"error,omitempty"`
+	TxStmtIndex int64          `json:"tx_stmt_index,omitempty"`
+	Source      string         `json:"file,omitempty"`
+	Attrs       map[string]any `json:"attrs,omitempty"`
+}
+
+// newQueryLogEvent returns the line of the query event
+func newQueryLogEvent(event QueryEvent) queryLogEvent {
+	line := queryLogEvent{
+		Time:        event.Time,
+		Query:       event.SQL,
+		Operation:   event.Operation,
+		Table:       event.Table,
+		Rows:        event.Rows,
+		Duration:    event.Duration,
+		TxStmtIndex: event.TxStmtIndex,
+		Source:      event.Source,
+		Attrs:       attrsMap(event.Attrs),
+	}
+	if event.Err != nil {
+		line.Error = event.Err.Error()
+	}
+	return line
+}
+
+// attrsMap returns the resolved values of the attributes by key, the groups as nested maps
+func attrsMap(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+		if value.Kind() == slog.KindGroup {
+			m[attr.Key] = attrsMap(value.Group())
+			continue
+		}
+		m[attr.Key] = value.Any()
+	}
+	return m
+}
+
+// QueryLogFile writes the queries to a file as JSON Lines, independently of the slog handler, see
+// WithQueryLogFile. Each line is a JSON object with the time the query started, the SQL query, its operation,
+// its main table, the number of rows, the duration in nanoseconds, the error, the index of the statement in
+// its transaction, the source and the context attributes, if any. It is a Sink, safe for concurrent use.
+type QueryLogFile struct {
+	mu             sync.Mutex
+	path           string
+	file           *os.File
+	size           int64
+	opened         time.Time
+	maxSize        int64
+	rotateInterval time.Duration
+	onRotate       func(rotated string)
+	closed         bool
+}
+
+// QueryLogFileOption is an option of the query log file, see NewQueryLogFile
+type QueryLogFileOption func(f *QueryLogFile)
+
+// WithQueryLogMaxSize rotates the query log file once it holds at least size bytes (no limit by default)
+func WithQueryLogMaxSize(size int64) QueryLogFileOption {
+	return func(f *QueryLogFile) {
+		f.maxSize = size
+	}
+}
+
+// WithQueryLogRotateInterval rotates the query log file once it has been open for at least the given interval
+// (no limit by default), e.g. daily. The rotation is triggered by the first query written after the interval,
+// before writing it.
+func WithQueryLogRotateInterval(interval time.Duration) QueryLogFileOption {
+	return func(f *QueryLogFile) {
+		f.rotateInterval = interval
+	}
+}
+
+// WithQueryLogRotateHook calls fn with the path of the rotated file after each rotation, e.g. to compress
+// or upload it. It is called synchronously by the query triggering the rotation, so the long tasks should
+// be run in a goroutine.
+func WithQueryLogRotateHook(fn func(rotated string)) QueryLogFileOption {
+	return func(f *QueryLogFile) {
+		f.onRotate = fn
+	}
+}
+
+// NewQueryLogFile opens the query log file at path, appending to it if it exists
+func NewQueryLogFile(path string, options ...QueryLogFileOption) (*QueryLogFile, error) {
+	f := &QueryLogFile{path: path}
+	for _, option := range options {
+		option(f)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the file at the path of the query log file
+func (f *QueryLogFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file, f.size, f.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+// Rotate renames the current file by suffixing its path with the current time (e.g.
+// "queries.jsonl.20240102T150405.123456789Z"), opens a new file at the path and calls the rotate hook,
+// if any. It returns the path of the rotated file.
+func (f *QueryLogFile) Rotate() (string, error) {
+	f.mu.Lock()
+	rotated, err := f.rotate()
+	f.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	if f.onRotate != nil {
+		f.onRotate(rotated)
+	}
+	return rotated, nil
+}
+
+// rotate rotates the file, with the lock held
+func (f *QueryLogFile) rotate() (string, error) {
+	if f.closed {
+		return "", os.ErrClosed
+	}
+	rotated := f.path + "." + time.Now().UTC().Format("20060102T150405.000000000Z")
+	if err := f.file.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.path, rotated); err != nil {
+		return "", errors.Join(err, f.open())
+	}
+	return rotated, f.open()
+}
+
+// Close closes the file. The queries are no longer written once it is closed.
+func (f *QueryLogFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.file.Close()
+}
+
+// HandleQuery implements Sink, writing the line of the query event. The write errors are ignored.
+func (f *QueryLogFile) HandleQuery(_ context.Context, event QueryEvent) {
+	_ = f.write(newQueryLogEvent(event))
+}
+
+// write writes the line of the query, rotating the file first if it has been open for longer than its rotate
+// interval, then if it exceeds its maximum size
+func (f *QueryLogFile) write(event queryLogEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return os.ErrClosed
+	}
+	var rotated []string
+	if f.rotateInterval > 0 && time.Since(f.opened) >= f.rotateInterval {
+		// An empty file is kept, and its interval restarted
+		if f.size == 0 {
+			f.opened = time.Now()
+		} else {
+			path, err := f.rotate()
+			if err != nil {
+				f.mu.Unlock()
+				return err
+			}
+			rotated = append(rotated, path)
+		}
+	}
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	if err == nil && f.maxSize > 0 && f.size >= f.maxSize {
+		var path string
+		if path, err = f.rotate(); path != "" {
+			rotated = append(rotated, path)
+		}
+	}
+	f.mu.Unlock()
+
+	if f.onRotate != nil {
+		for _, path := range rotated {
+			f.onRotate(path)
+		}
+	}
+	return err
+}
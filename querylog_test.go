@@ -0,0 +1,237 @@
+package slogGorm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readQueryLog returns the lines of the query log file at path
+func readQueryLog(t *testing.T, path string) []map[string]any {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func Test_logger_QueryLogFile(t *testing.T) {
+	trace := func(l *logger, sql string, err error) {
+		l.Trace(context.Background(), time.Now().Add(-time.Millisecond), func() (string, int64) { return sql, 2 }, err)
+	}
+	errQuery := errors.New("duplicate key value violates unique constraint")
+
+	t.Run("Queries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		file, err := NewQueryLogFile(path)
+		require.NoError(t, err)
+		defer file.Close()
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithIgnoreTrace(),
+			WithContextFunc("user", func(context.Context) (slog.Value, bool) {
+				return slog.GroupValue(slog.String("name", "john")), true
+			}),
+			WithQueryLogFile(file),
+		})
+
+		trace(gormLogger, "SELECT * FROM users WHERE id = 1", nil)
+		trace(gormLogger, "INSERT INTO users (name) VALUES ('john')", errQuery)
+
+		assert.Nil(t, receiver.Record)
+		lines := readQueryLog(t, path)
+		require.Len(t, lines, 2)
+		assert.Equal(t, "SELECT * FROM users WHERE id = 1", lines[0][QueryField])
+		assert.Equal(t, "SELECT", lines[0][OperationField])
+		assert.Equal(t, "users", lines[0][TableField])
+		assert.Equal(t, 2.0, lines[0][RowsField])
+		assert.GreaterOrEqual(t, lines[0][DurationField], float64(time.Millisecond))
+		assert.Contains(t, lines[0][SourceField], "querylog_test.go:")
+		assert.NotContains(t, lines[0], ErrorField)
+		assert.Equal(t, map[string]any{"user": map[string]any{"name": "john"}}, lines[0]["attrs"])
+		assert.Equal(t, "INSERT", lines[1][OperationField])
+		assert.Equal(t, errQuery.Error(), lines[1][ErrorField])
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		file, err := NewQueryLogFile(path)
+		require.NoError(t, err)
+		defer file.Close()
+		_, gormLogger := getReceiverAndLogger([]Option{WithNoValues(), WithQueryLogFile(file)})
+
+		trace(gormLogger, "SELECT * FROM users WHERE email = 'john@example.com'", errQuery)
+
+		lines := readQueryLog(t, path)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "SELECT * FROM users WHERE email = ?", lines[0][QueryField])
+		assert.Equal(t, "*errors.errorString", lines[0][ErrorField])
+	})
+
+	t.Run("Enabled temporarily", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		file, err := NewQueryLogFile(path)
+		require.NoError(t, err)
+		defer file.Close()
+		_, gormLogger := getReceiverAndLogger(nil)
+
+		trace(gormLogger, "SELECT 1", nil)
+		gormLogger.Apply(WithQueryLogFile(file))
+		trace(gormLogger, "SELECT 2", nil)
+		gormLogger.Apply(WithoutQueryLogFile())
+		trace(gormLogger, "SELECT 3", nil)
+
+		lines := readQueryLog(t, path)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "SELECT 2", lines[0][QueryField])
+	})
+}
+
+func Test_logger_SlowQueryLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.jsonl")
+	file, err := NewQueryLogFile(path)
+	require.NoError(t, err)
+	defer file.Close()
+	receiver, gormLogger := getReceiverAndLogger([]Option{
+		WithIgnoreTrace(),
+		WithSlowThreshold(10 * time.Millisecond),
+		WithSlowQueryLogFile(file),
+	})
+
+	gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 2", 1 }, nil)
+
+	assert.Nil(t, receiver.Record)
+	lines := readQueryLog(t, path)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "SELECT 2", lines[0][QueryField])
+}
+
+func TestQueryLogFile(t *testing.T) {
+	event := queryLogEvent{Time: time.Now(), Query: "SELECT * FROM users"}
+
+	t.Run("Rotation by size", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		var rotated []string
+		file, err := NewQueryLogFile(path,
+			WithQueryLogMaxSize(1),
+			WithQueryLogRotateHook(func(path string) { rotated = append(rotated, path) }),
+		)
+		require.NoError(t, err)
+		defer file.Close()
+
+		require.NoError(t, file.write(event))
+		require.NoError(t, file.write(event))
+
+		require.Len(t, rotated, 2)
+		for _, r := range rotated {
+			assert.True(t, strings.HasPrefix(r, path+"."))
+			assert.Len(t, readQueryLog(t, r), 1)
+		}
+		assert.Empty(t, readQueryLog(t, path))
+	})
+
+	t.Run("Rotation by interval", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		var rotated []string
+		file, err := NewQueryLogFile(path,
+			WithQueryLogRotateInterval(time.Hour),
+			WithQueryLogRotateHook(func(path string) { rotated = append(rotated, path) }),
+		)
+		require.NoError(t, err)
+		defer file.Close()
+
+		require.NoError(t, file.write(event))
+		require.NoError(t, file.write(event))
+		assert.Empty(t, rotated)
+
+		file.opened = file.opened.Add(-time.Hour)
+		require.NoError(t, file.write(event))
+
+		require.Len(t, rotated, 1)
+		assert.Len(t, readQueryLog(t, rotated[0]), 2)
+		assert.Len(t, readQueryLog(t, path), 1)
+	})
+
+	t.Run("Empty file not rotated by interval", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		file, err := NewQueryLogFile(path, WithQueryLogRotateInterval(time.Hour))
+		require.NoError(t, err)
+		defer file.Close()
+
+		file.opened = file.opened.Add(-time.Hour)
+		require.NoError(t, file.write(event))
+		require.NoError(t, file.write(event))
+
+		assert.Len(t, readQueryLog(t, path), 2)
+		matches, err := filepath.Glob(path + ".*")
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("Manual rotation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		var hooked string
+		file, err := NewQueryLogFile(path, WithQueryLogRotateHook(func(path string) { hooked = path }))
+		require.NoError(t, err)
+		defer file.Close()
+
+		require.NoError(t, file.write(event))
+		rotated, err := file.Rotate()
+		require.NoError(t, err)
+		require.NoError(t, file.write(event))
+
+		assert.Equal(t, rotated, hooked)
+		assert.Len(t, readQueryLog(t, rotated), 1)
+		assert.Len(t, readQueryLog(t, path), 1)
+	})
+
+	t.Run("Appending", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+
+		file, err := NewQueryLogFile(path, WithQueryLogMaxSize(4))
+		require.NoError(t, err)
+		defer file.Close()
+		require.NoError(t, file.write(event))
+
+		// The size of the existing file is taken into account
+		assert.Empty(t, readQueryLog(t, path))
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queries.jsonl")
+		file, err := NewQueryLogFile(path)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		require.NoError(t, file.Close())
+
+		assert.ErrorIs(t, file.write(event), os.ErrClosed)
+		_, err = file.Rotate()
+		assert.ErrorIs(t, err, os.ErrClosed)
+	})
+
+	t.Run("Invalid path", func(t *testing.T) {
+		_, err := NewQueryLogFile(filepath.Join(t.TempDir(), "missing", "queries.jsonl"))
+		assert.Error(t, err)
+	})
+}
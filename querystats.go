@@ -0,0 +1,235 @@
+package slogGorm
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxStatsFingerprints is the number of fingerprints aggregated by WithQueryStats, the queries with
+	// other fingerprints being ignored
+	maxStatsFingerprints = 1024
+	// maxStatsValues is the number of values of a context attribute aggregated by WithRouteStats and
+	// WithJobStats, the queries with other values being ignored
+	maxStatsValues = 1024
+	// statsSamples is the number of the last durations of a fingerprint whose percentiles are computed
+	statsSamples = 1024
+)
+
+// QueryStats are the aggregates of the queries sharing a fingerprint, see WithQueryStats
+type QueryStats struct {
+	// Fingerprint is the structure of the SQL queries without their values, see WithNoValues
+	Fingerprint string
+	Count       int64
+	// Errors is the number of queries which failed
+	Errors int64
+	// Slow is the number of queries slower than the slow threshold, see WithSlowThreshold
+	Slow    int64
+	Average time.Duration
+	// P95 is the 95th percentile of the durations of the last 1024 queries
+	P95 time.Duration
+	// FirstSeen and LastSeen are the start times of the first and last queries
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// AttrStats are the aggregates of the queries sharing the value of a context attribute, e.g. a route or a job, see
+// WithRouteStats and WithJobStats
+type AttrStats struct {
+	Value  string
+	Count  int64
+	Errors int64
+	Slow   int64
+	// Total is the cumulated duration of the queries, to find the values loading the database the most
+	Total   time.Duration
+	Average time.Duration
+	// P95 is the 95th percentile of the durations of the last 1024 queries
+	P95 time.Duration
+}
+
+// queryStats aggregates the queries by fingerprint, shared by the copies of the logger and the loggers
+// derived with With
+type queryStats struct {
+	mu           sync.Mutex
+	fingerprints map[string]*aggregate
+}
+
+// aggregate are the running aggregates of the queries of a fingerprint or of a value of a context attribute
+type aggregate struct {
+	count, errors, slow int64
+	total               time.Duration
+	firstSeen, lastSeen time.Time
+	// samples is the ring buffer of the last durations, next being the index of the oldest one once full
+	samples []time.Duration
+	next    int
+}
+
+// HandleQuery implements Sink, aggregating the query with the others of its fingerprint
+func (s *queryStats) HandleQuery(_ context.Context, event QueryEvent) {
+	key := fingerprint(event.SQL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.fingerprints[key]
+	if !ok {
+		if len(s.fingerprints) >= maxStatsFingerprints {
+			return
+		}
+		if s.fingerprints == nil {
+			s.fingerprints = make(map[string]*aggregate)
+		}
+		stats = &aggregate{firstSeen: event.Time, lastSeen: event.Time}
+		s.fingerprints[key] = stats
+	}
+	stats.add(event)
+}
+
+// add aggregates the query
+func (a *aggregate) add(event QueryEvent) {
+	a.count++
+	if event.Err != nil {
+		a.errors++
+	}
+	if event.Slow {
+		a.slow++
+	}
+	a.total += event.Duration
+	// The concurrent queries may be handled out of order
+	if event.Time.Before(a.firstSeen) {
+		a.firstSeen = event.Time
+	}
+	if event.Time.After(a.lastSeen) {
+		a.lastSeen = event.Time
+	}
+	if len(a.samples) < statsSamples {
+		a.samples = append(a.samples, event.Duration)
+	} else {
+		a.samples[a.next] = event.Duration
+		a.next = (a.next + 1) % statsSamples
+	}
+}
+
+// snapshot returns the aggregates of the fingerprints, sorted by decreasing count then by fingerprint
+func (s *queryStats) snapshot() []QueryStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queries := make([]QueryStats, 0, len(s.fingerprints))
+	for key, stats := range s.fingerprints {
+		queries = append(queries, QueryStats{
+			Fingerprint: key,
+			Count:       stats.count,
+			Errors:      stats.errors,
+			Slow:        stats.slow,
+			Average:     stats.total / time.Duration(stats.count),
+			P95:         percentile(slices.Clone(stats.samples), 0.95),
+			FirstSeen:   stats.firstSeen,
+			LastSeen:    stats.lastSeen,
+		})
+	}
+	slices.SortFunc(queries, func(a, b QueryStats) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Fingerprint, b.Fingerprint)
+	})
+	return queries
+}
+
+// attrStats aggregates the queries by value of a context attribute, shared by the copies of the logger and
+// the loggers derived with With, see WithRouteStats and WithJobStats
+type attrStats struct {
+	attr   string
+	mu     sync.Mutex
+	values map[string]*aggregate
+}
+
+// HandleQuery implements Sink, aggregating the query with the others of the value of its context attribute,
+// the queries without it being ignored
+func (s *attrStats) HandleQuery(_ context.Context, event QueryEvent) {
+	i := slices.IndexFunc(event.Attrs, func(attr slog.Attr) bool { return attr.Key == s.attr })
+	if i < 0 {
+		return
+	}
+	key := event.Attrs[i].Value.Resolve().String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.values[key]
+	if !ok {
+		if len(s.values) >= maxStatsValues {
+			return
+		}
+		if s.values == nil {
+			s.values = make(map[string]*aggregate)
+		}
+		stats = &aggregate{firstSeen: event.Time, lastSeen: event.Time}
+		s.values[key] = stats
+	}
+	stats.add(event)
+}
+
+// snapshot returns the aggregates of the values, sorted by decreasing total duration then by value
+func (s *attrStats) snapshot() []AttrStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make([]AttrStats, 0, len(s.values))
+	for key, stats := range s.values {
+		values = append(values, AttrStats{
+			Value:   key,
+			Count:   stats.count,
+			Errors:  stats.errors,
+			Slow:    stats.slow,
+			Total:   stats.total,
+			Average: stats.total / time.Duration(stats.count),
+			P95:     percentile(slices.Clone(stats.samples), 0.95),
+		})
+	}
+	slices.SortFunc(values, func(a, b AttrStats) int {
+		if a.Total != b.Total {
+			return cmp.Compare(b.Total, a.Total)
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return values
+}
+
+// WriteCSV writes the aggregates of the queries by fingerprint (see WithQueryStats) as CSV, with a header:
+// fingerprint, count, avg_ms, p95_ms, errors, first_seen and last_seen (RFC 3339 with nanoseconds, in UTC).
+func (s Stats) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"fingerprint", "count", "avg_ms", "p95_ms", "errors", "first_seen", "last_seen"}); err != nil {
+		return err
+	}
+	for _, q := range s.Queries {
+		record := []string{
+			q.Fingerprint,
+			strconv.FormatInt(q.Count, 10),
+			formatMilliseconds(q.Average),
+			formatMilliseconds(q.P95),
+			strconv.FormatInt(q.Errors, 10),
+			q.FirstSeen.UTC().Format(time.RFC3339Nano),
+			q.LastSeen.UTC().Format(time.RFC3339Nano),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatMilliseconds formats the duration in milliseconds, with 3 decimals
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
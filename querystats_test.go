@@ -0,0 +1,235 @@
+package slogGorm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_QueryStats(t *testing.T) {
+	begin := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	trace := func(l *logger, begin time.Time, sql string, err error) {
+		l.Trace(context.Background(), begin, func() (string, int64) { return sql, 1 }, err)
+	}
+
+	t.Run("Aggregates", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithIgnoreTrace(), WithQueryStats()})
+
+		trace(gormLogger, begin, "SELECT * FROM users WHERE id = 1", nil)
+		trace(gormLogger, begin.Add(time.Second), "SELECT * FROM users WHERE id = 2", errors.New("timeout"))
+		trace(gormLogger, begin.Add(2*time.Second), "DELETE FROM sessions", nil)
+
+		assert.Nil(t, receiver.Record)
+		queries := gormLogger.Stats().Queries
+		require.Len(t, queries, 2)
+		assert.Equal(t, "SELECT * FROM users WHERE id = ?", queries[0].Fingerprint)
+		assert.Equal(t, int64(2), queries[0].Count)
+		assert.Equal(t, int64(1), queries[0].Errors)
+		assert.Positive(t, queries[0].Average)
+		assert.Positive(t, queries[0].P95)
+		assert.Equal(t, begin, queries[0].FirstSeen)
+		assert.Equal(t, begin.Add(time.Second), queries[0].LastSeen)
+		assert.Equal(t, "DELETE FROM sessions", queries[1].Fingerprint)
+		assert.Equal(t, int64(1), queries[1].Count)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger(nil)
+
+		trace(gormLogger, begin, "SELECT 1", nil)
+
+		assert.Nil(t, gormLogger.Stats().Queries)
+	})
+}
+
+func Test_queryStats(t *testing.T) {
+	t.Run("Samples", func(t *testing.T) {
+		stats := &queryStats{}
+		for i := 1; i <= statsSamples+100; i++ {
+			stats.HandleQuery(context.Background(), QueryEvent{SQL: "SELECT 1", Duration: time.Duration(i)})
+		}
+
+		queries := stats.snapshot()
+		require.Len(t, queries, 1)
+		assert.Equal(t, int64(statsSamples+100), queries[0].Count)
+		assert.Zero(t, queries[0].Slow)
+		// The percentile is computed on the last durations
+		assert.Equal(t, time.Duration(1073), queries[0].P95)
+	})
+
+	t.Run("Slow queries", func(t *testing.T) {
+		stats := &queryStats{}
+		stats.HandleQuery(context.Background(), QueryEvent{SQL: "SELECT 1", Duration: time.Second, Slow: true})
+		stats.HandleQuery(context.Background(), QueryEvent{SQL: "SELECT 1", Duration: time.Millisecond})
+
+		queries := stats.snapshot()
+		require.Len(t, queries, 1)
+		assert.Equal(t, int64(1), queries[0].Slow)
+	})
+
+	t.Run("Bounded fingerprints", func(t *testing.T) {
+		stats := &queryStats{}
+		for i := 0; i <= maxStatsFingerprints; i++ {
+			stats.HandleQuery(context.Background(), QueryEvent{SQL: "SELECT * FROM t_" + strconv.Itoa(i)})
+		}
+
+		assert.Len(t, stats.snapshot(), maxStatsFingerprints)
+	})
+}
+
+func TestStats_WriteCSV(t *testing.T) {
+	seen := time.Date(2024, 1, 2, 16, 4, 5, 0, time.FixedZone("CET", 3600))
+	stats := Stats{Queries: []QueryStats{{
+		Fingerprint: "SELECT * FROM users WHERE name = ?",
+		Count:       3,
+		Errors:      1,
+		Average:     1500 * time.Microsecond,
+		P95:         2 * time.Millisecond,
+		FirstSeen:   seen,
+		LastSeen:    seen.Add(time.Minute),
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, stats.WriteCSV(&buf))
+
+	assert.Equal(t, "fingerprint,count,avg_ms,p95_ms,errors,first_seen,last_seen\n"+
+		"SELECT * FROM users WHERE name = ?,3,1.500,2.000,1,2024-01-02T15:04:05Z,2024-01-02T15:05:05Z\n", buf.String())
+}
+
+func Test_logger_RouteStats(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	routeKey := ctxKey(43)
+	trace := func(l *logger, route string, elapsed time.Duration, err error) {
+		ctx := context.Background()
+		if route != "" {
+			ctx = context.WithValue(ctx, routeKey, route)
+		}
+		l.Trace(ctx, now.Add(-elapsed), func() (string, int64) { return "SELECT * FROM users", 1 }, err)
+	}
+
+	t.Run("Aggregates", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(fixedClock{now: now}),
+			WithContextValue("http.route", routeKey),
+			WithRouteStats("http.route"),
+			WithTraceAll(),
+		})
+
+		trace(gormLogger, "GET /users", time.Millisecond, nil)
+		trace(gormLogger, "GET /users", 3*time.Millisecond, errors.New("timeout"))
+		trace(gormLogger, "GET /reports", 10*time.Millisecond, nil)
+		trace(gormLogger, "", time.Second, nil)
+
+		require.NotNil(t, receiver.Record)
+		routes := gormLogger.Stats().Routes
+		require.Len(t, routes, 2)
+		assert.Equal(t, AttrStats{
+			Value:   "GET /reports",
+			Count:   1,
+			Total:   10 * time.Millisecond,
+			Average: 10 * time.Millisecond,
+			P95:     10 * time.Millisecond,
+		}, routes[0])
+		assert.Equal(t, "GET /users", routes[1].Value)
+		assert.Equal(t, int64(2), routes[1].Count)
+		assert.Equal(t, int64(1), routes[1].Errors)
+		assert.Equal(t, 4*time.Millisecond, routes[1].Total)
+		assert.Equal(t, 2*time.Millisecond, routes[1].Average)
+	})
+
+	t.Run("Route on the records", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithContextValue("http.route", routeKey),
+			WithRouteStats("http.route"),
+			WithTraceAll(),
+		})
+
+		trace(gormLogger, "GET /users", time.Millisecond, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("http.route", "GET /users"))
+	})
+
+	t.Run("Whatever the tracing", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithContextValue("http.route", routeKey),
+			WithRouteStats("http.route"),
+			WithIgnoreTrace(),
+		})
+
+		trace(gormLogger, "GET /users", time.Millisecond, nil)
+
+		assert.Nil(t, receiver.Record)
+		assert.Len(t, gormLogger.Stats().Routes, 1)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{WithContextValue("http.route", routeKey)})
+
+		trace(gormLogger, "GET /users", time.Millisecond, nil)
+
+		assert.Nil(t, gormLogger.Stats().Routes)
+	})
+}
+
+func Test_attrStats(t *testing.T) {
+	stats := &attrStats{attr: "route"}
+	for i := 0; i < maxStatsValues+10; i++ {
+		stats.HandleQuery(context.Background(), QueryEvent{Attrs: []slog.Attr{slog.Int("route", i)}})
+	}
+
+	assert.Len(t, stats.snapshot(), maxStatsValues)
+}
+
+func Test_logger_JobStats(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	jobKey := ctxKey(44)
+	trace := func(l *logger, job string, elapsed time.Duration, err error) {
+		ctx := context.Background()
+		if job != "" {
+			ctx = context.WithValue(ctx, jobKey, job)
+		}
+		l.Trace(ctx, now.Add(-elapsed), func() (string, int64) { return "SELECT * FROM invoices", 1 }, err)
+	}
+
+	t.Run("Aggregates", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(fixedClock{now: now}),
+			WithContextValue("job", jobKey),
+			WithJobStats("job"),
+		})
+
+		trace(gormLogger, "send-invoices", time.Millisecond, nil)
+		trace(gormLogger, "send-invoices", 5*time.Millisecond, errors.New("timeout"))
+		trace(gormLogger, "purge-sessions", 2*time.Millisecond, nil)
+		trace(gormLogger, "", time.Second, nil)
+
+		jobs := gormLogger.Stats().Jobs
+		require.Len(t, jobs, 2)
+		assert.Equal(t, AttrStats{
+			Value:   "send-invoices",
+			Count:   2,
+			Errors:  1,
+			Total:   6 * time.Millisecond,
+			Average: 3 * time.Millisecond,
+			P95:     5 * time.Millisecond,
+		}, jobs[0])
+		assert.Equal(t, "purge-sessions", jobs[1].Value)
+		assert.Nil(t, gormLogger.Stats().Routes)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{WithContextValue("job", jobKey)})
+
+		trace(gormLogger, "send-invoices", time.Millisecond, nil)
+
+		assert.Nil(t, gormLogger.Stats().Jobs)
+	})
+}
@@ -0,0 +1,58 @@
+package slogGorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RedactionViolation describes a record whose SQL query still contains literals once redacted,
+// reported by WithRedactionVerification
+type RedactionViolation struct {
+	// LogType is the log type of the record
+	LogType LogType
+	// SQL is the SQL query of the record, as logged
+	SQL string
+	// Literals are the string and number literals of the SQL query, in their order
+	Literals []string
+}
+
+// Error implements error, so that the violation is the value of the panic when no callback is given
+func (v RedactionViolation) Error() string {
+	return fmt.Sprintf("slog-gorm: unredacted literals in the %s record: %s (%s)", v.LogType, strings.Join(v.Literals, ", "), v.SQL)
+}
+
+// unredactedLiterals returns the string and number literals of the SQL query, except the masked values
+func unredactedLiterals(sql string) []string {
+	var literals []string
+	tokenize(sql, func(t token) bool {
+		if isLiteral(t) && t.text != maskedValue {
+			literals = append(literals, t.text)
+		}
+		return true
+	})
+	return literals
+}
+
+// panicRedactionViolation is the callback of WithRedactionVerification when none is given
+func panicRedactionViolation(_ context.Context, violation RedactionViolation) {
+	panic(violation)
+}
+
+// verifyRedaction reports the SQL query of the record about to be logged if it still contains literals,
+// see WithRedactionVerification. The query is only resolved if the verification is enabled.
+func (l logger) verifyRedaction(ctx context.Context, logType LogType, query *lazyQuery) {
+	if l.redactionCheck != nil {
+		l.verifyRedactionSQL(ctx, logType, query.SQL())
+	}
+}
+
+// verifyRedactionSQL reports the SQL query if it still contains literals, see WithRedactionVerification
+func (l logger) verifyRedactionSQL(ctx context.Context, logType LogType, sql string) {
+	if l.redactionCheck == nil {
+		return
+	}
+	if literals := unredactedLiterals(sql); len(literals) > 0 {
+		l.redactionCheck(ctx, RedactionViolation{LogType: logType, SQL: sql, Literals: literals})
+	}
+}
@@ -0,0 +1,101 @@
+package slogGorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_RedactionVerification(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+	collect := func(violations *[]RedactionViolation) Option {
+		return WithRedactionVerification(func(_ context.Context, violation RedactionViolation) {
+			*violations = append(*violations, violation)
+		})
+	}
+
+	t.Run("Unmasked literals", func(t *testing.T) {
+		var violations []RedactionViolation
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithMaskedColumns("users.email"),
+			collect(&violations),
+		})
+
+		trace(gormLogger, "SELECT * FROM users WHERE email = 'john@example.com' AND age > 42")
+
+		require.NotNil(t, receiver.Record)
+		require.Len(t, violations, 1)
+		assert.Equal(t, DefaultLogType, violations[0].LogType)
+		assert.Equal(t, "SELECT * FROM users WHERE email = '***' AND age > 42", violations[0].SQL)
+		assert.Equal(t, []string{"42"}, violations[0].Literals)
+	})
+
+	t.Run("Masked literals", func(t *testing.T) {
+		var violations []RedactionViolation
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithMaskedColumns("users.email", "users.age"),
+			collect(&violations),
+		})
+
+		trace(gormLogger, "SELECT * FROM users WHERE email = 'john@example.com' AND age > 42")
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("No values", func(t *testing.T) {
+		var violations []RedactionViolation
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithNoValues(),
+			WithAudit(NewDummyHandler(), nil),
+			collect(&violations),
+		})
+
+		trace(gormLogger, "UPDATE users SET email = 'john@example.com' WHERE id = 1")
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Audit record", func(t *testing.T) {
+		var violations []RedactionViolation
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithAudit(NewDummyHandler(), nil),
+			collect(&violations),
+		})
+
+		trace(gormLogger, "DELETE FROM users WHERE id = 1")
+
+		require.Len(t, violations, 1)
+		assert.Equal(t, AuditLogType, violations[0].LogType)
+	})
+
+	t.Run("Records not logged", func(t *testing.T) {
+		var violations []RedactionViolation
+		_, gormLogger := getReceiverAndLogger([]Option{collect(&violations)})
+
+		trace(gormLogger, "SELECT * FROM users WHERE id = 1")
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithRedactionVerification(nil)})
+
+		assert.PanicsWithError(t,
+			"slog-gorm: unredacted literals in the default record: 1, 'john' (SELECT * FROM users WHERE id = 1 AND name = 'john')",
+			func() { trace(gormLogger, "SELECT * FROM users WHERE id = 1 AND name = 'john'") },
+		)
+	})
+}
+
+func Test_unredactedLiterals(t *testing.T) {
+	assert.Empty(t, unredactedLiterals(`SELECT * FROM "users" WHERE "email" = ? AND "token" = '***'`))
+	assert.Equal(t, []string{"'a'", "2"}, unredactedLiterals("SELECT 'a', 2 FROM users -- LIMIT 3"))
+}
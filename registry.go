@@ -0,0 +1,51 @@
+package slogGorm
+
+import (
+	"slices"
+	"sync"
+)
+
+// registry holds the named loggers by name, see WithName
+var registry = struct {
+	mu      sync.RWMutex
+	loggers map[string]Logger
+}{loggers: map[string]Logger{}}
+
+// register adds the logger to the registry under its name, if it is named, replacing the logger
+// registered with the same name
+func register(l *logger) {
+	if l.name == "" {
+		return
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.loggers[l.name] = l
+}
+
+// Get returns the logger created with the given name (see WithName), so that the SQL loggers of a
+// service connecting to several databases can be managed centrally, e.g. to reconfigure or to report
+// the health of one of them. A name registered again returns the last logger created with it.
+//
+// Usage:
+//
+//	if gormLogger, ok := slogGorm.Get("orders-db"); ok {
+//		health := gormLogger.Health()
+//	}
+func Get(name string) (Logger, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	l, ok := registry.loggers[name]
+	return l, ok
+}
+
+// Names returns the names of the loggers registered with WithName, sorted
+func Names() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	names := make([]string, 0, len(registry.loggers))
+	for name := range registry.loggers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
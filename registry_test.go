@@ -0,0 +1,109 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Name(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM orders", 1
+	}
+
+	t.Run("Every record", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithName("orders-db"), WithTraceAll(), WithBuildInfo()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		require.Equal(t, 2, receiver.Len())
+		for _, r := range receiver.Records {
+			values := startupValues(r)
+			assert.Equal(t, "orders-db", values[NameField].String())
+			assert.Contains(t, values, "slog_gorm.version")
+		}
+	})
+
+	t.Run("Key casing", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithName("orders-db"), WithTraceAll(), WithKeyCase(CamelCase)})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("loggerName", "orders-db"))
+	})
+
+	t.Run("Unnamed", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.NotContains(t, startupValues(*receiver.Record), NameField)
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("Registered by New", func(t *testing.T) {
+		gormLogger := New(WithName("registry-new"))
+
+		actual, ok := Get("registry-new")
+
+		require.True(t, ok)
+		assert.Same(t, gormLogger, actual)
+		assert.Contains(t, Names(), "registry-new")
+	})
+
+	t.Run("Registered again", func(t *testing.T) {
+		New(WithName("registry-again"))
+		gormLogger := New(WithName("registry-again"))
+
+		actual, ok := Get("registry-again")
+
+		require.True(t, ok)
+		assert.Same(t, gormLogger, actual)
+	})
+
+	t.Run("Renamed copy", func(t *testing.T) {
+		base, err := NewE(WithName("registry-base"))
+		require.NoError(t, err)
+
+		replica := base.With(WithName("registry-replica"))
+		base.With(WithTraceAll())
+
+		actual, ok := Get("registry-replica")
+		require.True(t, ok)
+		assert.Same(t, replica, actual)
+		actual, ok = Get("registry-base")
+		require.True(t, ok)
+		assert.Same(t, base, actual)
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		_, err := NewE(WithName("registry-invalid"), WithSamplingRate(2))
+		require.ErrorIs(t, err, ErrInvalidOption)
+
+		_, ok := Get("registry-invalid")
+		assert.False(t, ok)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, ok := Get("registry-unknown")
+		assert.False(t, ok)
+	})
+}
+
+func TestNames(t *testing.T) {
+	New(WithName("registry-names-b"))
+	New(WithName("registry-names-a"))
+
+	names := Names()
+
+	assert.IsIncreasing(t, names)
+	assert.Subset(t, names, []string{"registry-names-a", "registry-names-b"})
+}
@@ -0,0 +1,113 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The keys of the resource attributes, following the OpenTelemetry semantic conventions, see WithResource
+const (
+	ServiceNameField           = "service.name"
+	ServiceVersionField        = "service.version"
+	DeploymentEnvironmentField = "deployment.environment"
+	HostNameField              = "host.name"
+	ProcessPIDField            = "process.pid"
+	K8sPodNameField            = "k8s.pod.name"
+	K8sNamespaceNameField      = "k8s.namespace.name"
+	K8sNodeNameField           = "k8s.node.name"
+)
+
+// The environment variables of the OpenTelemetry SDKs describing the resource, see WithResourceFromEnv
+const (
+	envResourceAttributes = "OTEL_RESOURCE_ATTRIBUTES"
+	envServiceName        = "OTEL_SERVICE_NAME"
+)
+
+// kubernetesEnv are the environment variables set by the downward API of Kubernetes, by resource attribute,
+// see WithKubernetesMetadata
+var kubernetesEnv = []struct{ key, variable string }{
+	{key: K8sPodNameField, variable: "POD_NAME"},
+	{key: K8sNamespaceNameField, variable: "POD_NAMESPACE"},
+	{key: K8sNodeNameField, variable: "NODE_NAME"},
+}
+
+// hostInfo returns the attributes of the host name and of the process ID, computed once, see WithHostInfo
+var hostInfo = sync.OnceValue(func() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 2)
+	if hostname, err := os.Hostname(); err == nil {
+		attrs = append(attrs, slog.String(HostNameField, hostname))
+	}
+	return append(attrs, slog.Int(ProcessPIDField, os.Getpid()))
+})
+
+// setResource sets the resource attribute, replacing the previous value of its key
+func (l *logger) setResource(attr slog.Attr) {
+	for i := range l.resource {
+		if l.resource[i].Key == attr.Key {
+			l.resource[i].Value = attr.Value
+			return
+		}
+	}
+	l.resource = append(l.resource, attr)
+}
+
+// withResource returns the handler with the resource attributes, if any, except those overridden by the
+// context attributes, see WithResource and ConflictOverridden
+func (l logger) withResource(handler slog.Handler) slog.Handler {
+	resource, _ := l.splitResource()
+	if handler == nil || len(resource) == 0 {
+		return handler
+	}
+	return handler.WithAttrs(l.convertKeys(resource))
+}
+
+// parseResourceAttributes parses the resource attributes of OTEL_RESOURCE_ATTRIBUTES, a list of key=value
+// separated by commas whose values are percent-encoded, returning the entries which cannot be parsed apart
+func parseResourceAttributes(value string) (attrs []slog.Attr, invalid []string) {
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			invalid = append(invalid, entry)
+			continue
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(raw))
+		if err != nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		attrs = append(attrs, slog.String(key, decoded))
+	}
+	return attrs, invalid
+}
+
+// resourceFromEnv sets the resource attributes defined by the environment variables of the OpenTelemetry
+// SDKs, OTEL_SERVICE_NAME taking precedence over the service.name of OTEL_RESOURCE_ATTRIBUTES
+func (l *logger) resourceFromEnv() {
+	attrs, invalid := parseResourceAttributes(os.Getenv(envResourceAttributes))
+	for _, entry := range invalid {
+		l.invalidOption("invalid entry %q of %s", entry, envResourceAttributes)
+	}
+	for _, attr := range attrs {
+		l.setResource(attr)
+	}
+	if name := os.Getenv(envServiceName); name != "" {
+		l.setResource(slog.String(ServiceNameField, name))
+	}
+}
+
+// kubernetesFromEnv sets the resource attributes of the pod read from the environment variables of the
+// downward API, the missing ones being skipped
+func (l *logger) kubernetesFromEnv() {
+	for _, env := range kubernetesEnv {
+		if value := os.Getenv(env.variable); value != "" {
+			l.setResource(slog.String(env.key, value))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package slogGorm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Resource(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM orders", 1
+	}
+
+	// jsonLines decodes the records written by the JSON handler
+	jsonLines := func(t *testing.T, buf *bytes.Buffer) []map[string]any {
+		var records []map[string]any
+		decoder := json.NewDecoder(buf)
+		for decoder.More() {
+			var record map[string]any
+			require.NoError(t, decoder.Decode(&record))
+			records = append(records, record)
+		}
+		return records
+	}
+
+	t.Run("Every record", func(t *testing.T) {
+		var buf bytes.Buffer
+		gormLogger := New(
+			WithHandler(slog.NewJSONHandler(&buf, nil)),
+			WithResource("orders", "v1.2.0", "prod"),
+			WithTraceAll(),
+		)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		records := jsonLines(t, &buf)
+		require.Len(t, records, 2)
+		for _, record := range records {
+			assert.Equal(t, "orders", record[ServiceNameField])
+			assert.Equal(t, "v1.2.0", record[ServiceVersionField])
+			assert.Equal(t, "prod", record[DeploymentEnvironmentField])
+		}
+	})
+
+	t.Run("Empty values", func(t *testing.T) {
+		var buf bytes.Buffer
+		gormLogger := New(WithHandler(slog.NewJSONHandler(&buf, nil)), WithResource("orders", "", ""), WithTraceAll())
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		records := jsonLines(t, &buf)
+		require.Len(t, records, 1)
+		assert.Equal(t, "orders", records[0][ServiceNameField])
+		assert.NotContains(t, records[0], ServiceVersionField)
+		assert.NotContains(t, records[0], DeploymentEnvironmentField)
+	})
+
+	t.Run("Derived logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		base := New(WithHandler(slog.NewJSONHandler(&buf, nil)), WithResource("orders", "v1.2.0", ""), WithTraceAll())
+
+		base.With(WithResource("", "", "staging")).Trace(context.Background(), time.Now(), fc, nil)
+		base.Trace(context.Background(), time.Now(), fc, nil)
+
+		records := jsonLines(t, &buf)
+		require.Len(t, records, 2)
+		assert.Equal(t, "staging", records[0][DeploymentEnvironmentField])
+		assert.Equal(t, "v1.2.0", records[0][ServiceVersionField])
+		assert.NotContains(t, records[1], DeploymentEnvironmentField)
+	})
+
+	t.Run("Host info", func(t *testing.T) {
+		var buf bytes.Buffer
+		gormLogger := New(WithHandler(slog.NewJSONHandler(&buf, nil)), WithHostInfo(), WithTraceAll())
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		records := jsonLines(t, &buf)
+		require.Len(t, records, 1)
+		assert.Contains(t, records[0], HostNameField)
+		assert.Equal(t, float64(os.Getpid()), records[0][ProcessPIDField])
+	})
+
+	t.Run("Kubernetes metadata", func(t *testing.T) {
+		t.Setenv("POD_NAME", "orders-7d9f-x2k4")
+		t.Setenv("POD_NAMESPACE", "checkout")
+		t.Setenv("NODE_NAME", "node-3")
+		var buf bytes.Buffer
+		gormLogger := New(WithHandler(slog.NewJSONHandler(&buf, nil)), WithKubernetesMetadata(), WithTraceAll())
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		records := jsonLines(t, &buf)
+		require.Len(t, records, 1)
+		assert.Equal(t, "orders-7d9f-x2k4", records[0][K8sPodNameField])
+		assert.Equal(t, "checkout", records[0][K8sNamespaceNameField])
+		assert.Equal(t, "node-3", records[0][K8sNodeNameField])
+	})
+
+	t.Run("Fallback handler", func(t *testing.T) {
+		var buf bytes.Buffer
+		gormLogger := New(
+			WithHandler(failingHandler{}),
+			WithFallbackHandler(slog.NewJSONHandler(&buf, nil)),
+			WithResource("orders", "", ""),
+			WithTraceAll(),
+		)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		records := jsonLines(t, &buf)
+		require.Len(t, records, 1)
+		assert.Equal(t, "orders", records[0][ServiceNameField])
+	})
+}
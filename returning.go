@@ -0,0 +1,121 @@
+package slogGorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReturningRedaction defines how the RETURNING clauses of the SQL queries logged are redacted, see
+// WithReturningRedaction
+type ReturningRedaction int
+
+const (
+	// ReturningKept logs the RETURNING clauses as is (default)
+	ReturningKept ReturningRedaction = iota
+	// ReturningStripped removes the RETURNING clauses, e.g. "INSERT INTO users (name) VALUES ('john')"
+	ReturningStripped
+	// ReturningMasked replaces the expressions of the RETURNING clauses, e.g. "... VALUES ('john') RETURNING ?"
+	ReturningMasked
+)
+
+// returningRedactionNames are the names of the redactions of the RETURNING clauses
+var returningRedactionNames = map[ReturningRedaction]string{
+	ReturningKept:     "kept",
+	ReturningStripped: "stripped",
+	ReturningMasked:   "masked",
+}
+
+// String returns the name of the redaction of the RETURNING clauses
+func (r ReturningRedaction) String() string {
+	if name, ok := returningRedactionNames[r]; ok {
+		return name
+	}
+	return "ReturningRedaction(" + strconv.Itoa(int(r)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (r ReturningRedaction) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (r *ReturningRedaction) UnmarshalText(text []byte) error {
+	for redaction, name := range returningRedactionNames {
+		if strings.EqualFold(string(text), name) {
+			*r = redaction
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown RETURNING redaction %q", text)
+}
+
+// redactReturning redacts the RETURNING clauses of the SQL query, see WithReturningRedaction
+func (l logger) redactReturning(sql string) string {
+	if l.returningRedaction == ReturningKept {
+		return sql
+	}
+	return redactReturning(sql, l.returningRedaction)
+}
+
+// redactReturning strips or masks the RETURNING clauses of the statements of the SQL query, which end with
+// the statement. The RETURNING keywords in the strings, the comments and the parentheses are ignored.
+func redactReturning(sql string, redaction ReturningRedaction) string {
+	var (
+		b         strings.Builder
+		offset    int  // offset of the current token
+		written   int  // offset of the SQL query written so far
+		space     int  // offset of the whitespaces preceding the current token, -1 if none
+		depth     int  // depth of the parentheses
+		returning bool // whether the current token is in a RETURNING clause
+	)
+	space = -1
+	tokenize(sql, func(t token) bool {
+		start := offset
+		offset += len(t.text)
+
+		switch {
+		case t.kind == tokenSpace || t.kind == tokenComment:
+			if space < 0 {
+				space = start
+			}
+			return true
+		case t.kind == tokenPunctuation:
+			for i := 0; i < len(t.text); i++ {
+				switch t.text[i] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				case ';':
+					if returning && depth <= 0 {
+						// The clause ends with its statement
+						returning, written = false, start+i
+					}
+				}
+			}
+		case !returning && depth == 0 && t.isKeyword("RETURNING"):
+			returning = true
+			clause := start
+			if space >= 0 {
+				clause = space
+			}
+			b.WriteString(sql[written:clause])
+			if redaction == ReturningMasked {
+				b.WriteString(sql[clause:offset])
+				b.WriteString(" ?")
+			}
+			written = len(sql)
+		}
+		space = -1
+		return true
+	})
+	if written == 0 {
+		// No RETURNING clause
+		return sql
+	}
+	if written < len(sql) {
+		b.WriteString(sql[written:])
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_redactReturning(t *testing.T) {
+	tests := []struct {
+		sql      string
+		stripped string
+		masked   string
+	}{
+		{
+			sql:      "INSERT INTO users (name) VALUES ('john') RETURNING id, lower(email)",
+			stripped: "INSERT INTO users (name) VALUES ('john')",
+			masked:   "INSERT INTO users (name) VALUES ('john') RETURNING ?",
+		},
+		{
+			sql:      "UPDATE users SET name = 'returning' WHERE id = 1 RETURNING *; DELETE FROM sessions RETURNING token",
+			stripped: "UPDATE users SET name = 'returning' WHERE id = 1; DELETE FROM sessions",
+			masked:   "UPDATE users SET name = 'returning' WHERE id = 1 RETURNING ?; DELETE FROM sessions RETURNING ?",
+		},
+		{
+			sql:      "WITH deleted AS (DELETE FROM sessions RETURNING id) SELECT count(*) FROM deleted",
+			stripped: "WITH deleted AS (DELETE FROM sessions RETURNING id) SELECT count(*) FROM deleted",
+			masked:   "WITH deleted AS (DELETE FROM sessions RETURNING id) SELECT count(*) FROM deleted",
+		},
+		{
+			sql:      "SELECT * FROM users /* RETURNING */",
+			stripped: "SELECT * FROM users /* RETURNING */",
+			masked:   "SELECT * FROM users /* RETURNING */",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.stripped, redactReturning(tt.sql, ReturningStripped))
+			assert.Equal(t, tt.masked, redactReturning(tt.sql, ReturningMasked))
+		})
+	}
+}
+
+func Test_logger_ReturningRedaction(t *testing.T) {
+	fc := func() (string, int64) {
+		return "INSERT INTO users (name) VALUES ('john') RETURNING id, email", 1
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{name: "Kept", want: "INSERT INTO users (name) VALUES ('john') RETURNING id, email"},
+		{name: "Stripped", options: []Option{WithReturningRedaction(ReturningStripped)}, want: "INSERT INTO users (name) VALUES ('john')"},
+		{name: "Masked", options: []Option{WithReturningRedaction(ReturningMasked)}, want: "INSERT INTO users (name) VALUES ('john') RETURNING ?"},
+		{
+			name:    "No values",
+			options: []Option{WithReturningRedaction(ReturningMasked), WithNoValues()},
+			want:    "INSERT INTO users (name) VALUES (?) RETURNING ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver, gormLogger := getReceiverAndLogger(append(tt.options, WithTraceAll()))
+
+			gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+			require.Equal(t, 1, receiver.Len())
+			r := resolveRecord(*receiver.Record)
+			assertHasAttr(t, &r, slog.String(QueryField, tt.want))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package slogGorm
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+)
+
+// sampledLogTypes are the log types of the SQL queries whose sampling rate can be set, see WithSamplingRates
+var sampledLogTypes = []LogType{
+	DefaultLogType, ErrorLogType, SlowQueryLogType, LargeQueryLogType, LargeResultLogType,
+	DangerousWriteLogType, SavepointLogType, DDLLogType,
+}
+
+// sampled reports whether the record of the given type is kept by the sampling, with the sampling rate applied.
+// Only the SQL messages traced are sampled by default, never the errors nor the slow queries, see WithSamplingRates.
+func (l logger) sampled(ctx context.Context, logType LogType) (float64, bool) {
+	rate := l.samplingRateOf(ctx, logType)
+	return rate, rate >= 1 || rand.Float64() < rate
+}
+
+// samplingRateOf returns the sampling rate of the log type: for the SQL messages traced, the sampling rate of
+// the tenant of the context (see WithTenantSamplingRates), then the one of the log type (see WithSamplingRates),
+// then the sampling rate of the logger
+func (l logger) samplingRateOf(ctx context.Context, logType LogType) float64 {
+	if logType == DefaultLogType && len(l.tenantSamplingRates) > 0 {
+		if tenant, ok := l.tenantOf(ctx); ok {
+			if rate, ok := l.tenantSamplingRates[tenant]; ok {
+				return rate
+			}
+		}
+	}
+	if rate, ok := l.logTypeSamplingRates[logType]; ok {
+		return rate
+	}
+	if logType != DefaultLogType {
+		return 1
+	}
+	return l.samplingRate
+}
+
+// isSampledLogType reports whether the sampling rate of the log type can be set
+func isSampledLogType(logType LogType) bool {
+	return slices.Contains(sampledLogTypes, logType)
+}
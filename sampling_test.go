@@ -0,0 +1,103 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Trace_Sampling(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	t.Run("No SQL message sampled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSamplingRate(0)})
+
+		for i := 0; i < 10; i++ {
+			gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		}
+		assert.Equal(t, 0, receiver.Len())
+	})
+
+	t.Run("All SQL messages sampled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSamplingRate(1)})
+
+		for i := 0; i < 10; i++ {
+			gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		}
+		assert.Equal(t, 10, receiver.Len())
+	})
+
+	t.Run("Errors and slow queries are not sampled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithSlowThreshold(time.Millisecond),
+			WithSamplingRate(0),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, errors.New("awesome error"))
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+		assert.Equal(t, 2, receiver.Len())
+	})
+
+	t.Run("Log type sampling rates", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithSlowThreshold(time.Millisecond),
+			WithSamplingRate(0),
+			WithSamplingRates(map[LogType]float64{ErrorLogType: 0, DefaultLogType: 1}),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, errors.New("awesome error"))
+		assert.Equal(t, 0, receiver.Len())
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+		require.Equal(t, 1, receiver.Len())
+		assertNoAttr(t, receiver.Record, SamplingRateField)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+		assert.Equal(t, 2, receiver.Len())
+		assert.Equal(t, uint64(1), gormLogger.Stats().SampledOut)
+	})
+
+	t.Run("Sampling rate attribute", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Millisecond),
+			WithSamplingRates(map[LogType]float64{SlowQueryLogType: 0.5}),
+		})
+
+		for receiver.Len() == 0 {
+			gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+		}
+		assertHasAttr(t, receiver.Record, slog.Float64(SamplingRateField, 0.5))
+	})
+
+	t.Run("Tenant sampling rates", func(t *testing.T) {
+		type tenantKey struct{}
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithTenant(func(ctx context.Context) (string, bool) {
+				tenant, ok := ctx.Value(tenantKey{}).(string)
+				return tenant, ok
+			}),
+			WithTenantSamplingRates(map[string]float64{"noisy": 0}),
+		})
+		noisy := context.WithValue(context.Background(), tenantKey{}, "noisy")
+		other := context.WithValue(context.Background(), tenantKey{}, "other")
+
+		for i := 0; i < 10; i++ {
+			gormLogger.Trace(noisy, time.Now(), fc, nil)
+		}
+		gormLogger.Trace(other, time.Now(), fc, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(TenantField, "other"))
+		assert.Equal(t, uint64(10), gormLogger.Stats().SampledOut)
+	})
+}
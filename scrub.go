@@ -0,0 +1,69 @@
+package slogGorm
+
+import (
+	"log/slog"
+)
+
+// Scrubber removes the personal data from the records before they are logged, see WithScrubber
+type Scrubber interface {
+	// Scrub returns the SQL query and the attributes of the record without personal data. The SQL query is
+	// empty for the records which don't describe a query (e.g. the transactions), and the attributes don't
+	// include the SQL query. The attributes can be modified in place.
+	Scrub(sql string, attrs []slog.Attr) (string, []slog.Attr)
+}
+
+// ScrubberFunc is a function implementing Scrubber
+type ScrubberFunc func(sql string, attrs []slog.Attr) (string, []slog.Attr)
+
+// Scrub implements Scrubber
+func (f ScrubberFunc) Scrub(sql string, attrs []slog.Attr) (string, []slog.Attr) {
+	return f(sql, attrs)
+}
+
+// scrub scrubs the SQL query, if any, and the other attributes of the record. The SQL query is
+// resolved, so that its lazy value is the scrubbed SQL query.
+func (l logger) scrub(query *lazyQuery, attrs []slog.Attr) []slog.Attr {
+	if l.scrubber == nil {
+		return attrs
+	}
+
+	queryIndex := -1
+	others := make([]slog.Attr, 0, len(attrs))
+	for i, attr := range attrs {
+		if query != nil && attr.Key == QueryField && queryIndex < 0 {
+			queryIndex = i
+			continue
+		}
+		others = append(others, attr)
+	}
+
+	var sql string
+	if query != nil {
+		sql = query.SQL()
+	}
+	sql, others = l.scrubber.Scrub(sql, others)
+	if query != nil {
+		query.sql = sql
+	}
+
+	if queryIndex < 0 {
+		return append(attrs[:0], others...)
+	}
+	queryAttr := attrs[queryIndex]
+	attrs = attrs[:0]
+	if queryIndex > len(others) {
+		queryIndex = len(others)
+	}
+	attrs = append(attrs, others[:queryIndex]...)
+	attrs = append(attrs, queryAttr)
+	return append(attrs, others[queryIndex:]...)
+}
+
+// scrubSQL scrubs the given SQL query only
+func (l logger) scrubSQL(sql string) string {
+	if l.scrubber == nil {
+		return sql
+	}
+	sql, _ = l.scrubber.Scrub(sql, nil)
+	return sql
+}
@@ -0,0 +1,93 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emailScrubber masks the email addresses of the SQL queries and of the string attributes
+var emailScrubber = ScrubberFunc(func(sql string, attrs []slog.Attr) (string, []slog.Attr) {
+	email := regexp.MustCompile(`[\w.]+@[\w.]+`)
+	for i, attr := range attrs {
+		if attr.Value.Kind() == slog.KindString {
+			attrs[i].Value = slog.StringValue(email.ReplaceAllString(attr.Value.String(), "***"))
+		}
+	}
+	return email.ReplaceAllString(sql, "***"), attrs
+})
+
+func Test_logger_Scrubber(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user", "john@example.com")
+	options := []Option{
+		WithTraceAll(),
+		WithScrubber(emailScrubber),
+		WithContextValue("user", "user"),
+		WithMessage(DefaultLogType, "executed {query}"),
+	}
+
+	t.Run("Trace", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "SELECT * FROM users WHERE email = 'john@example.com'", 1
+		}, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT * FROM users WHERE email = '***'"))
+		assertHasAttr(t, &resolved, slog.String("user", "***"))
+		assertHasAttr(t, &resolved, slog.Int64(RowsField, 1))
+		assert.Equal(t, "executed SELECT * FROM users WHERE email = '***'", resolved.Message)
+
+		// The SQL query keeps its position
+		var keys []string
+		resolved.Attrs(func(attr slog.Attr) bool {
+			keys = append(keys, attr.Key)
+			return true
+		})
+		assert.Equal(t, []string{QueryField, DurationField, RowsField, SourceField, "user"}, keys)
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+
+		gormLogger.Info(ctx, "an info message")
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String("user", "***"))
+	})
+
+	t.Run("Full SQL query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithDebugFullQueries(),
+			WithScrubber(emailScrubber),
+			WithAsync(8),
+		})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users WHERE email = ?", "john@example.com").Error)
+		require.NoError(t, gormLogger.Close())
+
+		require.Equal(t, 2, receiver.Len())
+		assertHasAttr(t, &receiver.Records[1], slog.String(QueryField, `DELETE FROM users WHERE email = "***"`))
+	})
+}
+
+func Test_logger_scrub(t *testing.T) {
+	_, gormLogger := getReceiverAndLogger([]Option{
+		WithScrubber(ScrubberFunc(func(sql string, attrs []slog.Attr) (string, []slog.Attr) {
+			return sql, append(attrs, slog.Bool("scrubbed", true))
+		})),
+	})
+
+	attrs := gormLogger.scrub(nil, []slog.Attr{slog.String("key", "value")})
+
+	assert.Equal(t, []slog.Attr{slog.String("key", "value"), slog.Bool("scrubbed", true)}, attrs)
+}
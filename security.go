@@ -0,0 +1,128 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// The anomalies of the SQL queries reported by WithSecurityDetection
+const (
+	// TautologyAnomaly is a condition always true like "OR 1=1"
+	TautologyAnomaly = "tautology"
+	// StackedStatementsAnomaly is a statement following another one, like "SELECT ...; DROP TABLE users"
+	StackedStatementsAnomaly = "stacked_statements"
+	// InjectionPayloadAnomaly is a string literal containing a SQL injection payload, like "' OR '1'='1"
+	InjectionPayloadAnomaly = "injection_payload"
+)
+
+// injectionPayloads are the patterns of the SQL injection payloads in the string literals
+var injectionPayloads = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)['"]\s*(or|and)\s+['"\w]+\s*(=|like)\s*['"\w]+`),
+	regexp.MustCompile(`(?i)\bor\s+1\s*=\s*1\b`),
+	regexp.MustCompile(`(?i)\bunion\b(\s+all)?\s+select\b`),
+	regexp.MustCompile(`(?i);\s*(drop|delete|insert|update|alter|truncate|create|exec|shutdown)\b`),
+	regexp.MustCompile(`(?i)\b(sleep|pg_sleep|benchmark)\s*\(|\bwaitfor\s+delay\b`),
+}
+
+// detectAnomalies returns the anomalies of the SQL query, suspicious of a SQL injection, in the order of
+// the constants
+func detectAnomalies(sql string) []string {
+	var (
+		tautology, stacked, payload bool
+		previous                    []token // the last significant tokens
+		ended                       bool    // whether a statement ended with a semicolon
+	)
+
+	tokenize(sql, func(t token) bool {
+		switch t.kind {
+		case tokenSpace, tokenComment:
+			return true
+		case tokenString, tokenDoubleQuoted:
+			if !payload {
+				for _, pattern := range injectionPayloads {
+					if pattern.MatchString(t.text) {
+						payload = true
+						break
+					}
+				}
+			}
+		}
+
+		if ended {
+			stacked = true
+		}
+		ended = t.text == ";"
+
+		// OR <value> = <value>, the values being the same literal
+		previous = append(previous, t)
+		if n := len(previous); n >= 4 {
+			or, left, operator, right := previous[n-4], previous[n-3], previous[n-2], previous[n-1]
+			if or.isKeyword("OR") && operator.text == "=" && isLiteral(left) && left.kind == right.kind && left.text == right.text {
+				tautology = true
+			}
+			previous = previous[n-3:]
+		}
+		return true
+	})
+
+	var anomalies []string
+	if tautology {
+		anomalies = append(anomalies, TautologyAnomaly)
+	}
+	if stacked {
+		anomalies = append(anomalies, StackedStatementsAnomaly)
+	}
+	if payload {
+		anomalies = append(anomalies, InjectionPayloadAnomaly)
+	}
+	return anomalies
+}
+
+// isLiteral reports whether the token is a literal value
+func isLiteral(t token) bool {
+	return t.kind == tokenString || t.kind == tokenNumber
+}
+
+// detectSecurityAnomalies logs the SQL query with the SecurityLogType level if it is suspicious of a SQL
+// injection, whatever the tracing of the SQL queries
+func (l logger) detectSecurityAnomalies(ctx context.Context, elapsed time.Duration, query *lazyQuery, source *sourceValuer, txIndex int64) {
+	level := l.logLevel[SecurityLogType]
+	if l.silenced(SecurityLogType) || !l.enabled(ctx, level) {
+		return
+	}
+	anomalies := detectAnomalies(query.SQL())
+	if len(anomalies) == 0 {
+		return
+	}
+
+	// The record has its own query, as the scrubber replaces the SQL query of the query logged
+	suspicious := newLazyQuery(func() (string, int64) {
+		return l.sanitizeSQL(query.SQL()), query.Rows()
+	})
+
+	attributes := getAttrs()
+	defer putAttrs(attributes)
+	*attributes = append(*attributes,
+		slog.Any(AnomaliesField, anomalies),
+		slog.Any(QueryField, sqlValuer{suspicious}),
+		slog.Duration(DurationField, elapsed),
+	)
+	*attributes = l.appendSourceAttribute(*attributes, source)
+	*attributes = appendTransactionAttributes(txIndex, *attributes)
+	*attributes = l.appendContextAttributes(ctx, *attributes)
+	*attributes = l.scrub(suspicious, *attributes)
+	l.verifyRedaction(ctx, SecurityLogType, suspicious)
+
+	msg, custom := l.formatMessage(ctx, messageEvent{
+		logType: SecurityLogType,
+		query:   suspicious,
+		elapsed: elapsed,
+	})
+	if !custom {
+		msg = SecurityMessage
+	}
+
+	l.logAttrs(ctx, level, msg, l.applyPreset(ctx, SecurityLogType, level, suspicious, *attributes)...)
+}
@@ -0,0 +1,100 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_detectAnomalies(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{sql: "SELECT * FROM users WHERE name = 'john' AND id = 1"},
+		{sql: "SELECT * FROM users WHERE id IN (1, 2) OR name = 'john';"},
+		{sql: "SELECT * FROM users WHERE id = 1 OR 1=1", want: []string{TautologyAnomaly}},
+		{sql: "SELECT * FROM users WHERE name = '' OR 'a' = 'a'", want: []string{TautologyAnomaly}},
+		{sql: "SELECT * FROM users WHERE id = 1 OR 1 = 2"},
+		{sql: "SELECT * FROM users WHERE id = 1; DROP TABLE users", want: []string{StackedStatementsAnomaly}},
+		{sql: "SELECT * FROM users WHERE name = 'x'' OR ''1''=''1'", want: []string{InjectionPayloadAnomaly}},
+		{sql: `SELECT * FROM users WHERE name = "x' UNION ALL SELECT password FROM users --"`, want: []string{InjectionPayloadAnomaly}},
+		{sql: "SELECT * FROM users WHERE name = 'x''; DROP TABLE users; --'", want: []string{InjectionPayloadAnomaly}},
+		{sql: "SELECT * FROM users WHERE name = 'x'' AND pg_sleep(10) --'", want: []string{InjectionPayloadAnomaly}},
+		{sql: "SELECT * FROM users WHERE name = 'the union of selected friends'"},
+		{
+			sql:  "SELECT * FROM users WHERE name = 'a' OR 1=1; DELETE FROM users WHERE name = 'x OR 1=1'",
+			want: []string{TautologyAnomaly, StackedStatementsAnomaly, InjectionPayloadAnomaly},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectAnomalies(tt.sql))
+		})
+	}
+}
+
+func Test_logger_SecurityDetection(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 0 }, nil)
+	}
+
+	t.Run("Suspicious query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithSecurityDetection()})
+
+		trace(gormLogger, "SELECT * FROM users WHERE id = 1 OR 1=1")
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assert.Equal(t, SecurityMessage, receiver.Record.Message)
+		assert.Equal(t, []string{TautologyAnomaly}, anomaliesOf(*receiver.Record))
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(QueryField, "SELECT * FROM users WHERE id = 1 OR 1=1"))
+		assert.Contains(t, sourceOf(resolved), "security_test.go:")
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithSecurityDetection()})
+
+		trace(gormLogger, "SELECT * FROM users WHERE id = 1")
+
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Traced", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithSecurityDetection(), WithTraceAll(), WithNoValues()})
+
+		trace(gormLogger, "SELECT * FROM users WHERE name = 'x'' OR ''1''=''1'")
+
+		require.Equal(t, 2, receiver.Len())
+		security := resolveRecord(receiver.Records[0])
+		assert.Equal(t, []string{InjectionPayloadAnomaly}, anomaliesOf(security))
+		assertHasAttr(t, &security, slog.String(QueryField, "SELECT * FROM users WHERE name = ?"))
+		assert.Equal(t, slog.LevelInfo, receiver.Records[1].Level)
+	})
+
+	t.Run("Disabled level", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithSecurityDetection(), WithMinLevel(slog.LevelError)})
+
+		trace(gormLogger, "SELECT * FROM users WHERE id = 1 OR 1=1")
+
+		assert.Nil(t, receiver.Record)
+	})
+}
+
+// anomaliesOf returns the anomalies logged by the record
+func anomaliesOf(r slog.Record) []string {
+	var anomalies []string
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == AnomaliesField {
+			anomalies, _ = attr.Value.Any().([]string)
+		}
+		return true
+	})
+	return anomalies
+}
@@ -0,0 +1,57 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// The keys of the logical table and of the physical shard table of the queries on the sharded tables, see
+// WithShardedTables
+const (
+	LogicalTableField = "logical_table"
+	ShardTableField   = "shard_table"
+)
+
+// shardOf returns the logical table of the given main table of a query, and the table itself if it is one of its
+// shards, i.e. the logical table followed by an underscore and a suffix of digits and underscores (e.g. orders_01
+// or orders_2024_01), false if it is not a sharded table
+func (l logger) shardOf(table string) (logical, shard string, ok bool) {
+	for _, logical := range l.shardedTables {
+		if strings.EqualFold(table, logical) {
+			return logical, "", true
+		}
+		if len(table) > len(logical)+1 && strings.EqualFold(table[:len(logical)], logical) &&
+			table[len(logical)] == '_' && isShardSuffix(table[len(logical)+1:]) {
+			return logical, table, true
+		}
+	}
+	return "", "", false
+}
+
+// isShardSuffix reports whether the suffix of a shard table is made of digits and underscores, with a digit at least
+func isShardSuffix(suffix string) bool {
+	digit := false
+	for _, c := range suffix {
+		switch {
+		case c >= '0' && c <= '9':
+			digit = true
+		case c != '_':
+			return false
+		}
+	}
+	return digit
+}
+
+// appendShardAttributes adds the logical table and the shard table of the query, if its main table is sharded,
+// the queries on the logical table rewritten by the sharding plugin lacking the shard table
+func (l logger) appendShardAttributes(args []slog.Attr, query *lazyQuery) []slog.Attr {
+	logical, shard, ok := l.shardOf(query.Table())
+	if !ok {
+		return args
+	}
+	args = append(args, slog.String(LogicalTableField, logical))
+	if shard != "" {
+		args = append(args, slog.String(ShardTableField, shard))
+	}
+	return args
+}
@@ -0,0 +1,73 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_ShardedTables(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Shard table", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithShardedTables("orders"), WithTraceAll()})
+
+		trace(gormLogger, "SELECT * FROM orders_03 WHERE user_id = 42")
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(LogicalTableField, "orders"))
+		assertHasAttr(t, receiver.Record, slog.String(ShardTableField, "orders_03"))
+	})
+
+	t.Run("Logical table", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithShardedTables("orders"), WithTraceAll()})
+
+		trace(gormLogger, "SELECT * FROM orders WHERE user_id = 42")
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(LogicalTableField, "orders"))
+		assertNoAttr(t, receiver.Record, ShardTableField)
+	})
+
+	t.Run("Other table", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithShardedTables("orders"), WithTraceAll()})
+
+		trace(gormLogger, "SELECT * FROM orders_archive")
+
+		require.NotNil(t, receiver.Record)
+		assertNoAttr(t, receiver.Record, LogicalTableField)
+		assertNoAttr(t, receiver.Record, ShardTableField)
+	})
+}
+
+func Test_logger_shardOf(t *testing.T) {
+	l := logger{shardedTables: []string{"orders", "order_items"}}
+	tests := []struct {
+		table, logical, shard string
+		ok                    bool
+	}{
+		{table: "orders", logical: "orders", ok: true},
+		{table: "orders_01", logical: "orders", shard: "orders_01", ok: true},
+		{table: "ORDERS_2024_01", logical: "orders", shard: "ORDERS_2024_01", ok: true},
+		{table: "order_items_7", logical: "order_items", shard: "order_items_7", ok: true},
+		{table: "orders_", ok: false},
+		{table: "orders_old", ok: false},
+		{table: "orders01", ok: false},
+		{table: "users_01", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			logical, shard, ok := l.shardOf(tt.table)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.logical, logical)
+			assert.Equal(t, tt.shard, shard)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package slogGorm
+
+import "slices"
+
+// unsilenceableLogTypes are the log types which cannot be silenced: the records dropped by the asynchronous mode,
+// which would hide the loss of records, and the startup summary, logged on demand
+var unsilenceableLogTypes = []LogType{AsyncDropLogType, StartupLogType}
+
+// silenced reports whether the records of the log type are silenced, see WithSilencedLogTypes
+func (l logger) silenced(logType LogType) bool {
+	return len(l.silencedLogTypes) > 0 && slices.Contains(l.silencedLogTypes, logType)
+}
@@ -0,0 +1,66 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_SilencedLogTypes(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	trace := func(l *logger, elapsed time.Duration, err error) {
+		l.Trace(context.Background(), now.Add(-elapsed), func() (string, int64) { return "UPDATE users SET name = 'john'", 1 }, err)
+	}
+
+	t.Run("Silenced", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(fixedClock{now: now}),
+			WithSlowThreshold(time.Second),
+			WithSilencedLogTypes(SlowQueryLogType, GormWarnLogType),
+			WithQueryStats(),
+		})
+
+		trace(gormLogger, 2*time.Second, nil)
+		gormLogger.Warn(context.Background(), "a warn message")
+		assert.Equal(t, 0, receiver.Len())
+		require.Len(t, gormLogger.Stats().Queries, 1, "the statistics are not silenced")
+
+		trace(gormLogger, 2*time.Second, errors.New("awesome error"))
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, "awesome error", receiver.Record.Message)
+	})
+
+	t.Run("Decision", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(fixedClock{now: now}),
+			WithSlowThreshold(time.Second),
+			WithSilencedLogTypes(SlowQueryLogType),
+			WithDecisionDebug(),
+		})
+
+		trace(gormLogger, 2*time.Second, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(DecisionField, decisionSilenced))
+		assertHasAttr(t, receiver.Record, slog.String(LogTypeField, string(SlowQueryLogType)))
+	})
+
+	t.Run("Logged again", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(fixedClock{now: now}),
+			WithSlowThreshold(time.Second),
+			WithSilencedLogTypes(SlowQueryLogType),
+		})
+
+		gormLogger.Apply(WithSilencedLogTypes())
+		trace(gormLogger, 2*time.Second, nil)
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+	})
+}
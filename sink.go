@@ -0,0 +1,113 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// QueryEvent is a query traced by the logger, consumed by the sinks (see WithSink)
+type QueryEvent struct {
+	// Time is the time the query started
+	Time time.Time
+	// SQL is the SQL query, redacted like the records
+	SQL       string
+	Operation string
+	Table     string
+	Tables    []string // all the tables referenced by the SQL query, including its joins and subqueries
+	Rows      int64
+	Duration  time.Duration
+	// Slow reports whether the query is slow, see WithSlowThreshold
+	Slow bool
+	// Err is the error of the query, if any, without values with WithNoValues
+	Err error
+	// TxStmtIndex is the index of the statement in its transaction, zero outside of a transaction
+	TxStmtIndex int64
+	// Source is the file name and line number of the caller of gorm, empty without source field
+	Source string
+	// Attrs are the context attributes, see WithContextValue and WithContextFunc
+	Attrs []slog.Attr
+}
+
+// Sink consumes the queries traced by the logger as typed events, in addition to the slog records,
+// see WithSink
+type Sink interface {
+	// HandleQuery handles the event of a query. It is called synchronously by the query, so the slow
+	// sinks should buffer the events.
+	HandleQuery(ctx context.Context, event QueryEvent)
+}
+
+// SinkFunc is a function implementing Sink
+type SinkFunc func(ctx context.Context, event QueryEvent)
+
+// HandleQuery implements Sink
+func (f SinkFunc) HandleQuery(ctx context.Context, event QueryEvent) {
+	f(ctx, event)
+}
+
+// hasSinks reports whether a sink consumes the query events, including the slow query log,
+// the query log files, the query statistics, the tracer and the subscriptions
+func (l logger) hasSinks() bool {
+	return l.slowLog != nil || l.queryLogFile != nil || l.slowQueryLogFile != nil || l.queryStats != nil ||
+		l.routeStats != nil || l.jobStats != nil || l.tracer != nil || len(l.sinks) > 0 ||
+		(l.subscriptions != nil && l.subscriptions.active.Load() > 0)
+}
+
+// emitQueryEvent builds the event of the query and hands it to the sinks
+func (l logger) emitQueryEvent(ctx context.Context, begin time.Time, elapsed time.Duration, query *lazyQuery, source *sourceValuer, txIndex int64, err error) {
+	if l.noValues {
+		err = withoutValues(err)
+	}
+
+	// The attributes are not pooled, as the sinks may retain the event
+	attributes := l.appendContextAttributes(ctx, nil)
+	sql := l.sanitizeSQL(query.SQL())
+	if l.scrubber != nil {
+		sql, attributes = l.scrubber.Scrub(sql, attributes)
+	}
+
+	event := QueryEvent{
+		Time:        begin,
+		SQL:         sql,
+		Operation:   query.Operation(),
+		Table:       query.Table(),
+		Tables:      query.Tables(),
+		Rows:        query.Rows(),
+		Duration:    elapsed,
+		Slow:        l.slowThreshold != 0 && elapsed > l.slowThreshold,
+		Err:         err,
+		TxStmtIndex: txIndex,
+		Attrs:       attributes,
+	}
+	if source != nil {
+		event.Source = source.String()
+	}
+
+	if l.slowLog != nil {
+		l.slowLog.HandleQuery(ctx, event)
+	}
+	if l.queryLogFile != nil {
+		l.queryLogFile.HandleQuery(ctx, event)
+	}
+	if l.slowQueryLogFile != nil && event.Slow {
+		l.slowQueryLogFile.HandleQuery(ctx, event)
+	}
+	if l.queryStats != nil {
+		l.queryStats.HandleQuery(ctx, event)
+	}
+	if l.routeStats != nil {
+		l.routeStats.HandleQuery(ctx, event)
+	}
+	if l.jobStats != nil {
+		l.jobStats.HandleQuery(ctx, event)
+	}
+	if l.tracer != nil {
+		l.recordSpan(ctx, event)
+	}
+	for _, sink := range l.sinks {
+		sink.HandleQuery(ctx, event)
+	}
+	if l.subscriptions != nil {
+		l.subscriptions.HandleQuery(ctx, event)
+	}
+}
@@ -0,0 +1,77 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Sink(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey1, "42")
+	collect := func(events *[]QueryEvent) Sink {
+		return SinkFunc(func(_ context.Context, event QueryEvent) {
+			*events = append(*events, event)
+		})
+	}
+	errQuery := errors.New("duplicate key value violates unique constraint")
+
+	t.Run("Query events", func(t *testing.T) {
+		var events []QueryEvent
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithIgnoreTrace(),
+			WithSlowThreshold(10 * time.Millisecond),
+			WithContextValue("request_id", ctxKey1),
+			WithSink(collect(&events)),
+		})
+
+		begin := time.Now().Add(-time.Second)
+		gormLogger.Trace(ctx, begin, func() (string, int64) { return "UPDATE users SET name = 'john' WHERE id = 1", 1 }, errQuery)
+
+		assert.Nil(t, receiver.Record)
+		require.Len(t, events, 1)
+		event := events[0]
+		assert.Equal(t, begin, event.Time)
+		assert.Equal(t, "UPDATE users SET name = 'john' WHERE id = 1", event.SQL)
+		assert.Equal(t, "UPDATE", event.Operation)
+		assert.Equal(t, "users", event.Table)
+		assert.Equal(t, int64(1), event.Rows)
+		assert.GreaterOrEqual(t, event.Duration, time.Second)
+		assert.True(t, event.Slow)
+		assert.Equal(t, errQuery, event.Err)
+		assert.Contains(t, event.Source, "sink_test.go:")
+		require.Len(t, event.Attrs, 1)
+		assert.True(t, slog.String("request_id", "42").Equal(event.Attrs[0]))
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		var events []QueryEvent
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithNoValues(),
+			WithoutSourceField(),
+			WithSink(collect(&events)),
+		})
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT * FROM users WHERE name = 'john'", 1 }, errQuery)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, "SELECT * FROM users WHERE name = ?", events[0].SQL)
+		assert.Equal(t, "*errors.errorString", events[0].Err.Error())
+		assert.False(t, events[0].Slow)
+		assert.Empty(t, events[0].Source)
+	})
+
+	t.Run("Several sinks", func(t *testing.T) {
+		var first, second []QueryEvent
+		_, gormLogger := getReceiverAndLogger([]Option{WithSink(collect(&first), collect(&second))})
+
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+
+		assert.Len(t, first, 1)
+		assert.Len(t, second, 1)
+	})
+}
@@ -0,0 +1,106 @@
+// Package slogormgrpc seeds the context of the gRPC calls with their method and their request ID, logged by
+// slog-gorm with the SQL queries of the calls.
+//
+// The module of gRPC is not a dependency of slog-gorm: the interceptors call ContextWithRPC with the full method
+// of the call and its request ID, e.g. read from the x-request-id metadata:
+//
+//	func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+//		return handler(slogormgrpc.ContextWithRPC(ctx, info.FullMethod, requestID(ctx)), req)
+//	}
+//
+//	type contextStream struct {
+//		grpc.ServerStream
+//		ctx context.Context
+//	}
+//
+//	func (s contextStream) Context() context.Context { return s.ctx }
+//
+//	func streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+//		ctx := slogormgrpc.ContextWithRPC(ss.Context(), info.FullMethod, requestID(ss.Context()))
+//		return handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+//	}
+//
+//	func requestID(ctx context.Context) string {
+//		md, _ := metadata.FromIncomingContext(ctx)
+//		if ids := md.Get(slogormgrpc.RequestIDMetadata); len(ids) > 0 {
+//			return ids[0]
+//		}
+//		return ""
+//	}
+//
+// The SQL queries of the calls are then logged with their attributes:
+//
+//	gormLogger := slogGorm.New(slogormgrpc.LoggerOptions()...)
+//	// ... rpc.service=orders.v1.OrderService rpc.method=GetOrder request_id=4bf92f35
+package slogormgrpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+// The keys of the attributes logged by LoggerOptions, following the OpenTelemetry semantic conventions
+// for the service and the method
+const (
+	RequestIDField  = "request_id"
+	RPCServiceField = "rpc.service"
+	RPCMethodField  = "rpc.method"
+)
+
+// RequestIDMetadata is the key of the metadata conventionally carrying the request ID of the gRPC calls
+const RequestIDMetadata = "x-request-id"
+
+// contextKey is the type of the context keys of the call attributes
+type contextKey string
+
+// The context keys under which ContextWithRPC stores the request ID, the service and the method of the calls,
+// as strings, to register with slogGorm.WithContextValue
+var (
+	RequestIDKey  = contextKey(RequestIDField)
+	RPCServiceKey = contextKey(RPCServiceField)
+	RPCMethodKey  = contextKey(RPCMethodField)
+)
+
+// ContextWithRPC stores the service and the method of the gRPC call of the given full method (e.g.
+// "/orders.v1.OrderService/GetOrder") and its request ID in the context, under RPCServiceKey, RPCMethodKey and
+// RequestIDKey. An empty request ID is replaced by a random one of 16 hexadecimal characters.
+func ContextWithRPC(ctx context.Context, fullMethod, requestID string) context.Context {
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	ctx = context.WithValue(ctx, RequestIDKey, requestID)
+
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return context.WithValue(ctx, RPCMethodKey, fullMethod)
+	}
+	ctx = context.WithValue(ctx, RPCServiceKey, service)
+	return context.WithValue(ctx, RPCMethodKey, method)
+}
+
+// LoggerOptions returns the options of slog-gorm logging the service, the method and the request ID of the calls
+// stored by ContextWithRPC, as the rpc.service, rpc.method and request_id attributes
+func LoggerOptions() []slogGorm.Option {
+	return []slogGorm.Option{
+		slogGorm.WithContextValue(RPCServiceField, RPCServiceKey),
+		slogGorm.WithContextValue(RPCMethodField, RPCMethodKey),
+		slogGorm.WithContextValue(RequestIDField, RequestIDKey),
+	}
+}
+
+// RequestIDFromContext returns the request ID of the call stored by ContextWithRPC in the context, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(RequestIDKey).(string)
+	return id, ok
+}
+
+// newRequestID generates a random request ID of 16 hexadecimal characters
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
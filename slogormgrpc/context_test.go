@@ -0,0 +1,57 @@
+package slogormgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogGorm "github.com/orandin/slog-gorm"
+	"github.com/orandin/slog-gorm/slogormtest"
+)
+
+func TestContextWithRPC(t *testing.T) {
+	handler := slogormtest.NewHandler()
+	gormLogger := slogGorm.New(append(LoggerOptions(), slogGorm.WithHandler(handler), slogGorm.WithTraceAll())...)
+	fc := func() (string, int64) { return "SELECT * FROM orders", 1 }
+
+	t.Run("Full method", func(t *testing.T) {
+		handler.Reset()
+		ctx := ContextWithRPC(context.Background(), "/orders.v1.OrderService/GetOrder", "4bf92f35")
+
+		gormLogger.Trace(ctx, time.Now(), fc, nil)
+
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "orders.v1.OrderService", records[0].String(RPCServiceField))
+		assert.Equal(t, "GetOrder", records[0].String(RPCMethodField))
+		assert.Equal(t, "4bf92f35", records[0].String(RequestIDField))
+		id, ok := RequestIDFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "4bf92f35", id)
+	})
+
+	t.Run("Malformed method", func(t *testing.T) {
+		handler.Reset()
+		ctx := ContextWithRPC(context.Background(), "GetOrder", "4bf92f35")
+
+		gormLogger.Trace(ctx, time.Now(), fc, nil)
+
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "GetOrder", records[0].String(RPCMethodField))
+		_, ok := records[0].Attr(RPCServiceField)
+		assert.False(t, ok)
+	})
+
+	t.Run("Generated request ID", func(t *testing.T) {
+		ctx := ContextWithRPC(context.Background(), "/orders.v1.OrderService/ListOrders", "")
+
+		id, ok := RequestIDFromContext(ctx)
+
+		assert.True(t, ok)
+		assert.Regexp(t, "^[0-9a-f]{16}$", id)
+	})
+}
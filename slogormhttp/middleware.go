@@ -0,0 +1,133 @@
+// Package slogormhttp provides a net/http middleware seeding the context of the requests with their ID, method and
+// route, logged by slog-gorm with the SQL queries of the requests.
+//
+// Usage:
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{
+//		Logger: slogGorm.New(slogormhttp.LoggerOptions()...),
+//	})
+//
+//	http.ListenAndServe(":8080", slogormhttp.Middleware(mux))
+//	// db.WithContext(r.Context()).First(&user) => ... request_id=4bf92f35 http.request.method=GET
+package slogormhttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+// The keys of the attributes logged by LoggerOptions, following the OpenTelemetry semantic conventions
+// for the method and the route
+const (
+	RequestIDField = "request_id"
+	MethodField    = "http.request.method"
+	RouteField     = "http.route"
+)
+
+// DefaultRequestIDHeader is the header of the requests read as their ID, see WithRequestIDHeader
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// contextKey is the type of the context keys of the request attributes
+type contextKey string
+
+// The context keys under which Middleware stores the ID, the method and the route of the requests, as strings,
+// to register with slogGorm.WithContextValue
+var (
+	RequestIDKey = contextKey(RequestIDField)
+	MethodKey    = contextKey(MethodField)
+	RouteKey     = contextKey(RouteField)
+)
+
+// config is the configuration of the middleware
+type config struct {
+	requestIDHeader string
+	newRequestID    func() string
+	route           func(r *http.Request) string
+}
+
+// Option is an option of the middleware
+type Option func(c *config)
+
+// WithRequestIDHeader defines the header of the requests read as their ID (DefaultRequestIDHeader by default)
+func WithRequestIDHeader(header string) Option {
+	return func(c *config) {
+		c.requestIDHeader = header
+	}
+}
+
+// WithRequestIDGenerator defines the function generating the ID of the requests without the header of their ID
+// (16 random hexadecimal characters by default)
+func WithRequestIDGenerator(fn func() string) Option {
+	return func(c *config) {
+		if fn != nil {
+			c.newRequestID = fn
+		}
+	}
+}
+
+// WithRoute defines the function returning the route pattern of the requests (e.g. "/users/{id}"), the route
+// being omitted by default. With Go 1.22 and later, the pattern matched by http.ServeMux is r.Pattern:
+//
+//	slogormhttp.WithRoute(func(r *http.Request) string { return r.Pattern })
+func WithRoute(fn func(r *http.Request) string) Option {
+	return func(c *config) {
+		c.route = fn
+	}
+}
+
+// Middleware stores the ID, the method and the route of each request in its context, under RequestIDKey,
+// MethodKey and RouteKey. The ID is read from the header of the request ID, or generated without it, and
+// written to the same header of the response.
+func Middleware(next http.Handler, options ...Option) http.Handler {
+	c := config{
+		requestIDHeader: DefaultRequestIDHeader,
+		newRequestID:    newRequestID,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(c.requestIDHeader)
+		if id == "" {
+			id = c.newRequestID()
+		}
+		w.Header().Set(c.requestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), RequestIDKey, id)
+		ctx = context.WithValue(ctx, MethodKey, r.Method)
+		if c.route != nil {
+			if route := c.route(r); route != "" {
+				ctx = context.WithValue(ctx, RouteKey, route)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// LoggerOptions returns the options of slog-gorm logging the ID, the method and the route of the requests
+// stored by Middleware, as the request_id, http.request.method and http.route attributes
+func LoggerOptions() []slogGorm.Option {
+	return []slogGorm.Option{
+		slogGorm.WithContextValue(RequestIDField, RequestIDKey),
+		slogGorm.WithContextValue(MethodField, MethodKey),
+		slogGorm.WithContextValue(RouteField, RouteKey),
+	}
+}
+
+// RequestIDFromContext returns the ID of the request stored by Middleware in the context, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(RequestIDKey).(string)
+	return id, ok
+}
+
+// newRequestID generates a random request ID of 16 hexadecimal characters
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
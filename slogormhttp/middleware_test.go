@@ -0,0 +1,82 @@
+package slogormhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogGorm "github.com/orandin/slog-gorm"
+	"github.com/orandin/slog-gorm/slogormtest"
+)
+
+func TestMiddleware(t *testing.T) {
+	handler := slogormtest.NewHandler()
+	gormLogger := slogGorm.New(append(LoggerOptions(), slogGorm.WithHandler(handler), slogGorm.WithTraceAll())...)
+	fc := func() (string, int64) { return "SELECT * FROM users", 1 }
+
+	t.Run("Request ID header", func(t *testing.T) {
+		handler.Reset()
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := RequestIDFromContext(r.Context())
+			assert.True(t, ok)
+			assert.Equal(t, "4bf92f35", id)
+			gormLogger.Trace(r.Context(), time.Now(), fc, nil)
+		})
+		r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		r.Header.Set(DefaultRequestIDHeader, "4bf92f35")
+		w := httptest.NewRecorder()
+
+		Middleware(next, WithRoute(func(*http.Request) string { return "/users/{id}" })).ServeHTTP(w, r)
+
+		assert.Equal(t, "4bf92f35", w.Header().Get(DefaultRequestIDHeader))
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "4bf92f35", records[0].String(RequestIDField))
+		assert.Equal(t, http.MethodGet, records[0].String(MethodField))
+		assert.Equal(t, "/users/{id}", records[0].String(RouteField))
+	})
+
+	t.Run("Generated request ID", func(t *testing.T) {
+		handler.Reset()
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gormLogger.Trace(r.Context(), time.Now(), fc, nil)
+		})
+		w := httptest.NewRecorder()
+
+		Middleware(next).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Len(t, records[0].String(RequestIDField), 16)
+		assert.Equal(t, records[0].String(RequestIDField), w.Header().Get(DefaultRequestIDHeader))
+		_, ok := records[0].Attr(RouteField)
+		assert.False(t, ok)
+	})
+
+	t.Run("Custom header and generator", func(t *testing.T) {
+		handler.Reset()
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gormLogger.Trace(r.Context(), time.Now(), fc, nil)
+		})
+		w := httptest.NewRecorder()
+
+		Middleware(next,
+			WithRequestIDHeader("X-Correlation-ID"),
+			WithRequestIDGenerator(func() string { return "generated" }),
+		).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/42", nil))
+
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "generated", records[0].String(RequestIDField))
+		assert.Equal(t, "generated", w.Header().Get("X-Correlation-ID"))
+	})
+}
+
+func Test_newRequestID(t *testing.T) {
+	assert.Regexp(t, "^[0-9a-f]{16}$", newRequestID())
+	assert.NotEqual(t, newRequestID(), newRequestID())
+}
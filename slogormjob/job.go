@@ -0,0 +1,75 @@
+// Package slogormjob tags the context of the background jobs (cron jobs, queue workers...) with their name and
+// their run ID, logged by slog-gorm with the SQL queries of the jobs.
+//
+// Usage:
+//
+//	gormLogger := slogGorm.New(append(slogormjob.LoggerOptions(), slogGorm.WithJobStats(slogormjob.JobField))...)
+//
+//	func (w *worker) handle(ctx context.Context, msg Message) error {
+//		ctx = slogormjob.ContextWithJob(ctx, "send-invoices", msg.ID)
+//		return w.db.WithContext(ctx).Create(&invoice).Error
+//	}
+//
+//	// ... job=send-invoices job_run_id=7c3a9f user=...
+package slogormjob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+// The keys of the attributes logged by LoggerOptions
+const (
+	JobField      = "job"
+	JobRunIDField = "job_run_id"
+)
+
+// contextKey is the type of the context keys of the job attributes
+type contextKey string
+
+// The context keys under which ContextWithJob stores the name and the run ID of the jobs, as strings, to register
+// with slogGorm.WithContextValue
+var (
+	JobKey      = contextKey(JobField)
+	JobRunIDKey = contextKey(JobRunIDField)
+)
+
+// ContextWithJob stores the name of the job and the ID of its run in the context, under JobKey and JobRunIDKey.
+// An empty run ID is replaced by a random one of 16 hexadecimal characters, so that the queries of the runs of a
+// cron job can be told apart.
+func ContextWithJob(ctx context.Context, name, runID string) context.Context {
+	if runID == "" {
+		runID = newRunID()
+	}
+	ctx = context.WithValue(ctx, JobKey, name)
+	return context.WithValue(ctx, JobRunIDKey, runID)
+}
+
+// LoggerOptions returns the options of slog-gorm logging the name and the run ID of the jobs stored by
+// ContextWithJob, as the job and job_run_id attributes
+func LoggerOptions() []slogGorm.Option {
+	return []slogGorm.Option{
+		slogGorm.WithContextValue(JobField, JobKey),
+		slogGorm.WithContextValue(JobRunIDField, JobRunIDKey),
+	}
+}
+
+// JobFromContext returns the name and the run ID of the job stored by ContextWithJob in the context, if any
+func JobFromContext(ctx context.Context) (name, runID string, ok bool) {
+	name, ok = ctx.Value(JobKey).(string)
+	if !ok {
+		return "", "", false
+	}
+	runID, _ = ctx.Value(JobRunIDKey).(string)
+	return name, runID, true
+}
+
+// newRunID generates a random run ID of 16 hexadecimal characters
+func newRunID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,62 @@
+package slogormjob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogGorm "github.com/orandin/slog-gorm"
+	"github.com/orandin/slog-gorm/slogormtest"
+)
+
+func TestContextWithJob(t *testing.T) {
+	handler := slogormtest.NewHandler()
+	gormLogger := slogGorm.New(append(LoggerOptions(),
+		slogGorm.WithHandler(handler),
+		slogGorm.WithTraceAll(),
+		slogGorm.WithJobStats(JobField),
+	)...)
+	fc := func() (string, int64) { return "SELECT * FROM invoices", 1 }
+
+	t.Run("Job attributes", func(t *testing.T) {
+		handler.Reset()
+		ctx := ContextWithJob(context.Background(), "send-invoices", "7c3a9f")
+
+		gormLogger.Trace(ctx, time.Now(), fc, nil)
+
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "send-invoices", records[0].String(JobField))
+		assert.Equal(t, "7c3a9f", records[0].String(JobRunIDField))
+		name, runID, ok := JobFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "send-invoices", name)
+		assert.Equal(t, "7c3a9f", runID)
+	})
+
+	t.Run("Generated run ID", func(t *testing.T) {
+		ctx := ContextWithJob(context.Background(), "purge-sessions", "")
+
+		_, runID, ok := JobFromContext(ctx)
+
+		assert.True(t, ok)
+		assert.Regexp(t, "^[0-9a-f]{16}$", runID)
+	})
+
+	t.Run("Without job", func(t *testing.T) {
+		_, _, ok := JobFromContext(context.Background())
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Aggregated by job", func(t *testing.T) {
+		jobs := gormLogger.Stats().Jobs
+
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "send-invoices", jobs[0].Value)
+		assert.Equal(t, int64(1), jobs[0].Count)
+	})
+}
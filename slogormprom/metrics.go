@@ -0,0 +1,123 @@
+// Package slogormprom publishes the aggregates of slog-gorm (see slogGorm.WithQueryStats, WithRouteStats and
+// WithJobStats) and its counters as metrics, so that the applications registering the Prometheus plugin of gorm
+// (gorm.io/plugin/prometheus) expose them through its collectors rather than a parallel registry.
+//
+// The modules of Prometheus are not dependencies of slog-gorm: the collector of the plugin is written with them,
+// converting the metrics returned by Metrics at each scrape:
+//
+//	type logCollector struct{ logger slogGorm.Logger }
+//
+//	func (c logCollector) Metrics(*gormprometheus.Prometheus) []prometheus.Collector {
+//		return []prometheus.Collector{c}
+//	}
+//
+//	func (c logCollector) Describe(ch chan<- *prometheus.Desc) {
+//		prometheus.DescribeByCollect(c, ch)
+//	}
+//
+//	func (c logCollector) Collect(ch chan<- prometheus.Metric) {
+//		for _, m := range slogormprom.Metrics(c.logger) {
+//			valueType := prometheus.GaugeValue
+//			if m.Type == slogormprom.Counter {
+//				valueType = prometheus.CounterValue
+//			}
+//			desc := prometheus.NewDesc(m.Name, m.Help, m.LabelNames, nil)
+//			ch <- prometheus.MustNewConstMetric(desc, valueType, m.Value, m.LabelValues...)
+//		}
+//	}
+//
+//	db.Use(gormprometheus.New(gormprometheus.Config{
+//		DBName:           "orders",
+//		MetricsCollector: []gormprometheus.MetricsCollector{logCollector{gormLogger}},
+//	}))
+package slogormprom
+
+import slogGorm "github.com/orandin/slog-gorm"
+
+// Prefix is the prefix of the names of the metrics, following the gorm_status_ prefix of the metrics of the plugin
+const Prefix = "gorm_slog_"
+
+// MetricType is the type of a metric
+type MetricType int
+
+const (
+	// Counter is a metric which only increases, e.g. a number of queries
+	Counter MetricType = iota
+	// Gauge is a metric which may increase and decrease, e.g. a percentile
+	Gauge
+)
+
+// Metric is a sample of a metric, its label names being the same for all the samples of a name
+type Metric struct {
+	Name        string
+	Help        string
+	Type        MetricType
+	LabelNames  []string
+	LabelValues []string
+	Value       float64
+}
+
+// Metrics returns the samples of the metrics of the logger, read from its Stats:
+//
+//   - gorm_slog_queries_total, gorm_slog_query_errors_total, gorm_slog_slow_queries_total and
+//     gorm_slog_query_duration_p95_seconds by fingerprint, see slogGorm.WithQueryStats;
+//   - gorm_slog_route_queries_total, gorm_slog_route_query_errors_total, gorm_slog_route_slow_queries_total and
+//     gorm_slog_route_query_duration_seconds_total by route, see slogGorm.WithRouteStats;
+//   - the same metrics prefixed by gorm_slog_job_ by job, see slogGorm.WithJobStats;
+//   - gorm_slog_records_filtered_total, gorm_slog_records_sampled_out_total, gorm_slog_records_dropped_total and
+//     gorm_slog_handler_failures_total, the counters of the records not written.
+//
+// The metrics of the aggregates not enabled are omitted.
+func Metrics(l slogGorm.Logger) []Metric {
+	stats := l.Stats()
+	metrics := make([]Metric, 0, 4*(len(stats.Queries)+len(stats.Routes)+len(stats.Jobs)+1))
+
+	for _, q := range stats.Queries {
+		labels := []string{q.Fingerprint}
+		metrics = append(metrics,
+			counter("queries_total", "Number of SQL queries.", "fingerprint", labels, float64(q.Count)),
+			counter("query_errors_total", "Number of SQL queries which failed.", "fingerprint", labels, float64(q.Errors)),
+			counter("slow_queries_total", "Number of slow SQL queries.", "fingerprint", labels, float64(q.Slow)),
+			Metric{
+				Name:        Prefix + "query_duration_p95_seconds",
+				Help:        "95th percentile of the durations of the last 1024 SQL queries.",
+				Type:        Gauge,
+				LabelNames:  []string{"fingerprint"},
+				LabelValues: labels,
+				Value:       q.P95.Seconds(),
+			},
+		)
+	}
+	metrics = appendAttrMetrics(metrics, "route_", "route", stats.Routes)
+	metrics = appendAttrMetrics(metrics, "job_", "job", stats.Jobs)
+
+	return append(metrics,
+		counter("records_filtered_total", "Number of SQL records suppressed by the filters.", "", nil, float64(stats.Filtered)),
+		counter("records_sampled_out_total", "Number of SQL records discarded by the sampling.", "", nil, float64(stats.SampledOut)),
+		counter("records_dropped_total", "Number of records dropped by the asynchronous mode.", "", nil, float64(stats.Dropped)),
+		counter("handler_failures_total", "Number of records the handler failed to write.", "", nil, float64(stats.HandlerFailures)),
+	)
+}
+
+// appendAttrMetrics adds the metrics of the aggregates by value of a context attribute, labeled by the given name
+func appendAttrMetrics(metrics []Metric, prefix, label string, stats []slogGorm.AttrStats) []Metric {
+	for _, s := range stats {
+		labels := []string{s.Value}
+		metrics = append(metrics,
+			counter(prefix+"queries_total", "Number of SQL queries by "+label+".", label, labels, float64(s.Count)),
+			counter(prefix+"query_errors_total", "Number of SQL queries which failed by "+label+".", label, labels, float64(s.Errors)),
+			counter(prefix+"slow_queries_total", "Number of slow SQL queries by "+label+".", label, labels, float64(s.Slow)),
+			counter(prefix+"query_duration_seconds_total", "Cumulated duration of the SQL queries by "+label+".", label, labels, s.Total.Seconds()),
+		)
+	}
+	return metrics
+}
+
+// counter returns the sample of a counter, with a single label unless the label name is empty
+func counter(name, help, label string, values []string, value float64) Metric {
+	m := Metric{Name: Prefix + name, Help: help, Type: Counter, LabelValues: values, Value: value}
+	if label != "" {
+		m.LabelNames = []string{label}
+	}
+	return m
+}
@@ -0,0 +1,67 @@
+package slogormprom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogGorm "github.com/orandin/slog-gorm"
+	"github.com/orandin/slog-gorm/slogormtest"
+)
+
+// find returns the sample of the metric with the given name and label values
+func find(t *testing.T, metrics []Metric, name string, labelValues ...string) Metric {
+	t.Helper()
+	for _, m := range metrics {
+		if m.Name == name && assert.ObjectsAreEqual(labelValues, m.LabelValues) {
+			return m
+		}
+	}
+	require.Failf(t, "metric not found", "%s %v", name, labelValues)
+	return Metric{}
+}
+
+func TestMetrics(t *testing.T) {
+	routeKey := struct{ name string }{"route"}
+	gormLogger := slogGorm.New(
+		slogGorm.WithHandler(slogormtest.NewHandler()),
+		slogGorm.WithContextValue("http.route", routeKey),
+		slogGorm.WithQueryStats(),
+		slogGorm.WithRouteStats("http.route"),
+		slogGorm.WithSlowThreshold(10*time.Millisecond),
+	)
+	ctx := context.WithValue(context.Background(), routeKey, "GET /users")
+	fc := func() (string, int64) { return "SELECT * FROM users", 1 }
+
+	gormLogger.Trace(ctx, time.Now().Add(-time.Millisecond), fc, nil)
+	gormLogger.Trace(ctx, time.Now().Add(-20*time.Millisecond), fc, nil)
+	gormLogger.Trace(ctx, time.Now(), fc, errors.New("timeout"))
+
+	metrics := Metrics(gormLogger)
+
+	fingerprint := "SELECT * FROM users"
+	queries := find(t, metrics, "gorm_slog_queries_total", fingerprint)
+	assert.Equal(t, Counter, queries.Type)
+	assert.Equal(t, []string{"fingerprint"}, queries.LabelNames)
+	assert.Equal(t, 3.0, queries.Value)
+	assert.Equal(t, 1.0, find(t, metrics, "gorm_slog_query_errors_total", fingerprint).Value)
+	assert.Equal(t, 1.0, find(t, metrics, "gorm_slog_slow_queries_total", fingerprint).Value)
+	assert.Equal(t, Gauge, find(t, metrics, "gorm_slog_query_duration_p95_seconds", fingerprint).Type)
+
+	routeQueries := find(t, metrics, "gorm_slog_route_queries_total", "GET /users")
+	assert.Equal(t, []string{"route"}, routeQueries.LabelNames)
+	assert.Equal(t, 3.0, routeQueries.Value)
+	assert.Equal(t, 1.0, find(t, metrics, "gorm_slog_route_slow_queries_total", "GET /users").Value)
+	assert.Greater(t, find(t, metrics, "gorm_slog_route_query_duration_seconds_total", "GET /users").Value, 0.02)
+
+	filtered := find(t, metrics, "gorm_slog_records_filtered_total")
+	assert.Empty(t, filtered.LabelNames)
+	assert.Equal(t, 0.0, filtered.Value)
+	for _, m := range metrics {
+		assert.NotContains(t, m.Name, "gorm_slog_job_", "the job stats are not enabled")
+	}
+}
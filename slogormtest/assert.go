@@ -0,0 +1,147 @@
+package slogormtest
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Matcher reports whether a record matches, see AssertLoggedQuery
+type Matcher interface {
+	Match(r Record) bool
+	String() string
+}
+
+// matcher is a Matcher described by its name
+type matcher struct {
+	name  string
+	match func(r Record) bool
+}
+
+func (m matcher) Match(r Record) bool {
+	return m.match(r)
+}
+
+func (m matcher) String() string {
+	return m.name
+}
+
+// MatchFunc returns a Matcher described by the given name, matching the records for which fn returns true
+func MatchFunc(name string, fn func(r Record) bool) Matcher {
+	return matcher{name: name, match: fn}
+}
+
+// Query matches the records of the given SQL query
+func Query(sql string) Matcher {
+	return MatchFunc(fmt.Sprintf("query = %q", sql), func(r Record) bool {
+		return r.Query() == sql
+	})
+}
+
+// QueryContains matches the records whose SQL query contains the given text
+func QueryContains(text string) Matcher {
+	return MatchFunc(fmt.Sprintf("query contains %q", text), func(r Record) bool {
+		return strings.Contains(r.Query(), text)
+	})
+}
+
+// QueryMatches matches the records whose SQL query matches the given regular expression
+func QueryMatches(pattern *regexp.Regexp) Matcher {
+	return MatchFunc(fmt.Sprintf("query matches %q", pattern), func(r Record) bool {
+		return pattern.MatchString(r.Query())
+	})
+}
+
+// Level matches the records of the given level
+func Level(level slog.Level) Matcher {
+	return MatchFunc(fmt.Sprintf("level = %s", level), func(r Record) bool {
+		return r.Level == level
+	})
+}
+
+// Attr matches the records with the given attribute, the values being compared with slog.Value.Equal
+func Attr(key string, value any) Matcher {
+	expected := slog.AnyValue(value).Resolve()
+	return MatchFunc(fmt.Sprintf("%s = %v", key, expected), func(r Record) bool {
+		actual, ok := r.Attrs[key]
+		return ok && actual.Equal(expected)
+	})
+}
+
+// All matches the records matched by all the given matchers
+func All(matchers ...Matcher) Matcher {
+	names := make([]string, len(matchers))
+	for i, m := range matchers {
+		names[i] = m.String()
+	}
+	return MatchFunc(strings.Join(names, " and "), func(r Record) bool {
+		for _, m := range matchers {
+			if !m.Match(r) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Find returns the records of the SQL queries matched by the matcher, in order
+func (h *Handler) Find(m Matcher) []Record {
+	var matched []Record
+	for _, r := range h.Queries() {
+		if m.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// AssertLoggedQuery asserts that a SQL query matched by the matcher was logged, and returns the first
+// record matched, if any. The SQL queries logged are listed on failure.
+func (h *Handler) AssertLoggedQuery(t testing.TB, m Matcher) (Record, bool) {
+	t.Helper()
+
+	matched := h.Find(m)
+	if len(matched) == 0 {
+		t.Errorf("no SQL query logged matching %s, logged:%s", m, h.describeQueries())
+		return Record{}, false
+	}
+	return matched[0], true
+}
+
+// AssertNotLoggedQuery asserts that no SQL query matched by the matcher was logged
+func (h *Handler) AssertNotLoggedQuery(t testing.TB, m Matcher) bool {
+	t.Helper()
+
+	if matched := h.Find(m); len(matched) > 0 {
+		t.Errorf("%d SQL queries logged matching %s, first: %q", len(matched), m, matched[0].Query())
+		return false
+	}
+	return true
+}
+
+// AssertQueryCount asserts that exactly count SQL queries matched by the matcher were logged, e.g. to
+// detect the N+1 queries
+func (h *Handler) AssertQueryCount(t testing.TB, m Matcher, count int) bool {
+	t.Helper()
+
+	if matched := h.Find(m); len(matched) != count {
+		t.Errorf("%d SQL queries logged matching %s, expected %d, logged:%s", len(matched), m, count, h.describeQueries())
+		return false
+	}
+	return true
+}
+
+// describeQueries lists the SQL queries logged, for the failure messages
+func (h *Handler) describeQueries() string {
+	queries := h.Queries()
+	if len(queries) == 0 {
+		return " none"
+	}
+	var b strings.Builder
+	for _, r := range queries {
+		fmt.Fprintf(&b, "\n\t[%s] %s", r.Level, r.Query())
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package slogormtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+// recordingT records the failures of the assertions
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestHandler_Assertions(t *testing.T) {
+	handler := NewHandler()
+	gormLogger := slogGorm.New(slogGorm.WithHandler(handler), slogGorm.WithTraceAll())
+	for _, sql := range []string{
+		"SELECT * FROM users WHERE id = 1",
+		"SELECT * FROM orders WHERE user_id = 1",
+		"SELECT * FROM orders WHERE user_id = 2",
+	} {
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Logged query", func(t *testing.T) {
+		rt := &recordingT{}
+
+		record, ok := handler.AssertLoggedQuery(rt, All(QueryContains("FROM orders"), Level(slog.LevelInfo)))
+
+		assert.True(t, ok)
+		assert.Empty(t, rt.errors)
+		assert.Equal(t, "SELECT * FROM orders WHERE user_id = 1", record.Query())
+	})
+
+	t.Run("Query not logged", func(t *testing.T) {
+		rt := &recordingT{}
+
+		_, ok := handler.AssertLoggedQuery(rt, Query("DELETE FROM users"))
+
+		assert.False(t, ok)
+		assert.Len(t, rt.errors, 1)
+		assert.Contains(t, rt.errors[0], `query = "DELETE FROM users"`)
+		assert.Contains(t, rt.errors[0], "SELECT * FROM users WHERE id = 1")
+	})
+
+	t.Run("Not logged query", func(t *testing.T) {
+		rt := &recordingT{}
+
+		assert.True(t, handler.AssertNotLoggedQuery(rt, QueryContains("FROM sessions")))
+		assert.False(t, handler.AssertNotLoggedQuery(rt, QueryContains("FROM users")))
+		assert.Len(t, rt.errors, 1)
+	})
+
+	t.Run("Query count", func(t *testing.T) {
+		rt := &recordingT{}
+
+		assert.True(t, handler.AssertQueryCount(rt, QueryMatches(regexp.MustCompile(`user_id = \d`)), 2))
+		assert.False(t, handler.AssertQueryCount(rt, Level(slog.LevelInfo), 1))
+		assert.Len(t, rt.errors, 1)
+	})
+
+	t.Run("Attribute", func(t *testing.T) {
+		assert.Len(t, handler.Find(Attr(slogGorm.RowsField, 1)), 3)
+		assert.Empty(t, handler.Find(Attr(slogGorm.RowsField, 2)))
+	})
+}
@@ -0,0 +1,53 @@
+package slogormtest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a slogGorm.Clock whose time only moves when advanced, to trigger the slow queries and the long
+// transactions deterministically, see slogGorm.WithClock. It is safe for concurrent use.
+//
+// Usage:
+//
+//	clock := slogormtest.NewClock(time.Now())
+//	gormLogger := slogGorm.New(slogGorm.WithClock(clock), slogGorm.WithSlowThreshold(time.Second))
+//
+//	begin := clock.Now()
+//	clock.Advance(2 * time.Second)
+//	gormLogger.Trace(ctx, begin, fc, nil) // logged as a slow query
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a clock at the given time
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now implements slogGorm.Clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since implements slogGorm.Clock
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to the given time
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
@@ -0,0 +1,38 @@
+package slogormtest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+func TestClock(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	clock := NewClock(now)
+	handler := NewHandler()
+	gormLogger := slogGorm.New(
+		slogGorm.WithHandler(handler),
+		slogGorm.WithClock(clock),
+		slogGorm.WithSlowThreshold(time.Second),
+	)
+	fc := func() (string, int64) { return "SELECT * FROM users", 1 }
+
+	begin := clock.Now()
+	clock.Advance(500 * time.Millisecond)
+	gormLogger.Trace(context.Background(), begin, fc, nil)
+	handler.AssertNotLoggedQuery(t, Level(slog.LevelWarn))
+
+	clock.Advance(time.Second)
+	gormLogger.Trace(context.Background(), begin, fc, nil)
+	record, _ := handler.AssertLoggedQuery(t, Level(slog.LevelWarn))
+	assert.Equal(t, now.Add(1500*time.Millisecond), record.Time)
+
+	clock.Set(now)
+	assert.Equal(t, now, clock.Now())
+	assert.Equal(t, time.Minute, clock.Since(now.Add(-time.Minute)))
+}
@@ -0,0 +1,181 @@
+// Package slogormtest provides a recording slog handler to assert on the SQL records of slog-gorm in tests.
+//
+// Usage:
+//
+//	handler := slogormtest.NewHandler()
+//	db, err := gorm.Open(dialector, &gorm.Config{
+//		Logger: slogGorm.New(slogGorm.WithHandler(handler), slogGorm.WithTraceAll()),
+//	})
+//
+//	db.First(&user)
+//	handler.AssertLoggedQuery(t, slogormtest.QueryContains("FROM users"))
+package slogormtest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+// Record is a record written to the Handler, with its attributes resolved
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs are the attributes of the record and of the handler, the keys of the attributes in groups being
+	// prefixed by their group and a dot (e.g. "db.query")
+	Attrs map[string]slog.Value
+}
+
+// Attr returns the value of the attribute with the given key, if any
+func (r Record) Attr(key string) (slog.Value, bool) {
+	value, ok := r.Attrs[key]
+	return value, ok
+}
+
+// String returns the value of the attribute with the given key as a string, empty if there is none
+func (r Record) String(key string) string {
+	if value, ok := r.Attrs[key]; ok {
+		return value.String()
+	}
+	return ""
+}
+
+// Query returns the SQL query of the record (see slogGorm.QueryField), empty if it is not a SQL record
+func (r Record) Query() string {
+	return r.String(slogGorm.QueryField)
+}
+
+// Handler is a slog.Handler recording the records, safe for concurrent use. The values of the attributes are
+// resolved when the records are written, as slog-gorm resolves the SQL queries lazily.
+type Handler struct {
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+
+	// records are shared by the handlers derived with WithAttrs and WithGroup
+	records *records
+}
+
+// records are the records written to a Handler and to the handlers derived from it
+type records struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// HandlerOption is an option of the Handler, see NewHandler
+type HandlerOption func(h *Handler)
+
+// WithLevel records only the records of the given level or above (all the records by default)
+func WithLevel(level slog.Leveler) HandlerOption {
+	return func(h *Handler) {
+		h.level = level
+	}
+}
+
+// NewHandler creates a Handler recording the records
+func NewHandler(options ...HandlerOption) *Handler {
+	h := &Handler{records: &records{}}
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.level == nil || level >= h.level.Level()
+}
+
+// Handle implements slog.Handler, recording the record
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	record := Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   make(map[string]slog.Value, len(h.attrs)+r.NumAttrs()),
+	}
+	for _, attr := range h.attrs {
+		addAttr(record.Attrs, "", attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		addAttr(record.Attrs, h.group, attr)
+		return true
+	})
+
+	h.records.mu.Lock()
+	defer h.records.mu.Unlock()
+	h.records.records = append(h.records.records, record)
+	return nil
+}
+
+// addAttr adds the resolved attribute to the attributes, the attributes of the groups being flattened
+func addAttr(attrs map[string]slog.Value, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		// The attributes of the inline groups, without key, are added to the group of the attribute
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range value.Group() {
+			addAttr(attrs, prefix, a)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+	attrs[prefix+attr.Key] = value
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = slices.Clone(h.attrs)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr = slog.Attr{Key: h.group[:len(h.group)-1], Value: slog.GroupValue(attr)}
+		}
+		c.attrs = append(c.attrs, attr)
+	}
+	return &c
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.group = h.group + name + "."
+	return &c
+}
+
+// Records returns a copy of the records written, in order
+func (h *Handler) Records() []Record {
+	h.records.mu.Lock()
+	defer h.records.mu.Unlock()
+	return slices.Clone(h.records.records)
+}
+
+// Queries returns the records of the SQL queries written, i.e. with a query attribute, in order
+func (h *Handler) Queries() []Record {
+	var queries []Record
+	for _, r := range h.Records() {
+		if _, ok := r.Attrs[slogGorm.QueryField]; ok {
+			queries = append(queries, r)
+		}
+	}
+	return queries
+}
+
+// Reset forgets the records written
+func (h *Handler) Reset() {
+	h.records.mu.Lock()
+	defer h.records.mu.Unlock()
+	h.records.records = nil
+}
@@ -0,0 +1,59 @@
+package slogormtest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("SQL records", func(t *testing.T) {
+		handler := NewHandler()
+		gormLogger := slogGorm.New(slogGorm.WithHandler(handler), slogGorm.WithTraceAll())
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM users WHERE id = 1", 1
+		}, nil)
+		gormLogger.Info(context.Background(), "connected")
+
+		require.Len(t, handler.Records(), 2)
+		queries := handler.Queries()
+		require.Len(t, queries, 1)
+		assert.Equal(t, "SELECT * FROM users WHERE id = 1", queries[0].Query())
+		assert.Equal(t, slog.LevelInfo, queries[0].Level)
+		rows, ok := queries[0].Attr(slogGorm.RowsField)
+		require.True(t, ok)
+		assert.Equal(t, int64(1), rows.Int64())
+
+		handler.Reset()
+		assert.Empty(t, handler.Records())
+	})
+
+	t.Run("Attributes and groups", func(t *testing.T) {
+		handler := NewHandler()
+		logger := slog.New(handler).With("service", "api").WithGroup("db").With("name", "main")
+
+		logger.Info("query", slog.String("query", "SELECT 1"), slog.Group("user", slog.Int("id", 42)))
+
+		records := handler.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "api", records[0].String("service"))
+		assert.Equal(t, "main", records[0].String("db.name"))
+		assert.Equal(t, "SELECT 1", records[0].String("db.query"))
+		assert.Equal(t, "42", records[0].String("db.user.id"))
+		assert.Empty(t, records[0].Query())
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		handler := NewHandler(WithLevel(slog.LevelWarn))
+
+		assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+		assert.True(t, handler.Enabled(context.Background(), slog.LevelError))
+	})
+}
@@ -0,0 +1,140 @@
+package slogormtest
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable which, set to a true value (e.g. UPDATE_GOLDEN=1 go test ./...),
+// makes AssertSnapshot write the golden files instead of comparing them
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// SnapshotOption is an option of the rendering of the records, see Snapshot
+type SnapshotOption func(s *snapshotConfig)
+
+// snapshotConfig is the configuration of the rendering of the records
+type snapshotConfig struct {
+	ignored map[string]struct{}
+	masked  map[string]struct{}
+}
+
+// IgnoreAttrs omits the attributes with the given keys from the snapshot, e.g. the source of the queries
+// (slogGorm.SourceField) whose path depends on the machine running the tests
+func IgnoreAttrs(keys ...string) SnapshotOption {
+	return func(s *snapshotConfig) {
+		for _, key := range keys {
+			s.ignored[key] = struct{}{}
+		}
+	}
+}
+
+// MaskAttrs replaces the values of the attributes with the given keys by <masked> in the snapshot, e.g.
+// the generated identifiers, so that their presence is still asserted
+func MaskAttrs(keys ...string) SnapshotOption {
+	return func(s *snapshotConfig) {
+		for _, key := range keys {
+			s.masked[key] = struct{}{}
+		}
+	}
+}
+
+// Snapshot renders the records written canonically, for the golden files: a line per record with its level,
+// its message and its attributes sorted by key, without the time of the record. The durations and the times
+// of the attributes are rendered as <duration> and <time>, so that the snapshot is stable across the runs.
+// The messages are rendered as is: use slogGorm.WithMetadataMode(slogGorm.MetadataAttrsOnly) to log the SQL
+// messages without their duration.
+//
+// Example of line:
+//
+//	level=INFO msg="SQL query executed" duration=<duration> query="SELECT * FROM users" rows=1
+func (h *Handler) Snapshot(options ...SnapshotOption) string {
+	config := &snapshotConfig{ignored: map[string]struct{}{}, masked: map[string]struct{}{}}
+	for _, option := range options {
+		option(config)
+	}
+
+	var b strings.Builder
+	for _, r := range h.Records() {
+		b.WriteString("level=")
+		b.WriteString(r.Level.String())
+		b.WriteString(" msg=")
+		b.WriteString(quote(r.Message))
+
+		keys := make([]string, 0, len(r.Attrs))
+		for key := range r.Attrs {
+			if _, ok := config.ignored[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			b.WriteByte(' ')
+			b.WriteString(key)
+			b.WriteByte('=')
+			if _, ok := config.masked[key]; ok {
+				b.WriteString("<masked>")
+				continue
+			}
+			b.WriteString(renderValue(r.Attrs[key]))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// renderValue renders the value of an attribute in a snapshot
+func renderValue(value slog.Value) string {
+	switch value.Kind() {
+	case slog.KindDuration:
+		return "<duration>"
+	case slog.KindTime:
+		return "<time>"
+	}
+	return quote(value.String())
+}
+
+// quote quotes the text if it is empty or contains spaces, quotes or equal signs
+func quote(text string) string {
+	if text == "" || strings.ContainsAny(text, " \t\r\n\"=\\") || !strconv.CanBackquote(text) {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+// AssertSnapshot asserts that the snapshot of the records written (see Snapshot) equals the content of the
+// golden file at path, e.g. "testdata/users.golden". The golden file is written instead, creating its
+// directory if necessary, when the UpdateEnv environment variable is set to a true value.
+func (h *Handler) AssertSnapshot(t testing.TB, path string, options ...SnapshotOption) bool {
+	t.Helper()
+
+	actual := h.Snapshot(options...)
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateEnv)); update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("creating the directory of the golden file: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Errorf("writing the golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading the golden file (run the tests with %s=1 to write it): %v", UpdateEnv, err)
+		return false
+	}
+	if !bytes.Equal(expected, []byte(actual)) {
+		t.Errorf("the records differ from the golden file %s (run the tests with %s=1 to update it)\nexpected:\n%s\nactual:\n%s",
+			path, UpdateEnv, expected, actual)
+		return false
+	}
+	return true
+}
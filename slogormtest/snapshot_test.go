@@ -0,0 +1,64 @@
+package slogormtest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	slogGorm "github.com/orandin/slog-gorm"
+)
+
+// requestIDKey is the context key of the identifier of the request
+type requestIDKey struct{}
+
+func TestHandler_Snapshot(t *testing.T) {
+	handler := NewHandler()
+	gormLogger := slogGorm.New(
+		slogGorm.WithHandler(handler),
+		slogGorm.WithTraceAll(),
+		slogGorm.WithMetadataMode(slogGorm.MetadataAttrsOnly),
+		slogGorm.WithContextValue("request_id", requestIDKey{}),
+	)
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "42")
+	gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT * FROM users WHERE name = 'john'", 1 }, nil)
+	gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return "DELETE FROM users", 0 }, errors.New("read-only"))
+
+	t.Run("Canonical", func(t *testing.T) {
+		assert.Equal(t, `level=INFO msg="SQL query executed" duration=<duration> query="SELECT * FROM users WHERE name = 'john'" request_id=<masked> rows=1
+level=ERROR msg=read-only duration=<duration> error=read-only query="DELETE FROM users" request_id=<masked> rows=0
+`, handler.Snapshot(IgnoreAttrs(slogGorm.SourceField), MaskAttrs("request_id")))
+	})
+
+	t.Run("Golden file", func(t *testing.T) {
+		handler.AssertSnapshot(t, filepath.Join("testdata", "snapshot.golden"), IgnoreAttrs(slogGorm.SourceField))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Setenv(UpdateEnv, "1")
+		path := filepath.Join(t.TempDir(), "testdata", "snapshot.golden")
+
+		assert.True(t, handler.AssertSnapshot(t, path, IgnoreAttrs(slogGorm.SourceField)))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, handler.Snapshot(IgnoreAttrs(slogGorm.SourceField)), string(content))
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		t.Setenv(UpdateEnv, "")
+		rt := &recordingT{}
+		path := filepath.Join(t.TempDir(), "snapshot.golden")
+
+		assert.False(t, handler.AssertSnapshot(rt, path))
+		assert.NoError(t, os.WriteFile(path, []byte("level=INFO msg=other\n"), 0o644))
+		assert.False(t, handler.AssertSnapshot(rt, path))
+
+		assert.Len(t, rt.errors, 2)
+		assert.Contains(t, rt.errors[1], "level=INFO msg=other")
+	})
+}
@@ -0,0 +1,67 @@
+package slogGorm
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slowLog writes the slow queries in the format of the slow query log of MySQL, see WithSlowLog
+type slowLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// HandleQuery implements Sink, writing the slow queries only
+func (s *slowLog) HandleQuery(_ context.Context, event QueryEvent) {
+	if event.Slow {
+		_ = s.write(event.Time, event.Duration, event.SQL, event.Rows)
+	}
+}
+
+// write writes the entry of the slow query started at begin. The entries are written in the order of
+// the calls, with a single write each.
+func (s *slowLog) write(begin time.Time, elapsed time.Duration, sql string, rows int64) error {
+	entry := formatSlowLogEntry(begin, elapsed, sql, rows)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := io.WriteString(s.w, entry)
+	return err
+}
+
+// formatSlowLogEntry formats the entry of a slow query like the slow query log of MySQL:
+//
+//	# Time: 2024-01-02T15:04:05.123456Z
+//	# Query_time: 1.500000  Lock_time: 0.000000 Rows_sent: 2  Rows_examined: 2
+//	SET timestamp=1704207845;
+//	SELECT * FROM users;
+//
+// The number of rows affected is reported as the rows sent and examined, which are unknown to gorm,
+// and the lock time is always zero.
+func formatSlowLogEntry(begin time.Time, elapsed time.Duration, sql string, rows int64) string {
+	if rows < 0 {
+		rows = 0
+	}
+	sql = strings.TrimSpace(sql)
+	sql = strings.TrimSuffix(sql, ";")
+
+	var b strings.Builder
+	b.WriteString("# Time: ")
+	b.WriteString(begin.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	b.WriteString("\n# Query_time: ")
+	b.WriteString(strconv.FormatFloat(elapsed.Seconds(), 'f', 6, 64))
+	b.WriteString("  Lock_time: 0.000000 Rows_sent: ")
+	b.WriteString(strconv.FormatInt(rows, 10))
+	b.WriteString("  Rows_examined: ")
+	b.WriteString(strconv.FormatInt(rows, 10))
+	b.WriteString("\nSET timestamp=")
+	b.WriteString(strconv.FormatInt(begin.Unix(), 10))
+	b.WriteString(";\n")
+	b.WriteString(sql)
+	b.WriteString(";\n")
+	return b.String()
+}
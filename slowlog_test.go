@@ -0,0 +1,87 @@
+package slogGorm
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_SlowLog(t *testing.T) {
+	trace := func(l *logger, begin time.Time, sql string) {
+		l.Trace(context.Background(), begin, func() (string, int64) { return sql, 3 }, nil)
+	}
+
+	t.Run("Slow query", func(t *testing.T) {
+		var buf bytes.Buffer
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(10 * time.Millisecond),
+			WithSlowLog(&buf),
+		})
+
+		begin := time.Now().Add(-time.Second)
+		trace(gormLogger, begin, "SELECT * FROM users WHERE id = 1")
+
+		require.NotNil(t, receiver.Record)
+		entry := buf.String()
+		assert.True(t, strings.HasPrefix(entry, "# Time: "+begin.UTC().Format("2006-01-02T15:04:05.000000Z")+"\n# Query_time: 1.0"))
+		assert.Contains(t, entry, "  Lock_time: 0.000000 Rows_sent: 3  Rows_examined: 3\n")
+		assert.True(t, strings.HasSuffix(entry, "\nSELECT * FROM users WHERE id = 1;\n"))
+	})
+
+	t.Run("Fast query", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(time.Minute),
+			WithSlowLog(&buf),
+		})
+
+		trace(gormLogger, time.Now(), "SELECT * FROM users")
+
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("Whatever the tracing", func(t *testing.T) {
+		var buf bytes.Buffer
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(10 * time.Millisecond),
+			WithSlowLog(&buf),
+			WithIgnoreTrace(),
+			WithMinLevel(slog.LevelError),
+		})
+
+		trace(gormLogger, time.Now().Add(-time.Second), "SELECT * FROM users")
+
+		assert.Nil(t, receiver.Record)
+		assert.Contains(t, buf.String(), "\nSELECT * FROM users;\n")
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(10 * time.Millisecond),
+			WithSlowLog(&buf),
+			WithNoValues(),
+		})
+
+		trace(gormLogger, time.Now().Add(-time.Second), "SELECT * FROM users WHERE email = 'john@example.com'")
+
+		assert.Contains(t, buf.String(), "\nSELECT * FROM users WHERE email = ?;\n")
+	})
+}
+
+func Test_formatSlowLogEntry(t *testing.T) {
+	begin := time.Date(2024, 1, 2, 16, 4, 5, 123456789, time.FixedZone("CET", 3600))
+
+	entry := formatSlowLogEntry(begin, 1500*time.Millisecond, " SELECT * FROM users; ", -1)
+
+	assert.Equal(t, "# Time: 2024-01-02T15:04:05.123456Z\n"+
+		"# Query_time: 1.500000  Lock_time: 0.000000 Rows_sent: 0  Rows_examined: 0\n"+
+		"SET timestamp=1704207845;\n"+
+		"SELECT * FROM users;\n", entry)
+}
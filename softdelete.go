@@ -0,0 +1,72 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// SoftDeleteField is the key of the attribute flagging the statements of the soft deletes, see WithSoftDeleteFlag
+const SoftDeleteField = "soft_delete"
+
+// The values of the soft_delete attribute
+const (
+	// SoftDeleteDelete flags the UPDATE statements generated by the soft deletes
+	SoftDeleteDelete = "delete"
+	// SoftDeleteFilter flags the statements whose condition excluding the soft deleted rows (e.g. deleted_at IS
+	// NULL) was added by gorm
+	SoftDeleteFilter = "filter"
+)
+
+// softDeleteEnabled is the name of the clause set by gorm on the statements of the models with soft deletes,
+// unless they are unscoped
+const softDeleteEnabled = "soft_delete_enabled"
+
+// softDeleteContextKey is the context key under which the statement of a query is stored, to flag its soft delete
+type softDeleteContextKey struct{}
+
+// softDeleteStatement is the statement of a query, deletion reporting whether it is executed by a Delete
+type softDeleteStatement struct {
+	statement *gorm.Statement
+	deletion  bool
+}
+
+// captureSoftDelete returns a callback storing the statement into its context, so that Trace flags the soft
+// delete once gorm built it, see WithSoftDeleteFlag
+func (l logger) captureSoftDelete(deletion bool) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		l := l.snapshot()
+		if !l.softDeleteFlag {
+			return
+		}
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		db.Statement.Context = context.WithValue(ctx, softDeleteContextKey{}, &softDeleteStatement{
+			statement: db.Statement,
+			deletion:  deletion,
+		})
+	}
+}
+
+// appendSoftDeleteAttribute adds the soft_delete attribute, if the statement of the query was captured by the
+// plugin and is a soft delete or excludes the soft deleted rows
+func (l logger) appendSoftDeleteAttribute(ctx context.Context, args []slog.Attr, query *lazyQuery) []slog.Attr {
+	if !l.softDeleteFlag {
+		return args
+	}
+	captured, _ := ctx.Value(softDeleteContextKey{}).(*softDeleteStatement)
+	if captured == nil {
+		return args
+	}
+	if _, ok := captured.statement.Clauses[softDeleteEnabled]; !ok {
+		return args
+	}
+	if captured.deletion && query.Operation() == "UPDATE" {
+		return append(args, slog.String(SoftDeleteField, SoftDeleteDelete))
+	}
+	return append(args, slog.String(SoftDeleteField, SoftDeleteFilter))
+}
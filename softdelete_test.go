@@ -0,0 +1,86 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// document is a model with soft deletes
+type document struct {
+	ID        int
+	Title     string
+	DeletedAt gorm.DeletedAt
+}
+
+// queryRecord returns the record of the SQL query executed, the other records being those of its transaction
+func queryRecord(t *testing.T, receiver *DummyHandler) slog.Record {
+	t.Helper()
+	for _, r := range receiver.Records {
+		if strings.HasPrefix(r.Message, "SQL query executed") {
+			return r
+		}
+	}
+	require.Fail(t, "record of the SQL query not found")
+	return slog.Record{}
+}
+
+func Test_logger_SoftDeleteFlag(t *testing.T) {
+	t.Run("Soft delete", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSoftDeleteFlag()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Delete(&document{ID: 1}).Error)
+
+		r := queryRecord(t, receiver)
+		assertHasAttr(t, &r, slog.String(SoftDeleteField, SoftDeleteDelete))
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSoftDeleteFlag()})
+		db := openTestDB(t, gormLogger)
+
+		var documents []document
+		require.NoError(t, db.Find(&documents).Error)
+
+		r := queryRecord(t, receiver)
+		assertHasAttr(t, &r, slog.String(SoftDeleteField, SoftDeleteFilter))
+	})
+
+	t.Run("Unscoped", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSoftDeleteFlag()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Unscoped().Delete(&document{ID: 1}).Error)
+
+		r := queryRecord(t, receiver)
+		assertNoAttr(t, &r, SoftDeleteField)
+	})
+
+	t.Run("Without soft deletes", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSoftDeleteFlag()})
+		db := openTestDB(t, gormLogger)
+
+		type user struct {
+			ID   int
+			Name string
+		}
+		require.NoError(t, db.Delete(&user{ID: 1}).Error)
+
+		r := queryRecord(t, receiver)
+		assertNoAttr(t, &r, SoftDeleteField)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Delete(&document{ID: 1}).Error)
+
+		r := queryRecord(t, receiver)
+		assertNoAttr(t, &r, SoftDeleteField)
+	})
+}
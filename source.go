@@ -0,0 +1,241 @@
+package slogGorm
+
+import (
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// maxSourceDepth is the number of frames walked to find the caller of gorm,
+// as utils.FileWithLineNum does.
+const maxSourceDepth = 13
+
+// defaultSourceCacheSize is the default number of call sites whose source is cached
+const defaultSourceCacheSize = 1024
+
+// gormSourceDir is the source directory of gorm, computed as gorm.io/gorm/utils does
+var gormSourceDir = func() string {
+	file, _ := runtime.FuncForPC(reflect.ValueOf(gorm.Open).Pointer()).FileLine(0)
+
+	dir := filepath.Dir(filepath.Dir(file))
+	s := filepath.Dir(dir)
+	if filepath.Base(s) != "gorm.io" {
+		s = dir
+	}
+	return filepath.ToSlash(s) + "/"
+}()
+
+// sourceValuer is a slog.LogValuer resolving the file name and line number of the
+// caller of gorm only when the handler formats the record. The stack is captured
+// when created, but the frames are only resolved and formatted when needed.
+type sourceValuer struct {
+	pcs   [maxSourceDepth]uintptr
+	n     int
+	cache *sourceCache
+}
+
+// newSource captures the stack of the caller of the function calling newSource,
+// as utils.FileWithLineNum does. The frames are resolved through the cache, if any.
+func newSource(cache *sourceCache) *sourceValuer {
+	s := &sourceValuer{cache: cache}
+	// skip [runtime.Callers, this function, this function's caller]
+	s.n = runtime.Callers(3, s.pcs[:])
+	return s
+}
+
+// LogValue implements slog.LogValuer
+func (s *sourceValuer) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// String returns the file name and line number of the first frame outside of gorm
+func (s *sourceValuer) String() string {
+	return s.caller().source
+}
+
+// caller returns the first frame outside of gorm
+func (s *sourceValuer) caller() callerFrame {
+	if s.cache != nil {
+		for _, pc := range s.pcs[:s.n] {
+			if caller := s.cache.resolve(pc); caller.source != "" {
+				return caller
+			}
+		}
+		return callerFrame{}
+	}
+
+	frames := runtime.CallersFrames(s.pcs[:s.n])
+	for {
+		frame, more := frames.Next()
+		if isApplicationFrame(frame) {
+			return newCallerFrame(frame)
+		}
+		if !more {
+			return callerFrame{}
+		}
+	}
+}
+
+// callerFrame is the resolved frame of the caller of gorm
+type callerFrame struct {
+	// source is the file name and line number, empty for the frames within gorm
+	source string
+	// function is the package path-qualified name of the function
+	function string
+}
+
+func newCallerFrame(frame runtime.Frame) callerFrame {
+	return callerFrame{source: frame.File + ":" + strconv.Itoa(frame.Line), function: frame.Function}
+}
+
+// packageFunction splits the package path-qualified name of the function into the package path
+// and the name of the function, e.g. "github.com/app/repository" and "(*Users).Find"
+func (f callerFrame) packageFunction() (string, string) {
+	slash := strings.LastIndexByte(f.function, '/')
+	dot := strings.IndexByte(f.function[slash+1:], '.')
+	if dot < 0 {
+		return "", f.function
+	}
+	dot += slash + 1
+	return f.function[:dot], f.function[dot+1:]
+}
+
+// fileValuer is a slog.LogValuer resolving the file name of the source
+type fileValuer struct{ *sourceValuer }
+
+// LogValue implements slog.LogValuer
+func (v fileValuer) LogValue() slog.Value {
+	file, _ := v.fileLine()
+	return slog.StringValue(file)
+}
+
+// lineValuer is a slog.LogValuer resolving the line number of the source
+type lineValuer struct{ *sourceValuer }
+
+// LogValue implements slog.LogValuer
+func (v lineValuer) LogValue() slog.Value {
+	_, line := v.fileLine()
+	return slog.IntValue(line)
+}
+
+// packageValuer is a slog.LogValuer resolving the package of the function calling gorm
+type packageValuer struct{ *sourceValuer }
+
+// LogValue implements slog.LogValuer
+func (v packageValuer) LogValue() slog.Value {
+	pkg, _ := v.caller().packageFunction()
+	return slog.StringValue(pkg)
+}
+
+// functionValuer is a slog.LogValuer resolving the name of the function calling gorm
+type functionValuer struct{ *sourceValuer }
+
+// LogValue implements slog.LogValuer
+func (v functionValuer) LogValue() slog.Value {
+	_, function := v.caller().packageFunction()
+	return slog.StringValue(function)
+}
+
+// fileLine returns the file name and line number of the source
+func (s *sourceValuer) fileLine() (string, int) {
+	source := s.String()
+	i := strings.LastIndexByte(source, ':')
+	if i < 0 {
+		return source, 0
+	}
+	line, _ := strconv.Atoi(source[i+1:])
+	return source[:i], line
+}
+
+// resolvePC returns the first frame outside of gorm at the given PC, which can hold
+// several frames when functions are inlined.
+func resolvePC(pc uintptr) callerFrame {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		if isApplicationFrame(frame) {
+			return newCallerFrame(frame)
+		}
+		if !more {
+			return callerFrame{}
+		}
+	}
+}
+
+// sourceCache memoizes the resolution of the PCs, so that the queries executed from
+// the same call site don't resolve and format the same frames again. An empty source
+// means that the PC is within gorm. The cache is reset once it holds size PCs.
+type sourceCache struct {
+	mu      sync.RWMutex
+	size    int
+	sources map[uintptr]callerFrame
+}
+
+// newSourceCache creates a cache holding the source of up to size PCs
+func newSourceCache(size int) *sourceCache {
+	return &sourceCache{size: size, sources: make(map[uintptr]callerFrame, size)}
+}
+
+// resolve returns the frame at the given PC, from the cache if already resolved
+func (c *sourceCache) resolve(pc uintptr) callerFrame {
+	c.mu.RLock()
+	caller, ok := c.sources[pc]
+	c.mu.RUnlock()
+	if ok {
+		return caller
+	}
+
+	caller = resolvePC(pc)
+
+	c.mu.Lock()
+	if len(c.sources) >= c.size {
+		clear(c.sources)
+	}
+	c.sources[pc] = caller
+	c.mu.Unlock()
+	return caller
+}
+
+// len returns the number of PCs in the cache
+func (c *sourceCache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.sources)
+}
+
+// isApplicationFrame reports whether the frame is outside of gorm, like utils.FileWithLineNum
+func isApplicationFrame(frame runtime.Frame) bool {
+	return frame.File != "" &&
+		(!strings.HasPrefix(frame.File, gormSourceDir) || strings.HasSuffix(frame.File, "_test.go")) &&
+		!strings.HasSuffix(frame.File, ".gen.go")
+}
+
+// capturesSource reports whether the source of the records is captured, for the source field
+// or the caller function attributes
+func (l logger) capturesSource() bool {
+	return l.sourceField != "" || l.callerFunction
+}
+
+// appendSourceAttribute adds the lazy source attribute, unless the source field is disabled,
+// and the lazy attributes of the caller function, if enabled
+func (l logger) appendSourceAttribute(args []slog.Attr, source *sourceValuer) []slog.Attr {
+	if source == nil {
+		return args
+	}
+	if l.sourceField != "" {
+		args = append(args, slog.Any(l.sourceField, source))
+	}
+	if l.callerFunction {
+		args = append(args,
+			slog.Any(PackageField, packageValuer{source}),
+			slog.Any(FunctionField, functionValuer{source}),
+		)
+	}
+	return args
+}
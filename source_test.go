@@ -0,0 +1,191 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Trace_Source(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	t.Run("Source field", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+
+		_, file, line, _ := runtime.Caller(0)
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(SourceField, file+":"+strconv.Itoa(line+1)))
+	})
+
+	t.Run("Source field through gorm", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+		db := openTestDB(t, gormLogger)
+
+		_, file, line, _ := runtime.Caller(0)
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(SourceField, file+":"+strconv.Itoa(line+1)))
+	})
+
+	t.Run("Without source field", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithoutSourceField(),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertNoAttr(t, receiver.Record, SourceField)
+	})
+}
+
+func Test_logger_Trace_CallerFunction(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	t.Run("Caller function", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithCallerFunction()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(PackageField, "github.com/orandin/slog-gorm"))
+		assertHasAttr(t, &resolved, slog.String(FunctionField, "Test_logger_Trace_CallerFunction.func2"))
+	})
+
+	t.Run("Caller function through gorm", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithCallerFunction()})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(PackageField, "github.com/orandin/slog-gorm"))
+		assertHasAttr(t, &resolved, slog.String(FunctionField, "Test_logger_Trace_CallerFunction.func3"))
+	})
+
+	t.Run("Without source field", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithoutSourceField(),
+			WithCallerFunction(),
+		})
+		assert.NotNil(t, gormLogger.sourceCache)
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertNoAttr(t, receiver.Record, SourceField)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(PackageField, "github.com/orandin/slog-gorm"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertNoAttr(t, receiver.Record, PackageField)
+		assertNoAttr(t, receiver.Record, FunctionField)
+	})
+}
+
+func Test_callerFrame_packageFunction(t *testing.T) {
+	tests := map[string]struct {
+		function, pkg, name string
+	}{
+		"Function":        {function: "github.com/app/repository.Find", pkg: "github.com/app/repository", name: "Find"},
+		"Method":          {function: "github.com/app/repository.(*Users).Find", pkg: "github.com/app/repository", name: "(*Users).Find"},
+		"Dotted path":     {function: "gopkg.in/app.v2/repository.Find.func1", pkg: "gopkg.in/app.v2/repository", name: "Find.func1"},
+		"Main package":    {function: "main.main", pkg: "main", name: "main"},
+		"Unknown package": {function: "find", pkg: "", name: "find"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pkg, function := callerFrame{function: tt.function}.packageFunction()
+
+			assert.Equal(t, tt.pkg, pkg)
+			assert.Equal(t, tt.name, function)
+		})
+	}
+}
+
+func Test_sourceCache(t *testing.T) {
+	fc := func() (string, int64) {
+		return "SELECT * FROM user", 1
+	}
+
+	t.Run("Repeated call site", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll()})
+		require.NotNil(t, gormLogger.sourceCache)
+
+		var size int
+		_, file, line, _ := runtime.Caller(0)
+		for i := 0; i < 3; i++ {
+			gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+			require.NotNil(t, receiver.Record)
+			resolved := resolveRecord(*receiver.Record)
+			assertHasAttr(t, &resolved, slog.String(SourceField, file+":"+strconv.Itoa(line+2)))
+
+			// The frames of the call site are only resolved once
+			if i == 0 {
+				size = gormLogger.sourceCache.len()
+			}
+			assert.Equal(t, size, gormLogger.sourceCache.len())
+		}
+	})
+
+	t.Run("Bounded cache", func(t *testing.T) {
+		cache := newSourceCache(2)
+		for pc := uintptr(1); pc <= 5; pc++ {
+			cache.resolve(pc)
+			assert.LessOrEqual(t, cache.len(), 2)
+		}
+	})
+
+	t.Run("Disabled cache", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+			WithSourceCacheSize(0),
+		})
+		assert.Nil(t, gormLogger.sourceCache)
+
+		_, file, line, _ := runtime.Caller(0)
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		resolved := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &resolved, slog.String(SourceField, file+":"+strconv.Itoa(line+1)))
+	})
+
+	t.Run("Without source field", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{WithoutSourceField()})
+		assert.Nil(t, gormLogger.sourceCache)
+	})
+}
+
+func Test_gormSourceDir(t *testing.T) {
+	assert.Contains(t, gormSourceDir, "gorm.io/")
+	assert.False(t, isApplicationFrame(runtime.Frame{File: gormSourceDir + "gorm@v1.0.0/callbacks.go"}))
+	assert.True(t, isApplicationFrame(runtime.Frame{File: "/app/repository.go"}))
+	assert.False(t, isApplicationFrame(runtime.Frame{File: "/app/query.gen.go"}))
+}
@@ -0,0 +1,39 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// The keys of the identifiers of the span of the SQL queries, following the log data model of OpenTelemetry,
+// see WithSpanContext
+const (
+	TraceIDField = "trace_id"
+	SpanIDField  = "span_id"
+)
+
+// spanOf returns the hexadecimal identifiers of the span of the context, false if WithSpanContext is not
+// used or the context has no valid span
+func (l logger) spanOf(ctx context.Context) (traceID, spanID string, ok bool) {
+	if l.spanContext == nil {
+		return "", "", false
+	}
+	traceID, spanID = l.spanContext(ctx)
+	if !validSpanID(traceID) || !validSpanID(spanID) {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// validSpanID reports whether the hexadecimal identifier of a trace or a span is set, the invalid identifiers of
+// OpenTelemetry being made of zeros
+func validSpanID(id string) bool {
+	return strings.Trim(id, "0") != ""
+}
+
+// withoutQuery removes the SQL query from the attributes, the span carrying it, see WithoutSpanStatement
+func withoutQuery(attrs []slog.Attr) []slog.Attr {
+	return slices.DeleteFunc(attrs, func(attr slog.Attr) bool { return attr.Key == QueryField })
+}
@@ -0,0 +1,73 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_SpanContext(t *testing.T) {
+	spanKey := ctxKey(45)
+	spanContext := func(ctx context.Context) (string, string) {
+		if ctx.Value(spanKey) == nil {
+			return "00000000000000000000000000000000", "0000000000000000"
+		}
+		return "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	}
+	fc := func() (string, int64) { return "SELECT * FROM users", 1 }
+	spanned := context.WithValue(context.Background(), spanKey, true)
+
+	t.Run("Span identifiers", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithSpanContext(spanContext), WithTraceAll()})
+
+		gormLogger.Trace(spanned, time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		r := resolveRecord(*receiver.Record)
+		assertHasAttr(t, &r, slog.String(TraceIDField, "4bf92f3577b34da6a3ce929d0e0e4736"))
+		assertHasAttr(t, &r, slog.String(SpanIDField, "00f067aa0ba902b7"))
+		assertHasAttr(t, &r, slog.String(QueryField, "SELECT * FROM users"))
+	})
+
+	t.Run("Without span", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSpanContext(spanContext),
+			WithoutSpanStatement(),
+			WithTraceAll(),
+		})
+
+		gormLogger.Trace(context.Background(), time.Now(), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		r := resolveRecord(*receiver.Record)
+		assertNoAttr(t, &r, TraceIDField)
+		assertNoAttr(t, &r, SpanIDField)
+		assertHasAttr(t, &r, slog.String(QueryField, "SELECT * FROM users"))
+	})
+
+	t.Run("Without span statement", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSpanContext(spanContext),
+			WithoutSpanStatement(),
+			WithTraceAll(),
+		})
+
+		gormLogger.Trace(spanned, time.Now(), fc, errors.New("awesome error"))
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "awesome error", receiver.Record.Message)
+		assertNoAttr(t, receiver.Record, QueryField)
+		assertHasAttr(t, receiver.Record, slog.String(SpanIDField, "00f067aa0ba902b7"))
+	})
+}
+
+func Test_validSpanID(t *testing.T) {
+	assert.True(t, validSpanID("00f067aa0ba902b7"))
+	assert.False(t, validSpanID("0000000000000000"))
+	assert.False(t, validSpanID(""))
+}
@@ -0,0 +1,650 @@
+package slogGorm
+
+import (
+	"slices"
+	"strings"
+)
+
+// tokenKind is the kind of token of a SQL query
+type tokenKind int
+
+const (
+	tokenSpace        tokenKind = iota // whitespaces
+	tokenComment                       // -- comment, /* comment */
+	tokenString                        // 'string', E'string', $$string$$
+	tokenDoubleQuoted                  // "identifier" or "string", depending on the dialect
+	tokenIdentifier                    // `identifier`, [identifier]
+	tokenNumber                        // 42, 4.2, 4e2, 0x2A
+	tokenWord                          // keywords and unquoted identifiers
+	tokenPlaceholder                   // ?, $1, :name, @name
+	tokenPunctuation                   // operators, parentheses, commas...
+)
+
+// token is a token of a SQL query, its text is a slice of the query
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// value returns the text of the identifier or word, without its quotes
+func (t token) value() string {
+	switch t.kind {
+	case tokenDoubleQuoted, tokenIdentifier:
+		if len(t.text) >= 2 {
+			return t.text[1 : len(t.text)-1]
+		}
+	}
+	return t.text
+}
+
+// isKeyword reports whether the token is the given keyword (in uppercase)
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// escapeMode defines whether the backslashes escape the quotes of the strings, which depends on the dialect
+// (e.g. MySQL) and on its configuration (e.g. standard_conforming_strings for PostgreSQL)
+type escapeMode int
+
+const (
+	// escapeAmbiguous resolves the strings whose end depends on the escapes as the union of both interpretations
+	escapeAmbiguous escapeMode = iota
+	// escapeBackslash escapes the quotes with a backslash, or by doubling them
+	escapeBackslash
+	// escapeDoubled escapes the quotes by doubling them only
+	escapeDoubled
+)
+
+// tokenize calls fn for each token of the SQL query, until fn returns false.
+// The unterminated strings, identifiers and comments extend until the end of the query.
+func tokenize(sql string, fn func(token) bool) {
+	for i := 0; i < len(sql); {
+		kind, end := scanToken(sql, i)
+		if !fn(token{kind: kind, text: sql[i:end]}) {
+			return
+		}
+		i = end
+	}
+}
+
+// scanToken returns the kind and the end of the token starting at i. As the dialect is unknown, the strings
+// whose end depends on the escapes are the union of the interpretations, so that no literal is left outside
+// of a string whatever the dialect.
+func scanToken(sql string, i int) (tokenKind, int) {
+	return scanTokenIn(sql, i, escapeAmbiguous)
+}
+
+// scanTokenIn returns the kind and the end of the token starting at i, with the given escapes of the quotes
+func scanTokenIn(sql string, i int, mode escapeMode) (tokenKind, int) {
+	c := sql[i]
+	switch {
+	case isSpace(c):
+		j := i + 1
+		for j < len(sql) && isSpace(sql[j]) {
+			j++
+		}
+		return tokenSpace, j
+
+	case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+		j := strings.IndexByte(sql[i:], '\n')
+		if j < 0 {
+			return tokenComment, len(sql)
+		}
+		return tokenComment, i + j + 1
+
+	case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+		return tokenComment, scanBlockComment(sql, i)
+
+	case c == '\'':
+		end, _ := scanString(sql, i, '\'', mode)
+		return tokenString, end
+
+	case (c == 'E' || c == 'e') && i+1 < len(sql) && sql[i+1] == '\'':
+		// The escaped strings always support the backslashes
+		return tokenString, scanQuoted(sql, i+1, '\'', true)
+
+	case (c == 'N' || c == 'n' || c == 'X' || c == 'x' || c == 'B' || c == 'b') && i+1 < len(sql) && sql[i+1] == '\'':
+		// Prefixed strings: N'national', X'hexadecimal', B'binary'
+		end, _ := scanString(sql, i+1, '\'', mode)
+		return tokenString, end
+
+	case c == '"':
+		// The ambiguous double-quoted texts are strings, as they may hide literals
+		end, ambiguous := scanString(sql, i, '"', mode)
+		if ambiguous {
+			return tokenString, end
+		}
+		return tokenDoubleQuoted, end
+
+	case c == '`':
+		return tokenIdentifier, scanQuoted(sql, i, '`', false)
+
+	case c == '[':
+		j := strings.IndexByte(sql[i+1:], ']')
+		if j < 0 {
+			return tokenIdentifier, len(sql)
+		}
+		return tokenIdentifier, i + j + 2
+
+	case c == '$':
+		if end, ok := scanDollarQuoted(sql, i); ok {
+			return tokenString, end
+		}
+		j := i + 1
+		for j < len(sql) && isDigit(sql[j]) {
+			j++
+		}
+		if j > i+1 {
+			return tokenPlaceholder, j
+		}
+		return tokenPunctuation, i + 1
+
+	case c == '?':
+		return tokenPlaceholder, i + 1
+
+	case (c == ':' || c == '@') && i+1 < len(sql) && isWordStart(sql[i+1]) && (i == 0 || sql[i-1] != c):
+		j := i + 1
+		for j < len(sql) && isWordPart(sql[j]) {
+			j++
+		}
+		return tokenPlaceholder, j
+
+	case isDigit(c) || (c == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+		return tokenNumber, scanNumber(sql, i)
+
+	case isWordStart(c):
+		j := i + 1
+		for j < len(sql) && isWordPart(sql[j]) {
+			j++
+		}
+		return tokenWord, j
+	}
+
+	return tokenPunctuation, i + 1
+}
+
+// scanQuoted returns the end of the quoted text starting at i. The quote is escaped
+// by doubling it, or with a backslash if backslash is true.
+func scanQuoted(sql string, i int, quote byte, backslash bool) int {
+	for j := i + 1; j < len(sql); j++ {
+		switch sql[j] {
+		case '\\':
+			if backslash {
+				j++
+			}
+		case quote:
+			if j+1 < len(sql) && sql[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(sql)
+}
+
+// scanString returns the end of the quoted text starting at i with the given escapes. With ambiguous escapes,
+// the backslashes may or may not escape the quotes: if both interpretations end the text at different positions,
+// the text extends until they reach a same position outside of the quoted texts, e.g. "'C:\', 'secret'" is a
+// single string, and ambiguous is true.
+func scanString(sql string, i int, quote byte, mode escapeMode) (end int, ambiguous bool) {
+	if mode != escapeAmbiguous {
+		return scanQuoted(sql, i, quote, mode == escapeBackslash), false
+	}
+
+	escaped, doubled := scanQuoted(sql, i, quote, true), scanQuoted(sql, i, quote, false)
+	ambiguous = escaped != doubled
+	for escaped != doubled {
+		if escaped < doubled {
+			escaped = skipTokens(sql, escaped, doubled, escapeBackslash)
+		} else {
+			doubled = skipTokens(sql, doubled, escaped, escapeDoubled)
+		}
+	}
+	return escaped, ambiguous
+}
+
+// skipTokens returns the end of the first token ending at or after target, from the token starting at i
+func skipTokens(sql string, i, target int, mode escapeMode) int {
+	for i < target {
+		_, i = scanTokenIn(sql, i, mode)
+	}
+	return i
+}
+
+// scanBlockComment returns the end of the block comment starting at i, supporting nested comments
+func scanBlockComment(sql string, i int) int {
+	depth := 0
+	for j := i; j+1 < len(sql); j++ {
+		switch {
+		case sql[j] == '/' && sql[j+1] == '*':
+			depth++
+			j++
+		case sql[j] == '*' && sql[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(sql)
+}
+
+// scanDollarQuoted returns the end of the dollar-quoted string ($$text$$ or $tag$text$tag$) starting at i
+func scanDollarQuoted(sql string, i int) (int, bool) {
+	j := i + 1
+	for j < len(sql) && sql[j] != '$' {
+		if !isWordPart(sql[j]) || isDigit(sql[i+1]) {
+			return 0, false
+		}
+		j++
+	}
+	if j >= len(sql) {
+		return 0, false
+	}
+
+	tag := sql[i : j+1]
+	end := strings.Index(sql[j+1:], tag)
+	if end < 0 {
+		return len(sql), true
+	}
+	return j + 1 + end + len(tag), true
+}
+
+// scanNumber returns the end of the number starting at i
+func scanNumber(sql string, i int) int {
+	j := i
+	if sql[j] == '0' && j+1 < len(sql) && (sql[j+1] == 'x' || sql[j+1] == 'X') {
+		j += 2
+		for j < len(sql) && isHexDigit(sql[j]) {
+			j++
+		}
+		return j
+	}
+
+	for j < len(sql) && (isDigit(sql[j]) || sql[j] == '.') {
+		j++
+	}
+	if j < len(sql) && (sql[j] == 'e' || sql[j] == 'E') {
+		k := j + 1
+		if k < len(sql) && (sql[k] == '+' || sql[k] == '-') {
+			k++
+		}
+		if k < len(sql) && isDigit(sql[k]) {
+			j = k
+			for j < len(sql) && isDigit(sql[j]) {
+				j++
+			}
+		}
+	}
+	return j
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c >= 0x80
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '$'
+}
+
+// parseOperation returns the operation of the SQL query in uppercase (SELECT, INSERT, ...).
+// For common table expressions (WITH ...), it is the operation of the main statement.
+func parseOperation(sql string) string {
+	var (
+		operation string
+		depth     int
+		cte       bool
+	)
+
+	tokenize(sql, func(t token) bool {
+		switch {
+		case t.kind == tokenSpace || t.kind == tokenComment:
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case depth > 0:
+		case t.kind != tokenWord:
+			if !cte {
+				return false
+			}
+		case !cte:
+			operation = strings.ToUpper(t.text)
+			if operation != "WITH" {
+				return false
+			}
+			cte = true
+		case t.isKeyword("SELECT") || t.isKeyword("INSERT") || t.isKeyword("UPDATE") || t.isKeyword("DELETE"):
+			operation = strings.ToUpper(t.text)
+			return false
+		}
+		return true
+	})
+
+	return operation
+}
+
+// parseTable returns the main table of the SQL query, without its quotes
+func parseTable(sql string) string {
+	var (
+		table     string
+		depth     int
+		expecting bool // whether the next name is a table
+		name      strings.Builder
+		inName    bool
+	)
+
+	tokenize(sql, func(t token) bool {
+		if t.kind == tokenSpace || t.kind == tokenComment {
+			return true
+		}
+
+		if inName {
+			// Qualified name: schema.table
+			if t.text == "." {
+				name.WriteByte('.')
+				return true
+			}
+			if isName(t) && strings.HasSuffix(name.String(), ".") {
+				name.WriteString(t.value())
+				return true
+			}
+			table = name.String()
+			return false
+		}
+
+		switch {
+		case t.text == "(":
+			depth++
+			expecting = false
+		case t.text == ")":
+			depth--
+			expecting = false
+		case depth > 0:
+		case isTableKeyword(t):
+			expecting = true
+		case expecting && isTableModifier(t):
+		case expecting && isName(t):
+			name.WriteString(t.value())
+			inName = true
+		default:
+			expecting = false
+		}
+		return true
+	})
+
+	if inName && table == "" {
+		table = name.String()
+	}
+	return table
+}
+
+// parseTables returns the tables referenced by the SQL query, including its joins, its lists of tables
+// (FROM a, b) and its subqueries, without their quotes, in the order of appearance and without duplicates.
+// The names of the common table expressions (WITH name AS (...)) are not tables.
+func parseTables(sql string) []string {
+	var (
+		tables    []string
+		ctes      []string
+		expecting bool // whether the next name is a table
+		listing   bool // whether a table was read, which may be followed by an alias and a comma
+		aliased   bool // whether the alias of the table was read
+		name      strings.Builder
+		inName    bool
+		// functions are the parentheses opened, whether they are the arguments of a function whose
+		// arguments include a FROM keyword, e.g. EXTRACT(YEAR FROM created_at)
+		functions []bool
+		// the previous significant tokens, to recognize the common table expressions
+		previous, beforePrevious token
+	)
+	add := func(table string) {
+		if !slices.Contains(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+
+	tokenize(sql, func(t token) bool {
+		if t.kind == tokenSpace || t.kind == tokenComment {
+			return true
+		}
+		defer func() {
+			previous, beforePrevious = t, previous
+		}()
+
+		if inName {
+			// Qualified name: schema.table
+			if t.text == "." {
+				name.WriteByte('.')
+				return true
+			}
+			if isName(t) && strings.HasSuffix(name.String(), ".") {
+				name.WriteString(t.value())
+				return true
+			}
+			add(name.String())
+			name.Reset()
+			inName, listing, aliased = false, true, false
+		}
+
+		switch {
+		case t.text == "(":
+			if previous.isKeyword("AS") && isName(beforePrevious) {
+				ctes = append(ctes, beforePrevious.value())
+			}
+			functions = append(functions, isFromFunction(previous))
+			expecting, listing = false, false
+		case t.text == ")":
+			if len(functions) > 0 {
+				functions = functions[:len(functions)-1]
+			}
+			expecting, listing = false, false
+		case t.text == ",":
+			expecting, listing = listing, false
+		case len(functions) > 0 && functions[len(functions)-1]:
+		case isTableKeyword(t):
+			expecting, listing = true, false
+		case expecting && isTableModifier(t):
+		case expecting && isName(t):
+			name.WriteString(t.value())
+			inName, expecting = true, false
+		case listing && t.isKeyword("AS"):
+		case listing && !aliased && isName(t):
+			aliased = true
+		default:
+			expecting, listing = false, false
+		}
+		return true
+	})
+
+	if inName {
+		add(name.String())
+	}
+	return slices.DeleteFunc(tables, func(table string) bool {
+		return slices.Contains(ctes, table)
+	})
+}
+
+// isFromFunction reports whether the token is a function whose arguments include a FROM keyword
+func isFromFunction(t token) bool {
+	for _, function := range []string{"EXTRACT", "SUBSTRING", "TRIM", "POSITION", "OVERLAY"} {
+		if t.isKeyword(function) {
+			return true
+		}
+	}
+	return false
+}
+
+// isName reports whether the token can be the name of a table
+func isName(t token) bool {
+	return t.kind == tokenWord || t.kind == tokenIdentifier || t.kind == tokenDoubleQuoted
+}
+
+// isTableKeyword reports whether the token is a keyword followed by the name of a table
+func isTableKeyword(t token) bool {
+	for _, keyword := range []string{"FROM", "INTO", "UPDATE", "TABLE", "JOIN", "TRUNCATE"} {
+		if t.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTableModifier reports whether the token is a keyword which can precede the name of a table
+func isTableModifier(t token) bool {
+	for _, keyword := range []string{"TABLE", "IF", "NOT", "EXISTS", "ONLY", "IGNORE", "LOW_PRIORITY"} {
+		if t.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint returns the structure of the SQL query without its values: the strings, numbers and
+// placeholders are replaced by "?", the lists of values like "IN (?, ?, ?)" are collapsed to "IN (?)",
+// the comments are removed and the whitespaces are collapsed. The identifiers are kept, except the
+// double-quoted ones used as values (the strings of MySQL), see doubleQuotedValues.
+func fingerprint(sql string) string {
+	var (
+		b      strings.Builder
+		space  bool // whether a space is pending
+		list   bool // whether the last token written is a value, possibly followed by a comma
+		comma  bool // whether a comma is pending after a value
+		offset int  // offset of the current token
+		quoted []int
+	)
+	b.Grow(len(sql))
+	if strings.IndexByte(sql, '"') >= 0 {
+		quoted = doubleQuotedValues(sql)
+	}
+
+	tokenize(sql, func(t token) bool {
+		start := offset
+		offset += len(t.text)
+
+		kind := t.kind
+		if kind == tokenDoubleQuoted && len(quoted) > 0 && quoted[0] == start {
+			kind, quoted = tokenString, quoted[1:]
+		}
+		switch kind {
+		case tokenSpace, tokenComment:
+			space = b.Len() > 0
+			return true
+		case tokenString, tokenNumber, tokenPlaceholder:
+			if list && comma {
+				// Collapse the list of values
+				comma, space = false, false
+				return true
+			}
+			if comma {
+				b.WriteByte(',')
+				comma = false
+			}
+			if space {
+				b.WriteByte(' ')
+				space = false
+			}
+			b.WriteByte('?')
+			list = true
+			return true
+		}
+
+		if list && t.text == "," && !comma {
+			comma, space = true, false
+			return true
+		}
+		if comma {
+			b.WriteByte(',')
+			comma = false
+		}
+		list = false
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteString(t.text)
+		return true
+	})
+	if comma {
+		b.WriteByte(',')
+	}
+
+	return b.String()
+}
+
+// doubleQuotedValues returns the offsets of the double-quoted tokens used as values, in order: the ones
+// compared with an operator or LIKE, and the ones listed by IN or VALUES, e.g. "email = "a@b.c"" or
+// "VALUES ("a", "b")". The qualifiers of the names, like "users" in "users"."id", are not values.
+func doubleQuotedValues(sql string) []int {
+	var (
+		offsets    []int
+		offset     int
+		pending    = -1 // offset of the candidate, a value unless followed by a dot
+		prev       token
+		lists      []bool // whether each open parenthesis is a list of values
+		closedList bool   // whether the last parenthesis closed is a list of values
+	)
+
+	tokenize(sql, func(t token) bool {
+		start := offset
+		offset += len(t.text)
+		if t.kind == tokenSpace || t.kind == tokenComment {
+			return true
+		}
+
+		if pending >= 0 {
+			if t.text != "." {
+				offsets = append(offsets, pending)
+			}
+			pending = -1
+		}
+
+		switch {
+		case t.kind == tokenDoubleQuoted && isValuePosition(prev, lists):
+			pending = start
+		case t.text == "(":
+			lists = append(lists, prev.isKeyword("IN") || prev.isKeyword("VALUES") || (prev.text == "," && closedList))
+		case t.text == ")":
+			if len(lists) > 0 {
+				closedList = lists[len(lists)-1]
+				lists = lists[:len(lists)-1]
+			}
+		}
+		if t.text != ")" && t.text != "," {
+			closedList = false
+		}
+		prev = t
+		return true
+	})
+	if pending >= 0 {
+		offsets = append(offsets, pending)
+	}
+	return offsets
+}
+
+// isValuePosition reports whether the token following prev is a value, the innermost parenthesis open being
+// a list of values if the last element of lists is true
+func isValuePosition(prev token, lists []bool) bool {
+	switch {
+	case prev.kind == tokenPunctuation && (prev.text == "=" || prev.text == "<" || prev.text == ">"):
+		return true
+	case prev.isKeyword("LIKE") || prev.isKeyword("ILIKE"):
+		return true
+	case prev.text == "(" || prev.text == ",":
+		return len(lists) > 0 && lists[len(lists)-1]
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package slogGorm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// fuzzQueries are the seeds of the fuzz tests: pathological quotes, escapes, comments and dollar quotes
+var fuzzQueries = []string{
+	"SELECT * FROM users WHERE email = 'john@example.com' AND age > 42",
+	"INSERT INTO `users` (`name`,`email`) VALUES ('john','john@example.com'),('jane','jane@example.com')",
+	`UPDATE "users" SET "email" = 'it''s' WHERE "id" = 1`,
+	`SELECT 'a\'b', E'c\\'d', N'e', X'0F', B'01' FROM t`,
+	"SELECT $$secret$$, $tag$it's $$ nested$tag$, $1 FROM t",
+	"SELECT /* outer /* nested */ still */ 'secret' -- trailing 'comment'\nFROM t",
+	"SELECT 'unterminated",
+	"SELECT \"unterminated",
+	"SELECT /* unterminated 'secret'",
+	"SELECT $tag$unterminated 'secret'",
+	"SELECT [id] FROM [users] WHERE x = 0x2A OR y = 1.5e-3 OR z = .5",
+	"SELECT * FROM t WHERE a = ? AND b = :name AND c = @p1 AND d::text = 'x'",
+	"SELECT 1;DROP TABLE users;--",
+	"SELECT '\\\\', '\\'', '''', ''''''",
+	"SELECT\t\r\n\f\v1",
+	"SELECT 'é', 'ü' FROM \xff\xfe",
+	`INSERT INTO files (path, owner) VALUES ('C:\\', 'secret@example.com')`,
+	`SELECT * FROM t WHERE "x\" = 'secret' AND email = "a@b.c" AND "t"."id" IN ("1", "2")`,
+}
+
+func FuzzTokenize(f *testing.F) {
+	for _, sql := range fuzzQueries {
+		f.Add(sql)
+	}
+	f.Fuzz(func(t *testing.T, sql string) {
+		var b strings.Builder
+		tokenize(sql, func(tok token) bool {
+			if tok.text == "" {
+				t.Fatalf("empty token in %q", sql)
+			}
+			b.WriteString(tok.text)
+			return true
+		})
+		if b.String() != sql {
+			t.Fatalf("tokens of %q joined as %q", sql, b.String())
+		}
+
+		// The parsers never panic
+		_ = parseOperation(sql)
+		_ = parseTable(sql)
+		_ = detectAnomalies(sql)
+	})
+}
+
+func FuzzFingerprint(f *testing.F) {
+	for _, sql := range fuzzQueries {
+		f.Add(sql)
+	}
+	f.Fuzz(func(t *testing.T, sql string) {
+		fp := fingerprint(sql)
+		if literals := unredactedLiterals(fp); len(literals) > 0 {
+			t.Fatalf("fingerprint of %q leaks %q: %q", sql, literals, fp)
+		}
+		if values := doubleQuotedValues(fp); len(values) > 0 {
+			t.Fatalf("fingerprint of %q leaks double-quoted values: %q", sql, fp)
+		}
+		if again := fingerprint(fp); again != fp {
+			t.Fatalf("fingerprint of %q is not stable: %q then %q", sql, fp, again)
+		}
+		if utf8.ValidString(sql) && !utf8.ValidString(fp) {
+			t.Fatalf("fingerprint of %q is not valid UTF-8: %q", sql, fp)
+		}
+	})
+}
+
+func FuzzMaskColumns(f *testing.F) {
+	for _, sql := range fuzzQueries {
+		f.Add(sql)
+	}
+	columns := []maskedColumn{{name: "email", column: "email"}, {name: "users.name", table: "users", column: "name"}}
+	f.Fuzz(func(t *testing.T, sql string) {
+		masked := maskColumns(sql, columns)
+		if masked == sql {
+			return
+		}
+		// The masked values are replaced by tokens, but the other tokens are kept
+		if strings.Count(masked, maskedValue) <= strings.Count(sql, maskedValue) {
+			t.Fatalf("masked query of %q has no masked value: %q", sql, masked)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package slogGorm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tokenize(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []token
+	}{
+		{
+			sql: "SELECT * FROM `users` WHERE name = 'o''neil' AND id = 4.2e1",
+			want: []token{
+				{tokenWord, "SELECT"}, {tokenSpace, " "}, {tokenPunctuation, "*"}, {tokenSpace, " "},
+				{tokenWord, "FROM"}, {tokenSpace, " "}, {tokenIdentifier, "`users`"}, {tokenSpace, " "},
+				{tokenWord, "WHERE"}, {tokenSpace, " "}, {tokenWord, "name"}, {tokenSpace, " "},
+				{tokenPunctuation, "="}, {tokenSpace, " "}, {tokenString, "'o''neil'"}, {tokenSpace, " "},
+				{tokenWord, "AND"}, {tokenSpace, " "}, {tokenWord, "id"}, {tokenSpace, " "},
+				{tokenPunctuation, "="}, {tokenSpace, " "}, {tokenNumber, "4.2e1"},
+			},
+		},
+		{
+			sql: `"a" /* c /* nested */ */-- line` + "\n" + `$1::int`,
+			want: []token{
+				{tokenDoubleQuoted, `"a"`}, {tokenSpace, " "}, {tokenComment, "/* c /* nested */ */"},
+				{tokenComment, "-- line\n"}, {tokenPlaceholder, "$1"}, {tokenPunctuation, ":"},
+				{tokenPunctuation, ":"}, {tokenWord, "int"},
+			},
+		},
+		{
+			sql: `$tag$it's$tag$ E'\'' [id] ? :name 0x2A`,
+			want: []token{
+				{tokenString, "$tag$it's$tag$"}, {tokenSpace, " "}, {tokenString, `E'\''`}, {tokenSpace, " "},
+				{tokenIdentifier, "[id]"}, {tokenSpace, " "}, {tokenPlaceholder, "?"}, {tokenSpace, " "},
+				{tokenPlaceholder, ":name"}, {tokenSpace, " "}, {tokenNumber, "0x2A"},
+			},
+		},
+		{
+			sql:  "'unterminated",
+			want: []token{{tokenString, "'unterminated"}},
+		},
+		{
+			// The backslash may end the string (PostgreSQL) or escape the quote (MySQL)
+			sql:  `('C:\', 'secret') x`,
+			want: []token{{tokenPunctuation, "("}, {tokenString, `'C:\', 'secret') x`}},
+		},
+		{
+			sql:  `'a\\b' "x\" = 'secret' AND "y" = 1`,
+			want: []token{{tokenString, `'a\\b'`}, {tokenSpace, " "}, {tokenString, `"x\" = 'secret' AND "y" = 1`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			var actual []token
+			tokenize(tt.sql, func(t token) bool {
+				actual = append(actual, t)
+				return true
+			})
+
+			assert.Equal(t, tt.want, actual)
+			assert.Equal(t, tt.sql, joinTokens(actual))
+		})
+	}
+}
+
+func Test_parseOperation(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users":                                        "SELECT",
+		"  insert INTO users (name) VALUES ('john')":                 "INSERT",
+		"/* comment */ UPDATE users SET name = 'john'":               "UPDATE",
+		"WITH cte AS (SELECT * FROM users) DELETE FROM users":        "DELETE",
+		"with cte as (select 1), cte2 as (select 2) select * from t": "SELECT",
+		"CREATE TABLE users (id int)":                                "CREATE",
+		"":                                                           "",
+	}
+
+	for sql, want := range tests {
+		t.Run(sql, func(t *testing.T) {
+			assert.Equal(t, want, parseOperation(sql))
+		})
+	}
+}
+
+func Test_parseTable(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users WHERE id = 1":                          "users",
+		"SELECT * FROM `users` JOIN `orders` ON orders.user_id = 1": "users",
+		`SELECT * FROM "public"."users"`:                            "public.users",
+		"SELECT count(*) FROM (SELECT * FROM users) AS t":           "",
+		"SELECT EXTRACT(YEAR FROM created_at) FROM users":           "users",
+		"INSERT INTO users (name) VALUES ('john')":                  "users",
+		"INSERT IGNORE INTO `users` (`name`) VALUES ('john')":       "users",
+		"UPDATE LOW_PRIORITY users SET name = 'john'":               "users",
+		"DELETE FROM [dbo].[users] WHERE id = 1":                    "dbo.users",
+		"CREATE TABLE IF NOT EXISTS users (id int)":                 "users",
+		"DROP TABLE users":                                          "users",
+		"TRUNCATE users":                                            "users",
+		"SELECT 1":                                                  "",
+		"WITH cte AS (SELECT * FROM orders) SELECT * FROM cte":      "cte",
+	}
+
+	for sql, want := range tests {
+		t.Run(sql, func(t *testing.T) {
+			assert.Equal(t, want, parseTable(sql))
+		})
+	}
+}
+
+func Test_parseTables(t *testing.T) {
+	tests := map[string][]string{
+		"SELECT * FROM users WHERE id = 1":                                           {"users"},
+		"SELECT * FROM `users` JOIN `orders` ON orders.user_id = users.id":           {"users", "orders"},
+		"SELECT * FROM users u LEFT JOIN public.orders AS o ON o.user_id = u.id":     {"users", "public.orders"},
+		"SELECT * FROM users, orders o, items WHERE users.id = o.user_id":            {"users", "orders", "items"},
+		"SELECT count(*) FROM (SELECT * FROM users) AS t":                            {"users"},
+		"SELECT * FROM users WHERE id IN (SELECT user_id FROM orders JOIN users)":    {"users", "orders"},
+		"SELECT EXTRACT(YEAR FROM created_at) FROM users":                            {"users"},
+		"INSERT INTO archive (id, name) SELECT id, name FROM users":                  {"archive", "users"},
+		"UPDATE users SET name = 'a', email = 'b' FROM orders WHERE orders.id = 1":   {"users", "orders"},
+		"WITH cte AS (SELECT * FROM orders), recent AS (SELECT 1) SELECT * FROM cte": {"orders"},
+		"SELECT a, b FROM t ORDER BY a, b":                                           {"t"},
+		"SELECT 1":                                                                   nil,
+	}
+
+	for sql, want := range tests {
+		t.Run(sql, func(t *testing.T) {
+			assert.Equal(t, want, parseTables(sql))
+		})
+	}
+}
+
+// private helpers
+
+func joinTokens(tokens []token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+func Test_fingerprint(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{sql: "SELECT * FROM `users` WHERE name = 'john' AND id IN (1, 2, 3)", want: "SELECT * FROM `users` WHERE name = ? AND id IN (?)"},
+		{sql: "INSERT INTO users (name,age) VALUES ('a',1),('b',2)", want: "INSERT INTO users (name,age) VALUES (?),(?)"},
+		{sql: "UPDATE users SET a = $1, b = $2 WHERE id = $3", want: "UPDATE users SET a = ?, b = ? WHERE id = ?"},
+		{sql: "SELECT   *\n\tFROM t /* comment */ WHERE x = -5.2 -- comment", want: "SELECT * FROM t WHERE x = -?"},
+		{sql: `SELECT "users"."id" FROM "users" WHERE "email" = E'a@b.c' LIMIT 1`, want: `SELECT "users"."id" FROM "users" WHERE "email" = ? LIMIT ?`},
+		{sql: `SELECT * FROM users WHERE email = "a@b.c" OR name LIKE "j%" OR id IN ("1", "2")`, want: "SELECT * FROM users WHERE email = ? OR name LIKE ? OR id IN (?)"},
+		{sql: `INSERT INTO "users" ("name","email") VALUES ("a","b"),("c","d")`, want: `INSERT INTO "users" ("name","email") VALUES (?),(?)`},
+		{sql: `SELECT * FROM "a" JOIN "b" ON "a"."id" = "b"."a_id"`, want: `SELECT * FROM "a" JOIN "b" ON "a"."id" = "b"."a_id"`},
+		{sql: `INSERT INTO files (path, owner) VALUES ('C:\', 'secret@example.com')`, want: "INSERT INTO files (path, owner) VALUES (?"},
+		{sql: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			assert.Equal(t, tt.want, fingerprint(tt.sql))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+)
+
+// ErrStartupCheck is the error wrapped by the errors of the startup self-check, see LogStartupSummary
+var ErrStartupCheck = errors.New("slog-gorm: startup self-check failed")
+
+// LogStartupSummary logs a record describing the effective configuration of the logger (levels,
+// thresholds, filters, redaction...), at the StartupLogType level (slog.LevelInfo by default), and
+// verifies that the handler writes it, so that a misconfiguration is caught at boot rather than
+// during an incident. The record is written synchronously, even in asynchronous mode.
+//
+// It returns an error wrapping ErrStartupCheck if the handler does not accept the records at the
+// StartupLogType level or fails to write the record, which is then written by the fallback handler
+// if any, see WithFallbackHandler.
+//
+// Usage:
+//
+//	if err := gormLogger.LogStartupSummary(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+func (l logger) LogStartupSummary(ctx context.Context) error {
+	l = l.snapshot()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := l.logLevel[StartupLogType]
+	if !l.enabled(ctx, level) {
+		return fmt.Errorf("%w: the records at level %s are not logged", ErrStartupCheck, level)
+	}
+
+	var pcs [1]uintptr
+	// skip [runtime.Callers, this function]
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(l.clock.Now(), level, StartupMessage, pcs[0])
+	r.AddAttrs(l.convertKeys(l.startupAttrs(ctx))...)
+	r.AddAttrs(l.convertKeys(l.recordResource)...)
+	if l.name != "" {
+		r.AddAttrs(l.convertKeys([]slog.Attr{slog.String(NameField, l.name)})...)
+	}
+	if l.buildInfo != buildInfoNone {
+		r.AddAttrs(l.convertKeys([]slog.Attr{l.buildInfoAttr()})...)
+	}
+
+	// The record is written with the handler rather than the output, to report its failure even if the
+	// fallback handler writes it
+	var fallback slog.Record
+	if l.fallbackHandler != nil {
+		fallback = r.Clone()
+	}
+	err := l.withResource(l.sloggerHandler).Handle(ctx, r)
+	if err == nil {
+		return nil
+	}
+	l.handlerFailures.Add(1)
+	l.lastHandlerError.set(err, l.clock.Now())
+	if l.fallbackHandler != nil && l.fallbackHandler.Enabled(ctx, level) {
+		_ = l.withResource(l.fallbackHandler).Handle(ctx, fallback)
+	}
+	return fmt.Errorf("%w: the handler failed to write the record: %w", ErrStartupCheck, err)
+}
+
+// startupAttrs returns the attributes of the startup summary, describing the effective configuration
+func (l logger) startupAttrs(ctx context.Context) []slog.Attr {
+	config := l.Config()
+
+	attrs := []slog.Attr{
+		slog.String("handler", fmt.Sprintf("%T", l.sloggerHandler)),
+		slog.Bool("fallback_handler", l.fallbackHandler != nil),
+		slog.Duration("slow_threshold", config.SlowThreshold),
+		slog.Duration("transaction_watchdog", config.TransactionWatchdog),
+		slog.Bool("trace_all", config.TraceAll),
+		slog.Bool("ignore_trace", config.IgnoreTrace),
+		slog.Float64("sampling_rate", config.SamplingRate),
+	}
+	if config.MinLevel != nil {
+		attrs = append(attrs, slog.String("min_level", config.MinLevel.String()))
+	}
+
+	// The log types are sorted to keep the record stable, those not logged being listed apart
+	logTypes := make([]LogType, 0, len(l.logLevel))
+	for logType := range l.logLevel {
+		logTypes = append(logTypes, logType)
+	}
+	slices.Sort(logTypes)
+	levels := make([]slog.Attr, 0, len(logTypes))
+	var disabled []string
+	for _, logType := range logTypes {
+		levels = append(levels, slog.String(string(logType), l.logLevel[logType].String()))
+		if !l.enabled(ctx, l.logLevel[logType]) {
+			disabled = append(disabled, string(logType))
+		}
+	}
+	attrs = append(attrs, slog.Attr{Key: "levels", Value: slog.GroupValue(levels...)})
+	if len(disabled) > 0 {
+		attrs = append(attrs, slog.Any("disabled", disabled))
+	}
+
+	// The filters given as functions cannot be described, they are only counted
+	custom := 0
+	for _, filter := range l.filters {
+		if filter.describe == nil {
+			custom++
+		}
+	}
+	attrs = append(attrs, slog.Group("filters",
+		slog.Any("ignored_operations", config.IgnoredOperations),
+		slog.Any("ignored_tables", config.IgnoredTables),
+		slog.Any("ignored_queries", config.IgnoredQueries),
+		slog.Int("custom", custom),
+	))
+
+	attrs = append(attrs, slog.Group("redaction",
+		slog.Bool("parameterized_queries", config.ParameterizedQueries),
+		slog.Bool("no_values", config.NoValues),
+		slog.Any("masked_columns", config.MaskedColumns),
+		slog.Bool("scrubber", l.scrubber != nil),
+		slog.Bool("verification", l.redactionCheck != nil),
+	))
+
+	if config.AsyncBufferSize > 0 {
+		attrs = append(attrs,
+			slog.Int("async_buffer_size", config.AsyncBufferSize),
+			slog.String("async_drop_policy", config.AsyncDropPolicy.String()),
+		)
+	}
+	return attrs
+}
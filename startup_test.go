@@ -0,0 +1,104 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_LogStartupSummary(t *testing.T) {
+	t.Run("Configuration", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithSlowThreshold(500 * time.Millisecond),
+			WithTraceAll(),
+			WithIgnoredTables("sessions"),
+			WithIgnoredQueries(regexp.MustCompile(`^SELECT 1$`)),
+			WithQueryFilter(func(context.Context, QueryInfo) bool { return false }),
+			WithMaskedColumns("users.email"),
+			WithNoValues(),
+			WithMinLevel(slog.LevelInfo),
+		})
+
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		require.Equal(t, 1, receiver.Len())
+		r := receiver.Records[0]
+		assert.Equal(t, StartupMessage, r.Message)
+		assert.Equal(t, slog.LevelInfo, r.Level)
+		assertHasAttr(t, &r, slog.Duration("slow_threshold", 500*time.Millisecond))
+		assertHasAttr(t, &r, slog.Bool("trace_all", true))
+		assertHasAttr(t, &r, slog.String("min_level", "INFO"))
+
+		values := startupValues(r)
+		assert.Equal(t, []string{string(DecisionLogType), string(FullQueryLogType)}, values["disabled"].Any())
+		assert.Equal(t, "WARN", values["levels.slow_query"].String())
+		assert.Equal(t, []string{"sessions"}, values["filters.ignored_tables"].Any())
+		assert.Equal(t, []string{"^SELECT 1$"}, values["filters.ignored_queries"].Any())
+		assert.Equal(t, int64(1), values["filters.custom"].Int64())
+		assert.True(t, values["redaction.no_values"].Bool())
+		assert.Equal(t, []string{"users.email"}, values["redaction.masked_columns"].Any())
+		assert.False(t, values["redaction.scrubber"].Bool())
+	})
+
+	t.Run("Written synchronously in asynchronous mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithAsync(4)})
+		defer gormLogger.Close()
+
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		require.Equal(t, 1, receiver.Len())
+		assertHasAttr(t, &receiver.Records[0], slog.Int("async_buffer_size", 4))
+	})
+
+	t.Run("Level not logged", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithMinLevel(slog.LevelWarn)})
+
+		err := gormLogger.LogStartupSummary(context.Background())
+
+		assert.ErrorIs(t, err, ErrStartupCheck)
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Failing handler", func(t *testing.T) {
+		fallback := NewDummyHandler()
+		gormLogger := New(WithHandler(failingHandler{}), WithFallbackHandler(fallback)).(*logger)
+
+		err := gormLogger.LogStartupSummary(context.Background())
+
+		assert.ErrorIs(t, err, ErrStartupCheck)
+		require.NotNil(t, fallback.Record)
+		assert.Equal(t, StartupMessage, fallback.Record.Message)
+		assert.Equal(t, uint64(1), gormLogger.Stats().HandlerFailures)
+	})
+
+	t.Run("Custom level", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{SetLogLevel(StartupLogType, slog.LevelWarn)})
+
+		require.NoError(t, gormLogger.LogStartupSummary(context.Background()))
+
+		require.Equal(t, 1, receiver.Len())
+		assert.Equal(t, slog.LevelWarn, receiver.Records[0].Level)
+	})
+}
+
+// startupValues returns the values of the attributes of the record by key, the keys of the attributes
+// of the groups being prefixed by their group and a dot
+func startupValues(r slog.Record) map[string]slog.Value {
+	values := map[string]slog.Value{}
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Value.Kind() != slog.KindGroup {
+			values[attr.Key] = attr.Value
+			return true
+		}
+		for _, a := range attr.Value.Group() {
+			values[attr.Key+"."+a.Key] = a.Value
+		}
+		return true
+	})
+	return values
+}
@@ -0,0 +1,130 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats reports the internal counters of the logger
+type Stats struct {
+	// Dropped is the number of records discarded by the asynchronous mode because its buffer was full
+	Dropped uint64
+	// SubscriptionDropped is the number of query events discarded because the buffer of a channel
+	// subscribed with Subscribe was full
+	SubscriptionDropped uint64
+	// HandlerFailures is the number of records the handler failed to write, written by the fallback handler
+	// if any, see WithFallbackHandler
+	HandlerFailures uint64
+	// Filtered is the number of SQL records suppressed by the filters, see WithIgnoredOperations and WithQueryFilter
+	Filtered uint64
+	// SampledOut is the number of SQL records discarded by the sampling, see WithSamplingRate and WithVolumeBudget
+	SampledOut uint64
+	// Queries are the aggregates of the queries by fingerprint, sorted by decreasing count, see WithQueryStats
+	Queries []QueryStats
+	// Routes are the aggregates of the queries by route, sorted by decreasing total duration, see WithRouteStats
+	Routes []AttrStats
+	// Jobs are the aggregates of the queries by background job, sorted by decreasing total duration, see WithJobStats
+	Jobs []AttrStats
+}
+
+// counters counts the records suppressed by the logger, shared by the copies of the logger and the loggers
+// derived with With
+type counters struct {
+	processed  atomic.Uint64
+	filtered   atomic.Uint64
+	sampledOut atomic.Uint64
+}
+
+// Stats returns the internal counters of the logger
+func (l logger) Stats() Stats {
+	l = l.snapshot()
+	stats := l.counterStats()
+	if l.queryStats != nil {
+		stats.Queries = l.queryStats.snapshot()
+	}
+	if l.routeStats != nil {
+		stats.Routes = l.routeStats.snapshot()
+	}
+	if l.jobStats != nil {
+		stats.Jobs = l.jobStats.snapshot()
+	}
+	return stats
+}
+
+// counterStats returns the counters of the logger, without the aggregates of the queries
+func (l logger) counterStats() Stats {
+	var stats Stats
+	if l.async != nil {
+		stats.Dropped = l.async.dropped.Load()
+	}
+	if l.subscriptions != nil {
+		stats.SubscriptionDropped = l.subscriptions.dropped.Load()
+	}
+	if l.handlerFailures != nil {
+		stats.HandlerFailures = l.handlerFailures.Load()
+	}
+	if l.counters != nil {
+		stats.Filtered = l.counters.filtered.Load()
+		stats.SampledOut = l.counters.sampledOut.Load()
+	}
+	return stats
+}
+
+// statsSummary logs the counters of the logger periodically, see WithStatsSummary
+type statsSummary struct {
+	interval time.Duration
+	// next is the time of the next summary in Unix nanoseconds, zero until the first query traced
+	next atomic.Int64
+
+	mu       sync.Mutex
+	reported Stats
+}
+
+// due reports whether the summary is to be logged at the given time, only one caller being reported per interval
+func (s *statsSummary) due(now time.Time) bool {
+	next := s.next.Load()
+	if next == 0 {
+		s.next.CompareAndSwap(0, now.Add(s.interval).UnixNano())
+		return false
+	}
+	if now.UnixNano() < next {
+		return false
+	}
+	return s.next.CompareAndSwap(next, now.Add(s.interval).UnixNano())
+}
+
+// reportStats logs the counters incremented since the last summary, if the summary is due and
+// any counter was incremented
+func (l logger) reportStats() {
+	if l.statsSummary == nil || l.silenced(StatsLogType) || !l.statsSummary.due(l.clock.Now()) {
+		return
+	}
+
+	total := l.counterStats()
+	l.statsSummary.mu.Lock()
+	last := l.statsSummary.reported
+	l.statsSummary.reported = total
+	l.statsSummary.mu.Unlock()
+
+	delta := Stats{
+		Dropped:             total.Dropped - last.Dropped,
+		SubscriptionDropped: total.SubscriptionDropped - last.SubscriptionDropped,
+		HandlerFailures:     total.HandlerFailures - last.HandlerFailures,
+		Filtered:            total.Filtered - last.Filtered,
+		SampledOut:          total.SampledOut - last.SampledOut,
+	}
+	if delta.Dropped == 0 && delta.SubscriptionDropped == 0 && delta.HandlerFailures == 0 && delta.Filtered == 0 && delta.SampledOut == 0 {
+		return
+	}
+
+	l.logAttrs(context.Background(), l.logLevel[StatsLogType], StatsMessage,
+		slog.Uint64(FilteredField, delta.Filtered),
+		slog.Uint64(SampledOutField, delta.SampledOut),
+		slog.Uint64(DroppedField, delta.Dropped),
+		slog.Uint64(SubscriptionDroppedField, delta.SubscriptionDropped),
+		slog.Uint64(HandlerFailuresField, delta.HandlerFailures),
+	)
+}
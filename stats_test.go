@@ -0,0 +1,98 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Stats_Suppressed(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Filtered", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithIgnoredTables("sessions")})
+
+		trace(gormLogger, "SELECT * FROM sessions")
+		trace(gormLogger, "SELECT * FROM users")
+
+		assert.Equal(t, 1, receiver.Len())
+		assert.Equal(t, uint64(1), gormLogger.Stats().Filtered)
+		assert.Zero(t, gormLogger.Stats().SampledOut)
+	})
+
+	t.Run("Sampled out", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithTraceAll(), WithSamplingRate(0)})
+
+		trace(gormLogger, "SELECT * FROM users")
+
+		assert.Nil(t, receiver.Record)
+		assert.Equal(t, uint64(1), gormLogger.Stats().SampledOut)
+	})
+
+	t.Run("Shared with the derived loggers", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger([]Option{WithIgnoredTables("sessions")})
+
+		trace(gormLogger.with(WithTraceAll()), "SELECT * FROM sessions")
+
+		assert.Equal(t, uint64(1), gormLogger.Stats().Filtered)
+	})
+}
+
+func Test_logger_StatsSummary(t *testing.T) {
+	receiver, gormLogger := getReceiverAndLogger([]Option{
+		WithTraceAll(),
+		WithIgnoredTables("sessions"),
+		WithStatsSummary(time.Hour),
+	})
+	trace := func(sql string) {
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	// The first query schedules the first summary
+	trace("SELECT * FROM sessions")
+	trace("SELECT * FROM sessions")
+	assert.Nil(t, receiver.Record)
+
+	gormLogger.statsSummary.next.Store(time.Now().Add(-time.Second).UnixNano())
+	trace("SELECT * FROM users")
+	require.Len(t, receiver.Records, 2)
+	summary := receiver.Records[0]
+	assert.Equal(t, StatsMessage, summary.Message)
+	assert.Equal(t, slog.LevelInfo, summary.Level)
+	assertHasAttr(t, &summary, slog.Uint64(FilteredField, 2))
+	assertHasAttr(t, &summary, slog.Uint64(SampledOutField, 0))
+
+	// Not logged before the interval
+	trace("SELECT * FROM sessions")
+	assert.Equal(t, 2, receiver.Len())
+
+	// Only the counters incremented since the last summary are reported
+	gormLogger.statsSummary.next.Store(time.Now().Add(-time.Second).UnixNano())
+	trace("SELECT * FROM users")
+	require.Len(t, receiver.Records, 4)
+	summary = receiver.Records[2]
+	assert.Equal(t, StatsMessage, summary.Message)
+	assertHasAttr(t, &summary, slog.Uint64(FilteredField, 1))
+
+	// Not logged if no counter was incremented
+	gormLogger.statsSummary.next.Store(time.Now().Add(-time.Second).UnixNano())
+	trace("SELECT * FROM users")
+	assert.Equal(t, 5, receiver.Len())
+}
+
+func Test_statsSummary_due(t *testing.T) {
+	summary := &statsSummary{interval: time.Minute}
+	now := time.Now()
+
+	assert.False(t, summary.due(now))
+	assert.False(t, summary.due(now.Add(30*time.Second)))
+	assert.True(t, summary.due(now.Add(time.Minute)))
+	assert.False(t, summary.due(now.Add(time.Minute)))
+	assert.True(t, summary.due(now.Add(2*time.Minute)))
+}
@@ -0,0 +1,80 @@
+package slogGorm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// subscriptions are the channels of the query events subscribed with Subscribe, shared by
+// the copies of the logger and the loggers derived with With
+type subscriptions struct {
+	mu       sync.RWMutex
+	channels map[chan QueryEvent]struct{}
+	// active is the number of channels, to check it without locking
+	active  atomic.Int64
+	dropped atomic.Uint64
+}
+
+// HandleQuery implements Sink, sending the event to each channel whose buffer is not full
+func (s *subscriptions) HandleQuery(_ context.Context, event QueryEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.channels {
+		select {
+		case ch <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// subscribe adds a channel of the given buffer size
+func (s *subscriptions) subscribe(buffer int) (<-chan QueryEvent, func()) {
+	if buffer < 0 {
+		buffer = 0
+	}
+	ch := make(chan QueryEvent, buffer)
+
+	s.mu.Lock()
+	if s.channels == nil {
+		s.channels = make(map[chan QueryEvent]struct{})
+	}
+	s.channels[ch] = struct{}{}
+	s.active.Add(1)
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.channels, ch)
+			s.active.Add(-1)
+			close(ch)
+			s.mu.Unlock()
+		})
+	}
+}
+
+// Subscribe returns a channel receiving the events of the queries, like a Sink, to observe them in-process
+// (e.g. in the tests, the dashboards or the anomaly detectors) without touching the logging pipeline, and
+// the function to unsubscribe, closing the channel. The events are never waited for: they are dropped when
+// the buffer of the channel is full, and counted by Stats. The channel receives the queries of the copies
+// of the logger and of the loggers derived with With.
+//
+// Usage:
+//
+//	events, unsubscribe := gormLogger.Subscribe(64)
+//	defer unsubscribe()
+//	for event := range events {
+//		// ...
+//	}
+func (l logger) Subscribe(buffer int) (<-chan QueryEvent, func()) {
+	if l.subscriptions == nil {
+		// Not created by New
+		ch := make(chan QueryEvent)
+		close(ch)
+		return ch, func() {}
+	}
+	return l.subscriptions.subscribe(buffer)
+}
@@ -0,0 +1,80 @@
+package slogGorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Subscribe(t *testing.T) {
+	trace := func(l *logger, sql string) {
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	t.Run("Query events", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{WithIgnoreTrace()})
+		events, unsubscribe := gormLogger.Subscribe(4)
+
+		trace(gormLogger, "SELECT * FROM users")
+		unsubscribe()
+		trace(gormLogger, "SELECT * FROM orders")
+
+		assert.Nil(t, receiver.Record)
+		var received []QueryEvent
+		for event := range events {
+			received = append(received, event)
+		}
+		require.Len(t, received, 1)
+		assert.Equal(t, "SELECT * FROM users", received[0].SQL)
+
+		// Unsubscribing twice is a no-op
+		unsubscribe()
+		assert.False(t, gormLogger.hasSinks())
+	})
+
+	t.Run("Full buffer", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger(nil)
+		events, unsubscribe := gormLogger.Subscribe(1)
+		defer unsubscribe()
+
+		trace(gormLogger, "SELECT 1")
+		trace(gormLogger, "SELECT 2")
+
+		assert.Equal(t, "SELECT 1", (<-events).SQL)
+		assert.Equal(t, uint64(1), gormLogger.Stats().SubscriptionDropped)
+	})
+
+	t.Run("Several subscribers", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger(nil)
+		first, unsubscribeFirst := gormLogger.Subscribe(1)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := gormLogger.Subscribe(1)
+		defer unsubscribeSecond()
+
+		trace(gormLogger, "SELECT 1")
+
+		assert.Equal(t, "SELECT 1", (<-first).SQL)
+		assert.Equal(t, "SELECT 1", (<-second).SQL)
+	})
+
+	t.Run("Derived logger", func(t *testing.T) {
+		_, gormLogger := getReceiverAndLogger(nil)
+		events, unsubscribe := gormLogger.Subscribe(1)
+		defer unsubscribe()
+
+		trace(gormLogger.with(WithTraceAll()), "SELECT 1")
+
+		assert.Equal(t, "SELECT 1", (<-events).SQL)
+	})
+
+	t.Run("Logger not created by New", func(t *testing.T) {
+		events, unsubscribe := logger{}.Subscribe(1)
+		defer unsubscribe()
+
+		_, ok := <-events
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,60 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// slowThresholdOverride is the slow threshold of the SQL queries whose context attribute has the given
+// value, see WithSlowThresholdFor
+type slowThresholdOverride struct {
+	key       string
+	value     string
+	threshold time.Duration
+}
+
+// addSlowThreshold registers the override, replacing the one of the same attribute and value if any
+func (l *logger) addSlowThreshold(override slowThresholdOverride) {
+	for i := range l.slowThresholds {
+		if l.slowThresholds[i].key == override.key && l.slowThresholds[i].value == override.value {
+			l.slowThresholds[i] = override
+			return
+		}
+	}
+	l.slowThresholds = append(l.slowThresholds, override)
+}
+
+// slowThresholdOf returns the slow threshold of the SQL queries of the context: the threshold of the first
+// override matching its attributes, the threshold of the logger otherwise
+func (l logger) slowThresholdOf(ctx context.Context) time.Duration {
+	for _, override := range l.slowThresholds {
+		if value, ok := l.contextValue(ctx, override.key); ok && value.String() == override.value {
+			return override.threshold
+		}
+	}
+	return l.slowThreshold
+}
+
+// contextValue returns the value of the context attribute of the given name (the tenant being named
+// TenantField, see WithTenant), false if it is not registered or missing from the context
+func (l logger) contextValue(ctx context.Context, name string) (slog.Value, bool) {
+	if name == TenantField && l.tenant != nil {
+		tenant, ok := l.tenant(ctx)
+		return slog.StringValue(tenant), ok
+	}
+	for i := range l.contextAttrs {
+		attr := &l.contextAttrs[i]
+		if attr.name != name {
+			continue
+		}
+		if attr.fn != nil {
+			return attr.fn(ctx)
+		}
+		if value := ctx.Value(attr.key); value != nil {
+			return slog.AnyValue(value).Resolve(), true
+		}
+		return slog.Value{}, false
+	}
+	return slog.Value{}, false
+}
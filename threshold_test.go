@@ -0,0 +1,83 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_SlowThresholdFor(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	fc := func() (string, int64) { return "SELECT * FROM reports", 1 }
+	tenantKey := ctxKey(42)
+	options := []Option{
+		WithClock(fixedClock{now: now}),
+		WithSlowThreshold(100 * time.Millisecond),
+		WithTenant(func(ctx context.Context) (string, bool) {
+			tenant, ok := ctx.Value(tenantKey).(string)
+			return tenant, ok
+		}),
+		WithContextValue("plan", ctxKey1),
+		WithSlowThresholdFor(TenantField, "acme", time.Second),
+		WithSlowThresholdFor("plan", "enterprise", 2*time.Second),
+	}
+
+	t.Run("Tenant", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+		ctx := context.WithValue(context.Background(), tenantKey, "acme")
+
+		gormLogger.Trace(ctx, now.Add(-500*time.Millisecond), fc, nil)
+		assert.Nil(t, receiver.Record)
+
+		gormLogger.Trace(ctx, now.Add(-1500*time.Millisecond), fc, nil)
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "slow sql query [1.5s >= 1s]", receiver.Record.Message)
+	})
+
+	t.Run("Context attribute", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+		ctx := context.WithValue(context.Background(), ctxKey1, "enterprise")
+
+		gormLogger.Trace(ctx, now.Add(-1500*time.Millisecond), fc, nil)
+		assert.Nil(t, receiver.Record)
+
+		gormLogger.Trace(ctx, now.Add(-2500*time.Millisecond), fc, nil)
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.Duration(DurationField, 2500*time.Millisecond))
+	})
+
+	t.Run("First override matching", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+		ctx := context.WithValue(context.WithValue(context.Background(), tenantKey, "acme"), ctxKey1, "enterprise")
+
+		gormLogger.Trace(ctx, now.Add(-1500*time.Millisecond), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "slow sql query [1.5s >= 1s]", receiver.Record.Message)
+	})
+
+	t.Run("Other tenants", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(options)
+		ctx := context.WithValue(context.Background(), tenantKey, "globex")
+
+		gormLogger.Trace(ctx, now.Add(-500*time.Millisecond), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "slow sql query [500ms >= 100ms]", receiver.Record.Message)
+	})
+
+	t.Run("Decision", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(append(options, WithDecisionDebug(), WithMinLevel(slog.LevelDebug)))
+		ctx := context.WithValue(context.Background(), tenantKey, "acme")
+
+		gormLogger.Trace(ctx, now.Add(-500*time.Millisecond), fc, nil)
+
+		require.NotNil(t, receiver.Record)
+		assertHasAttr(t, receiver.Record, slog.String(DecisionField, decisionBelowSlowThreshold))
+		assertHasAttr(t, receiver.Record, slog.Duration(ThresholdField, time.Second))
+	})
+}
@@ -0,0 +1,73 @@
+package slogGorm
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// The keys of the attributes of the spans of the SQL queries, following the semantic conventions of
+// OpenTelemetry, see WithTracer
+const (
+	DBStatementField    = "db.statement"
+	DBOperationField    = "db.operation"
+	DBSQLTableField     = "db.sql.table"
+	DBRowsAffectedField = "db.rows_affected"
+)
+
+// QuerySpan is the span of an SQL query, recorded by the Tracer once the query is executed
+type QuerySpan struct {
+	// Name is the operation and the main table of the query (e.g. "SELECT users"), "SQL" if they are unknown
+	Name  string
+	Start time.Time
+	End   time.Time
+	// Attrs are db.statement, redacted like the records, db.operation, db.sql.table and db.rows_affected,
+	// the empty ones being omitted
+	Attrs []slog.Attr
+	// Err is the error of the query, if any, for the error status of the span
+	Err error
+}
+
+// Tracer records the spans of the SQL queries, e.g. with a tracer of OpenTelemetry, see WithTracer
+type Tracer interface {
+	// RecordSpan records the span of a query as a child of the span of the context, if any. It is called
+	// synchronously by the query.
+	RecordSpan(ctx context.Context, span QuerySpan)
+}
+
+// TracerFunc is a function implementing Tracer
+type TracerFunc func(ctx context.Context, span QuerySpan)
+
+// RecordSpan implements Tracer
+func (f TracerFunc) RecordSpan(ctx context.Context, span QuerySpan) {
+	f(ctx, span)
+}
+
+// recordSpan hands the span of the query of the event to the tracer
+func (l logger) recordSpan(ctx context.Context, event QueryEvent) {
+	span := QuerySpan{
+		Name:  "SQL",
+		Start: event.Time,
+		End:   event.Time.Add(event.Duration),
+		Attrs: make([]slog.Attr, 0, 4),
+		Err:   event.Err,
+	}
+	switch {
+	case event.Operation != "" && event.Table != "":
+		span.Name = event.Operation + " " + event.Table
+	case event.Operation != "":
+		span.Name = event.Operation
+	}
+
+	span.Attrs = append(span.Attrs, slog.String(DBStatementField, event.SQL))
+	if event.Operation != "" {
+		span.Attrs = append(span.Attrs, slog.String(DBOperationField, event.Operation))
+	}
+	if event.Table != "" {
+		span.Attrs = append(span.Attrs, slog.String(DBSQLTableField, event.Table))
+	}
+	if event.Rows >= 0 {
+		span.Attrs = append(span.Attrs, slog.Int64(DBRowsAffectedField, event.Rows))
+	}
+	l.tracer.RecordSpan(ctx, span)
+}
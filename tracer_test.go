@@ -0,0 +1,74 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_Tracer(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	var (
+		spans    []QuerySpan
+		contexts []context.Context
+	)
+	tracer := TracerFunc(func(ctx context.Context, span QuerySpan) {
+		contexts = append(contexts, ctx)
+		spans = append(spans, span)
+	})
+
+	t.Run("Spans", func(t *testing.T) {
+		spans, contexts = nil, nil
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(fixedClock{now: now}),
+			WithTracer(tracer),
+			WithIgnoreTrace(),
+		})
+		ctx := context.WithValue(context.Background(), ctxKey1, "parent")
+
+		gormLogger.Trace(ctx, now.Add(-time.Millisecond), func() (string, int64) {
+			return "SELECT * FROM users WHERE id = 42", 1
+		}, nil)
+		gormLogger.Trace(ctx, now.Add(-2*time.Millisecond), func() (string, int64) {
+			return "BEGIN", -1
+		}, errors.New("awesome error"))
+
+		assert.Nil(t, receiver.Record)
+		require.Len(t, spans, 2)
+		assert.Equal(t, QuerySpan{
+			Name:  "SELECT users",
+			Start: now.Add(-time.Millisecond),
+			End:   now,
+			Attrs: []slog.Attr{
+				slog.String(DBStatementField, "SELECT * FROM users WHERE id = 42"),
+				slog.String(DBOperationField, "SELECT"),
+				slog.String(DBSQLTableField, "users"),
+				slog.Int64(DBRowsAffectedField, 1),
+			},
+		}, spans[0])
+		assert.Equal(t, "parent", contexts[0].Value(ctxKey1))
+		assert.Equal(t, "BEGIN", spans[1].Name)
+		assert.Equal(t, []slog.Attr{
+			slog.String(DBStatementField, "BEGIN"),
+			slog.String(DBOperationField, "BEGIN"),
+		}, spans[1].Attrs)
+		assert.EqualError(t, spans[1].Err, "awesome error")
+	})
+
+	t.Run("Redacted statement", func(t *testing.T) {
+		spans = nil
+		_, gormLogger := getReceiverAndLogger([]Option{WithTracer(tracer), WithNoValues()})
+
+		gormLogger.Trace(context.Background(), now, func() (string, int64) {
+			return "SELECT * FROM users WHERE name = 'john'", 1
+		}, nil)
+
+		require.Len(t, spans, 1)
+		assert.Equal(t, slog.String(DBStatementField, "SELECT * FROM users WHERE name = ?"), spans[0].Attrs[0])
+	})
+}
@@ -0,0 +1,431 @@
+package slogGorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const pluginName = "slog-gorm"
+
+// txContextKey is the context key under which the current transaction is stored
+type txContextKey struct{}
+
+// savepointContextKey is the context key under which the savepoint operation of a query is stored
+type savepointContextKey struct{}
+
+// savepointOperations lists the savepoint operations, the longest prefixes first
+var savepointOperations = []string{
+	"ROLLBACK TO SAVEPOINT",
+	"RELEASE SAVEPOINT",
+	"ROLLBACK TO",
+	"SAVEPOINT",
+}
+
+// savepoint describes a savepoint operation
+type savepoint struct {
+	operation string
+	name      string
+}
+
+// savepointName returns the name of the savepoint, empty if there is none
+func savepointName(sp *savepoint) string {
+	if sp == nil {
+		return ""
+	}
+	return sp.name
+}
+
+// savepointOperation returns the operation of the savepoint (e.g. RELEASE SAVEPOINT), empty if there is none
+func savepointOperation(sp *savepoint) string {
+	if sp == nil {
+		return ""
+	}
+	return sp.operation
+}
+
+// Name implements gorm.Plugin
+func (l logger) Name() string {
+	return pluginName
+}
+
+// Initialize implements gorm.Plugin. It wraps the connection pool of the database
+// to follow the transactions, and registers the callbacks correlating the SQL
+// queries with the transaction they are executed in.
+//
+// Usage:
+//
+//	db.Use(gormLogger)
+func (l logger) Initialize(db *gorm.DB) error {
+	pool := &txTrackingPool{ConnPool: db.ConnPool, logger: l}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+
+	callbacks := db.Callback()
+	name := pluginName + ":transaction"
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register(name, correlateTransaction),
+		callbacks.Query().Before("*").Register(name, correlateTransaction),
+		callbacks.Update().Before("*").Register(name, correlateTransaction),
+		callbacks.Delete().Before("*").Register(name, correlateTransaction),
+		callbacks.Row().Before("*").Register(name, correlateTransaction),
+		callbacks.Raw().Before("*").Register(name, correlateTransaction),
+		callbacks.Raw().Before("*").Register(pluginName+":savepoint", detectSavepoint),
+		callbacks.Raw().Before("*").Register(pluginName+":ddl", detectDDL),
+		callbacks.Create().Before("*").Register(pluginName+":dialect", l.detectDialect()),
+		callbacks.Query().Before("*").Register(pluginName+":dialect", l.detectDialect()),
+		callbacks.Update().Before("*").Register(pluginName+":dialect", l.detectDialect()),
+		callbacks.Delete().Before("*").Register(pluginName+":dialect", l.detectDialect()),
+		callbacks.Row().Before("*").Register(pluginName+":dialect", l.detectDialect()),
+		callbacks.Raw().Before("*").Register(pluginName+":dialect", l.detectDialect()),
+		callbacks.Create().Before("*").Register(pluginName+":full_query", l.captureFullQuery()),
+		callbacks.Query().Before("*").Register(pluginName+":full_query", l.captureFullQuery()),
+		callbacks.Update().Before("*").Register(pluginName+":full_query", l.captureFullQuery()),
+		callbacks.Delete().Before("*").Register(pluginName+":full_query", l.captureFullQuery()),
+		callbacks.Row().Before("*").Register(pluginName+":full_query", l.captureFullQuery()),
+		callbacks.Raw().Before("*").Register(pluginName+":full_query", l.captureFullQuery()),
+		callbacks.Create().Before("*").Register(pluginName+":bind_params", l.captureStatement()),
+		callbacks.Query().Before("*").Register(pluginName+":bind_params", l.captureStatement()),
+		callbacks.Update().Before("*").Register(pluginName+":bind_params", l.captureStatement()),
+		callbacks.Delete().Before("*").Register(pluginName+":bind_params", l.captureStatement()),
+		callbacks.Row().Before("*").Register(pluginName+":bind_params", l.captureStatement()),
+		callbacks.Raw().Before("*").Register(pluginName+":bind_params", l.captureStatement()),
+		callbacks.Query().Before("*").Register(pluginName+":soft_delete", l.captureSoftDelete(false)),
+		callbacks.Update().Before("*").Register(pluginName+":soft_delete", l.captureSoftDelete(false)),
+		callbacks.Delete().Before("*").Register(pluginName+":soft_delete", l.captureSoftDelete(true)),
+		callbacks.Row().Before("*").Register(pluginName+":soft_delete", l.captureSoftDelete(false)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// correlateTransaction stores the current transaction into the statement context
+// so that Trace can retrieve it.
+func correlateTransaction(db *gorm.DB) {
+	tx := trackedTransaction(db.Statement.ConnPool)
+	if tx == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if current, _ := ctx.Value(txContextKey{}).(*trackedTx); current != tx {
+		db.Statement.Context = context.WithValue(ctx, txContextKey{}, tx)
+	}
+}
+
+// detectSavepoint stores the savepoint operation executed by the query, if any,
+// into the statement context so that Trace can retrieve it.
+func detectSavepoint(db *gorm.DB) {
+	sp, ok := parseSavepoint(db.Statement.SQL.String())
+	if !ok {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	db.Statement.Context = context.WithValue(ctx, savepointContextKey{}, sp)
+}
+
+// parseSavepoint returns the savepoint operation executed by the given query, if any
+func parseSavepoint(sql string) (*savepoint, bool) {
+	sql = strings.TrimSpace(sql)
+	for _, operation := range savepointOperations {
+		if len(sql) <= len(operation) || !strings.EqualFold(sql[:len(operation)], operation) || sql[len(operation)] != ' ' {
+			continue
+		}
+
+		name := strings.TrimSpace(strings.TrimSuffix(sql[len(operation):], ";"))
+		if name == "" || strings.ContainsAny(name, " \t\n") {
+			return nil, false
+		}
+		if operation == "ROLLBACK TO" {
+			operation = "ROLLBACK TO SAVEPOINT"
+		}
+		return &savepoint{operation: operation, name: strings.Trim(name, "`\"'[]")}, true
+	}
+	return nil, false
+}
+
+// savepointFromContext returns the savepoint operation stored into the given context, if any
+func savepointFromContext(ctx context.Context) *savepoint {
+	if ctx == nil {
+		return nil
+	}
+	sp, _ := ctx.Value(savepointContextKey{}).(*savepoint)
+	return sp
+}
+
+// trackedTransaction returns the tracked transaction behind the given connection pool, if any
+func trackedTransaction(pool gorm.ConnPool) *trackedTx {
+	switch p := pool.(type) {
+	case *trackedTx:
+		return p
+	case *gorm.PreparedStmtTX:
+		tx, _ := p.Tx.(*trackedTx)
+		return tx
+	}
+	return nil
+}
+
+// appendTransactionAttributes adds the index of the statement within its transaction, if any
+func appendTransactionAttributes(index int64, args []slog.Attr) []slog.Attr {
+	if index > 0 {
+		args = append(args, slog.Int64(TxStmtIndexField, index))
+	}
+	return args
+}
+
+// transactionFromContext returns the transaction correlated with the given context, if any
+func transactionFromContext(ctx context.Context) *trackedTx {
+	if ctx == nil {
+		return nil
+	}
+	tx, _ := ctx.Value(txContextKey{}).(*trackedTx)
+	return tx
+}
+
+// txTrackingPool wraps the connection pool of gorm to follow the transactions
+type txTrackingPool struct {
+	gorm.ConnPool
+	logger logger
+}
+
+// BeginTx implements gorm.ConnPoolBeginner
+func (p *txTrackingPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	var (
+		tx  gorm.ConnPool
+		err error
+	)
+
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case gorm.ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	l := p.logger.snapshot()
+	t := &trackedTx{ConnPool: tx, pool: p, ctx: ctx, begin: l.clock.Now()}
+	if l.txWatchdogThreshold > 0 && !l.ignoreTrace {
+		threshold := l.txWatchdogThreshold
+		source := newSource(l.sourceCache)
+		t.watchdog = time.AfterFunc(threshold, func() {
+			p.logger.logLongTransaction(t, threshold, source)
+		})
+	}
+	return t, nil
+}
+
+// GetDBConn implements gorm.GetDBConnector
+func (p *txTrackingPool) GetDBConn() (*sql.DB, error) {
+	switch pool := p.ConnPool.(type) {
+	case gorm.GetDBConnector:
+		return pool.GetDBConn()
+	case *sql.DB:
+		return pool, nil
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// trackedTx wraps a transaction to log its outcome
+type trackedTx struct {
+	gorm.ConnPool
+	pool     *txTrackingPool
+	ctx      context.Context
+	begin    time.Time
+	watchdog *time.Timer
+
+	mu         sync.Mutex
+	statements int64
+	lastErr    error
+}
+
+// track records a statement executed within the transaction and returns its index
+func (t *trackedTx) track(err error) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.statements++
+	if err != nil {
+		t.lastErr = err
+	}
+	return t.statements
+}
+
+// end stops following the transaction
+func (t *trackedTx) end() {
+	if t.watchdog != nil {
+		t.watchdog.Stop()
+	}
+}
+
+// Commit implements gorm.TxCommitter
+func (t *trackedTx) Commit() error {
+	committer, ok := t.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+
+	t.end()
+	err := committer.Commit()
+	t.pool.logger.logCommit(t, err, newSource(t.pool.logger.snapshot().sourceCache))
+	return err
+}
+
+// Rollback implements gorm.TxCommitter and logs the rollback of the transaction
+func (t *trackedTx) Rollback() error {
+	committer, ok := t.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+
+	t.end()
+	err := committer.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		// The transaction is already committed or rolled back
+		return err
+	}
+
+	t.pool.logger.logRollback(t, newSource(t.pool.logger.snapshot().sourceCache))
+	return err
+}
+
+// StmtContext implements gorm.Tx
+func (t *trackedTx) StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	if tx, ok := t.ConnPool.(interface {
+		StmtContext(context.Context, *sql.Stmt) *sql.Stmt
+	}); ok {
+		return tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+// GetDBConn implements gorm.GetDBConnector
+func (t *trackedTx) GetDBConn() (*sql.DB, error) {
+	return t.pool.GetDBConn()
+}
+
+// logCommit logs the commit of the given transaction, when all SQL messages are traced
+func (l logger) logCommit(t *trackedTx, err error, source *sourceValuer) {
+	l = l.snapshot()
+	if l.ignoreTrace || (!l.traceAll && l.gormLevel != gormlogger.Info) || l.silenced(CommitLogType) {
+		return // Silent
+	}
+
+	t.mu.Lock()
+	statements := t.statements
+	t.mu.Unlock()
+
+	elapsed := l.clock.Since(t.begin)
+	if l.noValues {
+		err = withoutValues(err)
+	}
+
+	attributes := []slog.Attr{
+		slog.Int64(StatementsField, statements),
+		slog.Duration(DurationField, elapsed),
+	}
+	attributes = l.appendSourceAttribute(attributes, source)
+	if err != nil {
+		attributes = append(attributes, slog.Any(l.errorField, err))
+	}
+
+	attributes = l.appendContextAttributes(t.ctx, attributes)
+	attributes = l.scrub(nil, attributes)
+	msg, custom := l.formatMessage(t.ctx, messageEvent{logType: CommitLogType, elapsed: elapsed, statements: statements, err: err})
+	if !custom {
+		msg = CommitMessage
+	}
+	level := l.logLevel[CommitLogType]
+	l.logAttrs(t.ctx, level, msg, l.applyPreset(t.ctx, CommitLogType, level, nil, attributes)...)
+}
+
+// logRollback logs the rollback of the given transaction
+func (l logger) logRollback(t *trackedTx, source *sourceValuer) {
+	l = l.snapshot()
+	if l.ignoreTrace || l.silenced(RollbackLogType) {
+		return // Silent
+	}
+
+	t.mu.Lock()
+	statements, lastErr := t.statements, t.lastErr
+	t.mu.Unlock()
+	if l.noValues {
+		lastErr = withoutValues(lastErr)
+	}
+
+	elapsed := l.clock.Since(t.begin)
+	attributes := []slog.Attr{
+		slog.Int64(DiscardedStatementsField, statements),
+		slog.Duration(DurationField, elapsed),
+	}
+	attributes = l.appendSourceAttribute(attributes, source)
+	if lastErr != nil {
+		attributes = append(attributes, slog.Any(l.errorField, lastErr))
+	}
+
+	attributes = l.appendContextAttributes(t.ctx, attributes)
+	attributes = l.scrub(nil, attributes)
+	msg, custom := l.formatMessage(t.ctx, messageEvent{logType: RollbackLogType, elapsed: elapsed, statements: statements, err: lastErr})
+	if !custom {
+		msg = RollbackMessage
+	}
+	level := l.logLevel[RollbackLogType]
+	l.logAttrs(t.ctx, level, msg, l.applyPreset(t.ctx, RollbackLogType, level, nil, attributes)...)
+}
+
+// logLongTransaction warns that the given transaction is open for longer than the threshold
+func (l logger) logLongTransaction(t *trackedTx, threshold time.Duration, source *sourceValuer) {
+	l = l.snapshot()
+	if l.silenced(LongTransactionLogType) {
+		return
+	}
+	t.mu.Lock()
+	statements := t.statements
+	t.mu.Unlock()
+
+	elapsed := l.clock.Since(t.begin)
+	attributes := l.appendSourceAttribute([]slog.Attr{
+		slog.Int64(StatementsField, statements),
+		slog.Duration(DurationField, elapsed),
+	}, source)
+	attributes = l.appendContextAttributes(t.ctx, attributes)
+	attributes = l.scrub(nil, attributes)
+	level := l.logLevel[LongTransactionLogType]
+	attributes = l.applyPreset(t.ctx, LongTransactionLogType, level, nil, attributes)
+
+	event := messageEvent{
+		logType:    LongTransactionLogType,
+		elapsed:    elapsed,
+		threshold:  threshold,
+		statements: statements,
+	}
+	msg, custom := l.formatMessage(t.ctx, event)
+	if !custom {
+		msg = l.defaultMessage(t.ctx, event)
+	}
+	l.logAttrs(t.ctx, level, msg, attributes...)
+}
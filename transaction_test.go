@@ -0,0 +1,387 @@
+package slogGorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+func Test_logger_Initialize(t *testing.T) {
+	_, gormLogger := getReceiverAndLogger(nil)
+	db := openTestDB(t, gormLogger)
+
+	_, ok := db.ConnPool.(*txTrackingPool)
+	assert.True(t, ok)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	assert.NotNil(t, sqlDB)
+
+	tx := db.Begin()
+	require.NoError(t, tx.Error)
+	assert.NotNil(t, trackedTransaction(tx.Statement.ConnPool))
+
+	sqlDB, err = tx.DB()
+	require.NoError(t, err)
+	assert.NotNil(t, sqlDB)
+	require.NoError(t, tx.Commit().Error)
+}
+
+func Test_logger_Rollback(t *testing.T) {
+	t.Run("Rollback", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		db := openTestDB(t, gormLogger)
+
+		tx := db.Begin()
+		require.NoError(t, tx.Exec("UPDATE users SET name = ?", "john").Error)
+		require.NoError(t, tx.Exec("DELETE FROM users").Error)
+		require.NoError(t, tx.Rollback().Error)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "transaction rolled back", receiver.Record.Message)
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assertHasAttr(t, receiver.Record, slog.Int64(DiscardedStatementsField, 2))
+		assertNoAttr(t, receiver.Record, ErrorField)
+	})
+
+	t.Run("Rollback caused by an error", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			SetLogLevel(RollbackLogType, slog.LevelError),
+		})
+		db := openTestDB(t, gormLogger)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("INSERT INTO users (name) VALUES (?)", "john").Error; err != nil {
+				return err
+			}
+			return tx.Exec("FAIL").Error
+		})
+		require.ErrorIs(t, err, errFakeDriver)
+
+		require.NotNil(t, receiver.Record)
+		assert.Equal(t, "transaction rolled back", receiver.Record.Message)
+		assert.Equal(t, slog.LevelError, receiver.Record.Level)
+		assertHasAttr(t, receiver.Record, slog.Int64(DiscardedStatementsField, 2))
+		assertHasAttr(t, receiver.Record, slog.Any(ErrorField, errFakeDriver))
+	})
+
+	t.Run("Record not found", func(t *testing.T) {
+		fc := func() (string, int64) { return "SELECT * FROM users WHERE id = 1", 0 }
+
+		// The records not found are ignored, they are not the cause of the rollback
+		_, gormLogger := getReceiverAndLogger(nil)
+		tx := &trackedTx{}
+		gormLogger.Trace(context.WithValue(context.Background(), txContextKey{}, tx), time.Now(), fc, gorm.ErrRecordNotFound)
+		assert.Equal(t, int64(1), tx.statements)
+		assert.NoError(t, tx.lastErr)
+
+		_, gormLogger = getReceiverAndLogger([]Option{WithRecordNotFoundError()})
+		tx = &trackedTx{}
+		gormLogger.Trace(context.WithValue(context.Background(), txContextKey{}, tx), time.Now(), fc, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, tx.lastErr, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		db := openTestDB(t, gormLogger)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM users").Error
+		})
+		require.NoError(t, err)
+		assert.Nil(t, receiver.Record)
+	})
+
+	t.Run("Rollback but ignoreTrace option is enabled", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithIgnoreTrace(),
+		})
+		db := openTestDB(t, gormLogger)
+
+		tx := db.Begin()
+		require.NoError(t, tx.Rollback().Error)
+		assert.Nil(t, receiver.Record)
+	})
+}
+
+func Test_logger_Savepoint(t *testing.T) {
+	receiver, gormLogger := getReceiverAndLogger(nil)
+	db := openTestDB(t, gormLogger)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_ = tx.Transaction(func(tx *gorm.DB) error {
+			return errors.New("nested error")
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, receiver.Records, 2)
+	assert.Contains(t, receiver.Records[0].Message, "SAVEPOINT executed [sp")
+	assert.Equal(t, slog.LevelInfo, receiver.Records[0].Level)
+	assert.Contains(t, receiver.Records[1].Message, "ROLLBACK TO SAVEPOINT executed [sp")
+	assert.Equal(t, slog.LevelInfo, receiver.Records[1].Level)
+
+	var name string
+	receiver.Records[1].Attrs(func(attr slog.Attr) bool {
+		if attr.Key == SavepointField {
+			name = attr.Value.String()
+			return false
+		}
+		return true
+	})
+	assert.True(t, strings.HasPrefix(name, "sp"))
+}
+
+func Test_parseSavepoint(t *testing.T) {
+	tests := []struct {
+		sql           string
+		wantOk        bool
+		wantOperation string
+		wantName      string
+	}{
+		{sql: "SAVEPOINT sp1", wantOk: true, wantOperation: "SAVEPOINT", wantName: "sp1"},
+		{sql: "savepoint `sp1`;", wantOk: true, wantOperation: "SAVEPOINT", wantName: "sp1"},
+		{sql: "ROLLBACK TO SAVEPOINT sp1", wantOk: true, wantOperation: "ROLLBACK TO SAVEPOINT", wantName: "sp1"},
+		{sql: "ROLLBACK TO sp1", wantOk: true, wantOperation: "ROLLBACK TO SAVEPOINT", wantName: "sp1"},
+		{sql: " RELEASE SAVEPOINT \"sp1\"", wantOk: true, wantOperation: "RELEASE SAVEPOINT", wantName: "sp1"},
+		{sql: "SAVEPOINT", wantOk: false},
+		{sql: "SAVEPOINTS sp1", wantOk: false},
+		{sql: "SELECT * FROM savepoint", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			sp, ok := parseSavepoint(tt.sql)
+
+			require.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.wantOperation, sp.operation)
+				assert.Equal(t, tt.wantName, sp.name)
+			}
+		})
+	}
+}
+
+func Test_logger_TransactionStatements(t *testing.T) {
+	t.Run("With trace all mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+		})
+		db := openTestDB(t, gormLogger)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("UPDATE users SET name = ?", "john").Error; err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM users").Error
+		})
+		require.NoError(t, err)
+
+		require.Len(t, receiver.Records, 3)
+		assertHasAttr(t, &receiver.Records[0], slog.Int64(TxStmtIndexField, 1))
+		assertHasAttr(t, &receiver.Records[1], slog.Int64(TxStmtIndexField, 2))
+		assert.Equal(t, "transaction committed", receiver.Records[2].Message)
+		assert.Equal(t, slog.LevelInfo, receiver.Records[2].Level)
+		assertHasAttr(t, &receiver.Records[2], slog.Int64(StatementsField, 2))
+	})
+
+	t.Run("Outside of a transaction", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTraceAll(),
+		})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Exec("DELETE FROM users").Error)
+
+		require.Len(t, receiver.Records, 1)
+		assertNoAttr(t, receiver.Record, TxStmtIndexField)
+	})
+
+	t.Run("Without trace all mode", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger(nil)
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Begin().Commit().Error)
+		assert.Nil(t, receiver.Record)
+	})
+}
+
+func Test_logger_TransactionWatchdog(t *testing.T) {
+	t.Run("Transaction open for too long", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTransactionWatchdog(10 * time.Millisecond),
+		})
+		db := openTestDB(t, gormLogger)
+
+		tx := db.Begin()
+		require.NoError(t, tx.Exec("DELETE FROM users").Error)
+		require.Eventually(t, func() bool { return receiver.Len() == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, tx.Commit().Error)
+
+		assert.Contains(t, receiver.Record.Message, "transaction open for too long")
+		assert.Equal(t, slog.LevelWarn, receiver.Record.Level)
+		assertHasAttr(t, receiver.Record, slog.Int64(StatementsField, 1))
+	})
+
+	t.Run("Transaction committed in time", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithTransactionWatchdog(20 * time.Millisecond),
+		})
+		db := openTestDB(t, gormLogger)
+
+		require.NoError(t, db.Begin().Commit().Error)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, 0, receiver.Len())
+	})
+}
+
+// private helpers
+
+func openTestDB(t *testing.T, l *logger) *gorm.DB {
+	t.Helper()
+
+	sqlDB, err := sql.Open(fakeDriverName, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(fakeDialector{}, &gorm.Config{
+		ConnPool: sqlDB,
+		Logger:   l,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(l))
+
+	return db
+}
+
+func assertHasAttr(t *testing.T, r *slog.Record, expected slog.Attr) {
+	t.Helper()
+
+	found := false
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Equal(expected) {
+			found = true
+			return false
+		}
+		return true
+	})
+	assert.True(t, found, "expected attribute %v not found", expected)
+}
+
+func assertNoAttr(t *testing.T, r *slog.Record, key string) {
+	t.Helper()
+
+	r.Attrs(func(attr slog.Attr) bool {
+		assert.NotEqual(t, key, attr.Key, "unexpected attribute %v", attr)
+		return true
+	})
+}
+
+// Mock
+
+// fakeDialector adds the support of savepoints to tests.DummyDialector
+type fakeDialector struct {
+	tests.DummyDialector
+}
+
+func (fakeDialector) SavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("SAVEPOINT " + name).Error
+}
+
+func (fakeDialector) RollbackTo(tx *gorm.DB, name string) error {
+	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
+}
+
+const fakeDriverName = "slog-gorm-fake"
+
+// errFakeDriver is returned by the fake driver for the queries starting with FAIL
+var errFakeDriver = errors.New("fake driver error")
+
+func init() {
+	sql.Register(fakeDriverName, fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(_ string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{query: query}, nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	if strings.HasPrefix(s.query, "FAIL") {
+		return nil, errFakeDriver
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	if strings.HasPrefix(s.query, "FAIL") {
+		return nil, errFakeDriver
+	}
+	if strings.HasPrefix(s.query, "EXPLAIN") {
+		return &fakePlanRows{query: s.query}, nil
+	}
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return []string{}
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next(_ []driver.Value) error {
+	return io.EOF
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package slogGorm
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// budgetWindow is the window of the volume budget, see WithVolumeBudget
+const budgetWindow = time.Minute
+
+// The priorities of the log types of the SQL queries under the volume budget, see WithVolumeBudget
+const (
+	// budgetProtected are the records never dropped: the errors, the dangerous writes and the DDL statements
+	budgetProtected = iota
+	// budgetHigh are the slow and large queries, sampled once the records of budgetProtected are logged
+	budgetHigh
+	// budgetLow are the SQL messages traced and the savepoints, sampled first
+	budgetLow
+	budgetPriorities
+)
+
+// budgetPriority returns the priority of the log type under the volume budget
+func budgetPriority(logType LogType) int {
+	switch logType {
+	case SlowQueryLogType, LargeQueryLogType, LargeResultLogType:
+		return budgetHigh
+	case DefaultLogType, SavepointLogType:
+		return budgetLow
+	default:
+		return budgetProtected
+	}
+}
+
+// volumeBudget adapts the sampling rates of the records per priority to log at most limit records per window,
+// shared with the loggers derived from, see WithVolumeBudget
+type volumeBudget struct {
+	mu    sync.Mutex
+	limit int
+	// start is the start of the current window
+	start time.Time
+	// logged is the number of records logged in the current window
+	logged int
+	// offered is the number of records per priority submitted to the budget in the current window
+	offered [budgetPriorities]int
+	// rates are the sampling rates per priority of the current window, computed from the previous one
+	rates [budgetPriorities]float64
+}
+
+// newVolumeBudget returns the volume budget of limit records per window
+func newVolumeBudget(limit int) *volumeBudget {
+	b := &volumeBudget{limit: limit}
+	b.resetRates()
+	return b
+}
+
+// resetRates keeps all the records, until the volume of a window is known
+func (b *volumeBudget) resetRates() {
+	for i := range b.rates {
+		b.rates[i] = 1
+	}
+}
+
+// allow reports whether the record of the log type is logged, with the sampling rate of its priority. The
+// protected records are always logged, the others are sampled with the rate of their priority and dropped
+// once the budget of the window is spent.
+func (b *volumeBudget) allow(now time.Time, logType LogType) (float64, bool) {
+	priority := budgetPriority(logType)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if elapsed := now.Sub(b.start); elapsed >= budgetWindow || elapsed < 0 {
+		if elapsed >= 2*budgetWindow || elapsed < 0 {
+			// The previous window is over for long, its volume does not describe the current traffic
+			b.offered = [budgetPriorities]int{}
+		}
+		b.adapt()
+		b.start, b.logged, b.offered = now, 0, [budgetPriorities]int{}
+	}
+	b.offered[priority]++
+
+	rate := b.rates[priority]
+	if priority != budgetProtected && (b.logged >= b.limit || (rate < 1 && rand.Float64() >= rate)) {
+		return rate, false
+	}
+	b.logged++
+	return rate, true
+}
+
+// adapt computes the sampling rates of the window from the records offered during the previous one, the budget
+// being spent on the priorities in order
+func (b *volumeBudget) adapt() {
+	b.resetRates()
+	remaining := b.limit - b.offered[budgetProtected]
+	for priority := budgetHigh; priority < budgetPriorities; priority++ {
+		offered := b.offered[priority]
+		switch {
+		case offered == 0 || remaining >= offered:
+			remaining -= offered
+			continue
+		case remaining <= 0:
+			b.rates[priority] = 0
+		default:
+			b.rates[priority] = float64(remaining) / float64(offered)
+		}
+		remaining = 0
+	}
+}
@@ -0,0 +1,104 @@
+package slogGorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logger_VolumeBudget(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	fc := func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}
+
+	t.Run("Budget spent", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithTraceAll(),
+			WithVolumeBudget(5),
+		})
+
+		for i := 0; i < 10; i++ {
+			gormLogger.Trace(context.Background(), now, fc, nil)
+		}
+		require.Equal(t, 5, receiver.Len())
+		assertNoAttr(t, receiver.Record, SamplingRateField)
+
+		gormLogger.Trace(context.Background(), now, fc, errors.New("awesome error"))
+		assert.Equal(t, 6, receiver.Len(), "the errors are never dropped")
+		assert.Equal(t, uint64(5), gormLogger.Stats().SampledOut)
+	})
+
+	t.Run("Priorities", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithTraceAll(),
+			WithSlowThreshold(time.Second),
+			WithVolumeBudget(8),
+		})
+		slow := func() { gormLogger.Trace(context.Background(), now.Add(-2*time.Second), fc, nil) }
+		traced := func() { gormLogger.Trace(context.Background(), now, fc, nil) }
+
+		for i := 0; i < 3; i++ {
+			gormLogger.Trace(context.Background(), now, fc, errors.New("awesome error"))
+		}
+		for i := 0; i < 5; i++ {
+			slow()
+		}
+		for i := 0; i < 10; i++ {
+			traced()
+		}
+		require.Equal(t, 8, receiver.Len())
+
+		// The next minute, the budget left by the errors is spent on the slow queries
+		now = now.Add(time.Minute)
+		traced()
+		assert.Equal(t, 8, receiver.Len())
+		slow()
+		require.Equal(t, 9, receiver.Len())
+		assertNoAttr(t, receiver.Record, SamplingRateField)
+
+		// Once the traffic is back to normal, all the records are logged again
+		now = now.Add(time.Minute)
+		traced()
+		assert.Equal(t, 10, receiver.Len())
+	})
+
+	t.Run("Sampling rate", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithTraceAll(),
+			WithVolumeBudget(10),
+		})
+
+		for i := 0; i < 20; i++ {
+			gormLogger.Trace(context.Background(), now, fc, nil)
+		}
+		now = now.Add(time.Minute)
+		for receiver.Len() == 10 {
+			gormLogger.Trace(context.Background(), now, fc, nil)
+		}
+		assertHasAttr(t, receiver.Record, slog.Float64(SamplingRateField, 0.5))
+	})
+
+	t.Run("Decision", func(t *testing.T) {
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithClock(movingClock{now: &now}),
+			WithTraceAll(),
+			WithVolumeBudget(1),
+			WithDecisionDebug(),
+		})
+
+		gormLogger.Trace(context.Background(), now, fc, nil)
+		gormLogger.Trace(context.Background(), now, fc, nil)
+
+		require.Equal(t, 2, receiver.Len())
+		assertHasAttr(t, receiver.Record, slog.String(DecisionField, decisionOverBudget))
+	})
+}
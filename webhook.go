@@ -0,0 +1,208 @@
+package slogGorm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxWebhookFingerprints is the number of fingerprints of slow queries tracked by SlowQueryWebhook
+const maxWebhookFingerprints = 1024
+
+// SlowQueryAlert is the JSON payload posted by SlowQueryWebhook
+type SlowQueryAlert struct {
+	// Fingerprint is the structure of the SQL query without its values, see WithNoValues
+	Fingerprint string `json:"fingerprint"`
+	// SampleSQL is the SQL query of the last occurrence, redacted like the records
+	SampleSQL string `json:"sample_sql"`
+	Operation string `json:"operation,omitempty"`
+	Table     string `json:"table,omitempty"`
+	// Count is the number of slow occurrences within the window
+	Count int `json:"count"`
+	// P95 is the 95th percentile of the durations of the occurrences, in milliseconds
+	P95 float64 `json:"p95_ms"`
+	// FirstSeen and LastSeen are the start times of the first and last occurrences
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// SlowQueryWebhook is a Sink posting a SlowQueryAlert to a URL when the same slow query, identified by its
+// fingerprint, occurs more than a given number of times within a window, as a lightweight alarm of the query
+// regressions. The occurrences of a fingerprint are reset once alerted. It is safe for concurrent use.
+type SlowQueryWebhook struct {
+	url     string
+	count   int
+	window  time.Duration
+	client  *http.Client
+	onError func(err error)
+
+	mu          sync.Mutex
+	occurrences map[string][]slowOccurrence
+}
+
+// slowOccurrence is an occurrence of a slow query tracked by SlowQueryWebhook
+type slowOccurrence struct {
+	time     time.Time
+	duration time.Duration
+}
+
+// SlowQueryWebhookOption is an option of the webhook, see NewSlowQueryWebhook
+type SlowQueryWebhookOption func(w *SlowQueryWebhook)
+
+// WithWebhookClient defines the HTTP client posting the alerts (a client with a 10 seconds timeout by default)
+func WithWebhookClient(client *http.Client) SlowQueryWebhookOption {
+	return func(w *SlowQueryWebhook) {
+		w.client = client
+	}
+}
+
+// WithWebhookErrorHandler calls fn with the errors of the alerts not delivered, e.g. to log them. The
+// errors are ignored by default.
+func WithWebhookErrorHandler(fn func(err error)) SlowQueryWebhookOption {
+	return func(w *SlowQueryWebhook) {
+		w.onError = fn
+	}
+}
+
+// NewSlowQueryWebhook creates the webhook posting an alert to the given HTTP(S) URL when the same slow query
+// occurs more than count times within the window. It returns an error wrapping ErrInvalidOption if the URL,
+// the count, the window or the HTTP client are invalid.
+func NewSlowQueryWebhook(rawURL string, count int, window time.Duration, options ...SlowQueryWebhookOption) (*SlowQueryWebhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: webhook URL: %w", ErrInvalidOption, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("%w: webhook URL %q is not an HTTP URL", ErrInvalidOption, rawURL)
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("%w: webhook count %d is not positive", ErrInvalidOption, count)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: webhook window %s is not positive", ErrInvalidOption, window)
+	}
+
+	w := &SlowQueryWebhook{
+		url:         rawURL,
+		count:       count,
+		window:      window,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		occurrences: make(map[string][]slowOccurrence),
+	}
+	for _, option := range options {
+		option(w)
+	}
+	if w.client == nil {
+		return nil, fmt.Errorf("%w: nil webhook client", ErrInvalidOption)
+	}
+	return w, nil
+}
+
+// HandleQuery implements Sink, tracking the slow queries only. The alerts are posted in the background.
+func (w *SlowQueryWebhook) HandleQuery(_ context.Context, event QueryEvent) {
+	if !event.Slow {
+		return
+	}
+	if alert, ok := w.track(event); ok {
+		go w.post(alert)
+	}
+}
+
+// track records the occurrence of the slow query, and returns the alert if its fingerprint occurred more
+// than the count of the webhook within its window
+func (w *SlowQueryWebhook) track(event QueryEvent) (SlowQueryAlert, bool) {
+	key := fingerprint(event.SQL)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	occurrences, tracked := w.occurrences[key]
+	if !tracked && len(w.occurrences) >= maxWebhookFingerprints {
+		w.prune(event.Time)
+		if len(w.occurrences) >= maxWebhookFingerprints {
+			return SlowQueryAlert{}, false
+		}
+	}
+
+	occurrences = append(dropExpired(occurrences, event.Time.Add(-w.window)), slowOccurrence{time: event.Time, duration: event.Duration})
+	if len(occurrences) <= w.count {
+		w.occurrences[key] = occurrences
+		return SlowQueryAlert{}, false
+	}
+	delete(w.occurrences, key)
+
+	durations := make([]time.Duration, len(occurrences))
+	for i, o := range occurrences {
+		durations[i] = o.duration
+	}
+	return SlowQueryAlert{
+		Fingerprint: key,
+		SampleSQL:   event.SQL,
+		Operation:   event.Operation,
+		Table:       event.Table,
+		Count:       len(occurrences),
+		P95:         float64(percentile(durations, 0.95)) / float64(time.Millisecond),
+		FirstSeen:   occurrences[0].time,
+		LastSeen:    event.Time,
+	}, true
+}
+
+// prune removes the fingerprints without occurrence within the window, with the lock held
+func (w *SlowQueryWebhook) prune(now time.Time) {
+	for key, occurrences := range w.occurrences {
+		if occurrences = dropExpired(occurrences, now.Add(-w.window)); len(occurrences) == 0 {
+			delete(w.occurrences, key)
+		} else {
+			w.occurrences[key] = occurrences
+		}
+	}
+}
+
+// dropExpired removes the occurrences started before the given time, the occurrences being sorted by time
+func dropExpired(occurrences []slowOccurrence, before time.Time) []slowOccurrence {
+	i := 0
+	for i < len(occurrences) && occurrences[i].time.Before(before) {
+		i++
+	}
+	return occurrences[i:]
+}
+
+// percentile returns the percentile p (between 0 and 1) of the durations, with the nearest-rank method.
+// The durations are sorted in place.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	slices.Sort(durations)
+	rank := int(math.Ceil(p*float64(len(durations)))) - 1
+	return durations[max(0, min(rank, len(durations)-1))]
+}
+
+// post posts the alert to the URL of the webhook
+func (w *SlowQueryWebhook) post(alert SlowQueryAlert) {
+	err := func() error {
+		payload, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+		resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("slog-gorm: webhook %s: unexpected status %s", w.url, resp.Status)
+		}
+		return nil
+	}()
+	if err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
@@ -0,0 +1,143 @@
+package slogGorm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowQueryWebhook(t *testing.T) {
+	slow := func(begin time.Time, sql string, duration time.Duration) QueryEvent {
+		return QueryEvent{Time: begin, SQL: sql, Operation: "SELECT", Table: "users", Duration: duration, Slow: true}
+	}
+
+	t.Run("Repeated slow fingerprint", func(t *testing.T) {
+		alerts := make(chan SlowQueryAlert, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var alert SlowQueryAlert
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+			alerts <- alert
+		}))
+		defer server.Close()
+
+		webhook, err := NewSlowQueryWebhook(server.URL, 2, time.Minute)
+		require.NoError(t, err)
+		receiver, gormLogger := getReceiverAndLogger([]Option{
+			WithIgnoreTrace(),
+			WithSlowThreshold(10 * time.Millisecond),
+			WithSink(webhook),
+		})
+
+		begin := time.Now().Add(-time.Second)
+		for _, id := range []string{"1", "2", "3"} {
+			gormLogger.Trace(context.Background(), begin, func() (string, int64) {
+				return "SELECT * FROM users WHERE id = " + id, 1
+			}, nil)
+		}
+
+		assert.Nil(t, receiver.Record)
+		select {
+		case alert := <-alerts:
+			assert.Equal(t, "SELECT * FROM users WHERE id = ?", alert.Fingerprint)
+			assert.Equal(t, "SELECT * FROM users WHERE id = 3", alert.SampleSQL)
+			assert.Equal(t, "SELECT", alert.Operation)
+			assert.Equal(t, "users", alert.Table)
+			assert.Equal(t, 3, alert.Count)
+			assert.GreaterOrEqual(t, alert.P95, 1000.0)
+			assert.True(t, alert.FirstSeen.Equal(begin))
+		case <-time.After(5 * time.Second):
+			t.Fatal("no alert posted")
+		}
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		webhook, err := NewSlowQueryWebhook("http://localhost", 2, time.Minute)
+		require.NoError(t, err)
+		begin := time.Now()
+
+		_, alerted := webhook.track(slow(begin, "SELECT 1", time.Second))
+		assert.False(t, alerted)
+		_, alerted = webhook.track(slow(begin.Add(time.Second), "SELECT 2", time.Second))
+		assert.False(t, alerted)
+		// The first occurrence is out of the window
+		_, alerted = webhook.track(slow(begin.Add(time.Minute+time.Second), "SELECT 3", time.Second))
+		assert.False(t, alerted)
+		alert, alerted := webhook.track(slow(begin.Add(time.Minute+time.Second), "SELECT 4", 3*time.Second))
+		require.True(t, alerted)
+		assert.Equal(t, 3, alert.Count)
+		assert.Equal(t, 3000.0, alert.P95)
+
+		// The occurrences are reset once alerted
+		_, alerted = webhook.track(slow(begin.Add(time.Minute+time.Second), "SELECT 5", time.Second))
+		assert.False(t, alerted)
+	})
+
+	t.Run("Fast query", func(t *testing.T) {
+		webhook, err := NewSlowQueryWebhook("http://localhost", 1, time.Minute)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			webhook.HandleQuery(context.Background(), QueryEvent{Time: time.Now(), SQL: "SELECT 1"})
+		}
+
+		assert.Empty(t, webhook.occurrences)
+	})
+
+	t.Run("Delivery error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		errs := make(chan error, 1)
+		webhook, err := NewSlowQueryWebhook(server.URL, 1, time.Minute,
+			WithWebhookClient(server.Client()),
+			WithWebhookErrorHandler(func(err error) { errs <- err }),
+		)
+		require.NoError(t, err)
+
+		webhook.post(SlowQueryAlert{Fingerprint: "SELECT ?"})
+
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, <-errs, "500 Internal Server Error")
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		for _, tt := range []struct {
+			url    string
+			count  int
+			window time.Duration
+		}{
+			{url: "localhost:8080", count: 1, window: time.Minute},
+			{url: "ftp://localhost", count: 1, window: time.Minute},
+			{url: "http://localhost", count: 0, window: time.Minute},
+			{url: "http://localhost", count: 1, window: 0},
+		} {
+			_, err := NewSlowQueryWebhook(tt.url, tt.count, tt.window)
+			assert.ErrorIs(t, err, ErrInvalidOption, tt)
+		}
+
+		_, err := NewSlowQueryWebhook("http://localhost", 1, time.Minute, WithWebhookClient(nil))
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+}
+
+func Test_percentile(t *testing.T) {
+	durations := make([]time.Duration, 0, 20)
+	for i := 20; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 19*time.Millisecond, percentile(durations, 0.95))
+	assert.Equal(t, 10*time.Millisecond, percentile(durations, 0.5))
+	assert.Equal(t, time.Millisecond, percentile(durations, 0))
+	assert.Equal(t, time.Duration(0), percentile(nil, 0.95))
+}